@@ -0,0 +1,110 @@
+package weightedrand
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// adjustableTable is an alias table over a fixed list of items whose
+// weights may change between selections. Items with a weight of zero are
+// excluded. The alias table is rebuilt lazily, on the first selection after
+// a change, so that several changes only cost a single rebuild.
+//
+// adjustableTable is not safe for concurrent use; the choosers embedding it
+// are responsible for synchronization.
+type adjustableTable[TItem any] struct {
+	random  RandIntN
+	base    []weightedItem[TItem]
+	current []decimal.Decimal
+	dirty   bool
+	table   *voseAliasMethodRandom[int]
+}
+
+func newAdjustableTable[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight]) *adjustableTable[TItem] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	}
+	table := &adjustableTable[TItem]{
+		random:  random,
+		base:    make([]weightedItem[TItem], 0, len(items)),
+		current: make([]decimal.Decimal, 0, len(items)),
+		dirty:   true,
+	}
+	for _, item := range items {
+		weight := effectiveWeight(item.Weight)
+		table.base = append(table.base, weightedItem[TItem]{
+			Item:   item.Item,
+			Weight: weight,
+		})
+		table.current = append(table.current, weight)
+	}
+	return table
+}
+
+func (table *adjustableTable[TItem]) len() int {
+	return len(table.base)
+}
+
+func (table *adjustableTable[TItem]) item(index int) TItem {
+	return table.base[index].Item
+}
+
+// baseWeight is the weight the item was constructed with.
+func (table *adjustableTable[TItem]) baseWeight(index int) decimal.Decimal {
+	return table.base[index].Weight
+}
+
+// weight is the weight currently used for the item.
+func (table *adjustableTable[TItem]) weight(index int) decimal.Decimal {
+	return table.current[index]
+}
+
+func (table *adjustableTable[TItem]) setWeight(index int, weight decimal.Decimal) {
+	if table.current[index].Equal(weight) {
+		return
+	}
+	table.current[index] = weight
+	table.dirty = true
+}
+
+func (table *adjustableTable[TItem]) exclude(index int) {
+	table.setWeight(index, decimal.Zero)
+}
+
+func (table *adjustableTable[TItem]) restore(index int) {
+	table.setWeight(index, table.base[index].Weight)
+}
+
+func (table *adjustableTable[TItem]) excluded(index int) bool {
+	return table.current[index].IsZero()
+}
+
+// next selects the index of an item by its current weight. If every item is
+// excluded, it returns false.
+func (table *adjustableTable[TItem]) next() (int, bool) {
+	if table.dirty {
+		table.rebuild()
+	}
+	if table.table == nil {
+		return 0, false
+	}
+	return table.table.Next(), true
+}
+
+func (table *adjustableTable[TItem]) rebuild() {
+	table.dirty = false
+	indices := make([]weightedItem[int], 0, len(table.current))
+	for index, weight := range table.current {
+		if weight.IsPositive() {
+			indices = append(indices, weightedItem[int]{
+				Item:   index,
+				Weight: weight,
+			})
+		}
+	}
+	if len(indices) == 0 {
+		table.table = nil
+		return
+	}
+	aliasMethod := newVoseAliasMethodFromDecimals(table.random, indices)
+	table.table = &aliasMethod
+}
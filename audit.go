@@ -0,0 +1,114 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AuditRecord describes a single draw of an Audited chooser. Together with
+// the items the chooser was constructed with, the seed and counter are
+// sufficient to reproduce the draw, as
+// NewCounterBasedWithOptions(record.Seed, items, WithExactThresholds()).NthSelection(record.Counter).
+// Probability is the exact probability the item was drawn with.
+type AuditRecord[TItem any] struct {
+	Time        time.Time
+	Item        TItem
+	Probability decimal.Decimal
+	Seed        string
+	Counter     uint64
+}
+
+// Audited is a WeightedRandom that records every draw in a ring buffer, so
+// that the fairness of each draw can be demonstrated after the fact, as
+// required of promotions and lotteries in regulated environments. Draws are
+// made by a CounterBased chooser with exact thresholds, which makes every
+// recorded draw reproducible by a third party, and draws every item with
+// exactly the probability recorded, however tiny its weight.
+//
+// Audited is safe for concurrent use.
+type Audited[TItem comparable] struct {
+	mutex         sync.Mutex
+	seed          string
+	clock         Clock
+	counterBased  *CounterBased[TItem]
+	probabilities map[TItem]decimal.Decimal
+	records       []AuditRecord[TItem]
+	start         int
+}
+
+// NewAudited constructs a new Audited instance, which retains the records
+// of the most recent draws up to the given capacity.
+//
+// The function panics if no items are provided, weights are negative, the
+// capacity is not positive, or the weights span too many orders of
+// magnitude to be scaled into exact thresholds, as by WithExactThresholds.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be selected.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - seed:     The seed the draws are derived from.
+//   - clock:    A Clock implementation used to timestamp the draws.
+//   - capacity: The number of records to retain.
+//   - items:    A variadic list of WeightedItem values, each containing an item and its weight.
+//
+// Example usage:
+//
+//	raffle := NewAudited("spring-raffle-2025", SystemClock, 10_000,
+//		WeightedItem[string, int]{Item: "alice", Weight: 3},
+//		WeightedItem[string, int]{Item: "bob", Weight: 1},
+//	)
+//	winner := raffle.Next()
+//	records := raffle.ExportAudit()
+func NewAudited[TItem comparable, TWeight Weight](seed string, clock Clock, capacity int, items ...WeightedItem[TItem, TWeight]) *Audited[TItem] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("capacity must be positive value, but was %d", capacity))
+	}
+	audited := &Audited[TItem]{
+		seed:          seed,
+		clock:         clock,
+		counterBased:  NewCounterBasedWithOptions(seed, items, WithExactThresholds()),
+		probabilities: make(map[TItem]decimal.Decimal, len(items)),
+		records:       make([]AuditRecord[TItem], 0, capacity),
+	}
+	for _, item := range normalizedItems(items) {
+		audited.probabilities[item.Item] = audited.probabilities[item.Item].Add(item.Weight)
+	}
+	return audited
+}
+
+// Next selects an item by weight, and records the draw.
+func (audited *Audited[TItem]) Next() TItem {
+	audited.mutex.Lock()
+	defer audited.mutex.Unlock()
+	counter := audited.counterBased.Counter()
+	item := audited.counterBased.Next()
+	record := AuditRecord[TItem]{
+		Time:        audited.clock.Now(),
+		Item:        item,
+		Probability: audited.probabilities[item],
+		Seed:        audited.seed,
+		Counter:     counter,
+	}
+	if len(audited.records) < cap(audited.records) {
+		audited.records = append(audited.records, record)
+	} else {
+		audited.records[audited.start] = record
+		audited.start = (audited.start + 1) % len(audited.records)
+	}
+	return item
+}
+
+// ExportAudit returns a copy of the retained records, from the oldest to
+// the most recent draw.
+func (audited *Audited[TItem]) ExportAudit() []AuditRecord[TItem] {
+	audited.mutex.Lock()
+	defer audited.mutex.Unlock()
+	records := make([]AuditRecord[TItem], 0, len(audited.records))
+	records = append(records, audited.records[audited.start:]...)
+	return append(records, audited.records[:audited.start]...)
+}
@@ -0,0 +1,74 @@
+package weightedrand_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudited(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 1},
+		{Item: Blue, Weight: 2},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewAudited("raffle", NewFixtureClock(), 0, items...)
+		})
+	})
+	t.Run("records are reproducible", func(t *testing.T) {
+		clock := NewFixtureClock()
+		audited := NewAudited("raffle", clock, 10, items...)
+		selected := make([]MarbleColor, 0, 5)
+		for range 5 {
+			selected = append(selected, audited.Next())
+			clock.Advance(time.Second)
+		}
+		records := audited.ExportAudit()
+		assert.Len(t, records, 5)
+		replay := NewCounterBasedWithOptions("raffle", items, WithExactThresholds())
+		for index, record := range records {
+			assert.Equal(t, selected[index], record.Item)
+			assert.Equal(t, uint64(index), record.Counter)
+			assert.Equal(t, "raffle", record.Seed)
+			assert.Equal(t, NewFixtureClock().Now().Add(time.Duration(index)*time.Second), record.Time)
+			assert.Equal(t, replay.NthSelection(record.Counter), record.Item)
+			if record.Item == Blue {
+				assert.Equal(t, "0.5", record.Probability.String())
+			} else {
+				assert.Equal(t, "0.25", record.Probability.String())
+			}
+		}
+	})
+	t.Run("ring buffer retains the most recent draws", func(t *testing.T) {
+		audited := NewAudited("raffle", NewFixtureClock(), 3, items...)
+		for range 7 {
+			audited.Next()
+		}
+		records := audited.ExportAudit()
+		assert.Len(t, records, 3)
+		for index, record := range records {
+			assert.Equal(t, uint64(4+index), record.Counter)
+		}
+	})
+	t.Run("recorded probabilities are drawn", func(t *testing.T) {
+		const draws = 200_000
+		audited := NewAudited("raffle", NewFixtureClock(), 1,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 999},
+		)
+		red := 0
+		for range draws {
+			if audited.Next() == Red {
+				red++
+				assert.Equal(t, "0.001", audited.ExportAudit()[0].Probability.String())
+			}
+		}
+		// A coin toss of 1/100 resolution would draw red about 0.005 of
+		// the time.
+		assert.InDelta(t, 0.001, float64(red)/draws, 0.0003)
+	})
+}
@@ -0,0 +1,94 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// Backlog is a WeightedRandom whose weights are supplied by a callback at
+// every selection, such as the current depth of each queue, for consumers
+// that should drain queues in proportion to their backlog. No table is built:
+// every selection evaluates the weight of every item once, and selects among
+// them in a single pass with weighted reservoir sampling, so it costs O(n)
+// rather than O(1), but always reflects the current weights.
+//
+// Unlike the constructors, an item with a weight of zero is not assumed to
+// have a weight of 1, but is never selected, as an empty queue has nothing
+// to consume.
+//
+// Backlog is safe for concurrent use, given that the random number generator
+// and the callback are.
+type Backlog[TItem any, TWeight Weight] struct {
+	random   RandIntN
+	items    []TItem
+	weightOf func(TItem) TWeight
+}
+
+// NewBacklog constructs a new Backlog instance over the items, whose weights
+// are reported by weightOf at every selection.
+//
+// The function panics if no items are provided.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random:   A RandIntN implementation used for random number generation.
+//   - weightOf: A function returning the current weight of an item.
+//   - items:    A variadic list of the items to be sampled.
+//
+// Example usage:
+//
+//	backlog := NewBacklog(randSource, func(queue *Queue) int { return queue.Len() }, queues...)
+//	for queue, ok := backlog.TryNext(); ok; queue, ok = backlog.TryNext() {
+//		consume(queue.Pop())
+//	}
+func NewBacklog[TItem any, TWeight Weight](random RandIntN, weightOf func(TItem) TWeight, items ...TItem) *Backlog[TItem, TWeight] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	}
+	return &Backlog[TItem, TWeight]{
+		random:   random,
+		items:    slices.Clone(items),
+		weightOf: weightOf,
+	}
+}
+
+// Next selects an item in proportion to its current weight.
+//
+// Panics:
+//   - If every weight is zero, or any weight is negative.
+func (backlog *Backlog[TItem, TWeight]) Next() TItem {
+	item, ok := backlog.TryNext()
+	if !ok {
+		panic("every item has a weight of zero")
+	}
+	return item
+}
+
+// TryNext selects an item in proportion to its current weight, and reports
+// false if every weight is zero.
+//
+// Panics:
+//   - If any weight is negative.
+func (backlog *Backlog[TItem, TWeight]) TryNext() (TItem, bool) {
+	var selected TItem
+	totalWeight := decimal.Zero
+	for _, item := range backlog.items {
+		weight := WeightAsDecimal(backlog.weightOf(item))
+		if weight.IsNegative() {
+			panic(fmt.Sprintf("weight of %v must be non-negative value, but was %s", item, weight.String()))
+		} else if weight.IsZero() {
+			continue
+		}
+		totalWeight = totalWeight.Add(weight)
+		// Replace the selection with a probability of weight/totalWeight.
+		if uniformDecimal(backlog.random).Mul(totalWeight).LessThan(weight) {
+			selected = item
+		}
+	}
+	return selected, totalWeight.IsPositive()
+}
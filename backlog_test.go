@@ -0,0 +1,44 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBacklog(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewBacklog[MarbleColor, int](nil, nil)
+		})
+		r := rand.New(rand.NewSource(1))
+		backlog := NewBacklog(r, func(MarbleColor) int { return -1 }, Red)
+		assert.Panics(t, func() {
+			backlog.Next()
+		})
+	})
+	t.Run("drains in proportion to backlog", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		depths := map[MarbleColor]int{Red: 1_000, Green: 0, Blue: 3_000}
+		backlog := NewBacklog(r, func(color MarbleColor) int { return depths[color] }, Red, Green, Blue)
+		counts := make(MarbleColorCounts)
+		for range 400 {
+			color := backlog.Next()
+			counts[color] += 1
+			depths[color] -= 1
+		}
+		assert.Zero(t, counts[Green])
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/400, 0.1, "%s", counts)
+		// Draining empties every queue, after which nothing is selected.
+		for color, ok := backlog.TryNext(); ok; color, ok = backlog.TryNext() {
+			depths[color] -= 1
+		}
+		assert.Equal(t, map[MarbleColor]int{Red: 0, Green: 0, Blue: 0}, depths)
+		assert.Panics(t, func() {
+			backlog.Next()
+		})
+	})
+}
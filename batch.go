@@ -0,0 +1,140 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// AppendNextN appends n selections from the WeightedRandom to dst, and
+// returns the extended slice. Reusing dst across calls avoids allocating a
+// new slice for every batch. For large batches, choosers constructed by
+// NewAliasVoseMethod resolve the selections in bulk, which is significantly
+// faster than calling Next, while selecting the same items.
+//
+// Panics:
+//   - If n is negative.
+func AppendNextN[TItem any](dst []TItem, wr WeightedRandom[TItem], n int) []TItem {
+	// The background context is never done, so there is never an error.
+	dst, _ = AppendNextNContext(context.Background(), dst, wr, n)
+	return dst
+}
+
+// AppendNextNContext is like AppendNextN, but stops early when the context
+// is done. The selections made until then are appended to dst, and returned
+// with the error of the context.
+//
+// Panics:
+//   - If n is negative.
+//
+// Example usage:
+//
+//	batch, err := AppendNextNContext(ctx, batch[:0], wr, 50_000_000)
+//	if err != nil {
+//		log.Printf("stopped after %d selections: %v", len(batch), err)
+//	}
+func AppendNextNContext[TItem any](ctx context.Context, dst []TItem, wr WeightedRandom[TItem], n int) ([]TItem, error) {
+	if n < 0 {
+		panic(fmt.Sprintf("n must be non-negative value, but was %d", n))
+	}
+	dst = growSlice(dst, n)
+	if bulk, ok := wr.(bulkSampler[TItem]); ok && n >= bulkThreshold {
+		return bulk.appendNextN(ctx, dst, n)
+	}
+	for i := range n {
+		if i%cancelInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return dst, err
+			}
+		}
+		dst = append(dst, wr.Next())
+	}
+	return dst, nil
+}
+
+// NextN returns n selections from the WeightedRandom in a new slice.
+//
+// Panics:
+//   - If n is negative.
+func NextN[TItem any](wr WeightedRandom[TItem], n int) []TItem {
+	return AppendNextN(nil, wr, n)
+}
+
+// NextNContext is like NextN, but stops early when the context is done, and
+// returns the selections made until then with the error of the context.
+//
+// Panics:
+//   - If n is negative.
+func NextNContext[TItem any](ctx context.Context, wr WeightedRandom[TItem], n int) ([]TItem, error) {
+	return AppendNextNContext(ctx, nil, wr, n)
+}
+
+// Counts performs n selections from the WeightedRandom, and returns how many
+// times each item was selected.
+//
+// Panics:
+//   - If n is negative.
+func Counts[TItem comparable](wr WeightedRandom[TItem], n int) map[TItem]int {
+	counts, _ := CountsContext(context.Background(), wr, n)
+	return counts
+}
+
+// CountsContext is like Counts, but stops early when the context is done,
+// and returns the counts of the selections made until then with the error
+// of the context.
+//
+// Panics:
+//   - If n is negative.
+func CountsContext[TItem comparable](ctx context.Context, wr WeightedRandom[TItem], n int) (map[TItem]int, error) {
+	if n < 0 {
+		panic(fmt.Sprintf("n must be non-negative value, but was %d", n))
+	}
+	counts := make(map[TItem]int)
+	for i := range n {
+		if i%cancelInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return counts, err
+			}
+		}
+		counts[wr.Next()] += 1
+	}
+	return counts, nil
+}
+
+// BatchPool provides reusable buffers for batches of selections, for hot
+// loops where even AppendNextN's occasional growth is too costly. The zero
+// value is ready to use, and it is safe for concurrent use.
+type BatchPool[TItem any] struct {
+	pool sync.Pool
+}
+
+// NextN returns n selections from the WeightedRandom in a buffer from the
+// pool. The buffer should be returned with Put once it is no longer used.
+//
+// Panics:
+//   - If n is negative.
+func (batchPool *BatchPool[TItem]) NextN(wr WeightedRandom[TItem], n int) []TItem {
+	var dst []TItem
+	if buffer, ok := batchPool.pool.Get().(*[]TItem); ok {
+		dst = (*buffer)[:0]
+	}
+	return AppendNextN(dst, wr, n)
+}
+
+// Put returns the buffer to the pool, so that it may be reused by a later
+// call to NextN. The buffer must not be used after it is returned.
+func (batchPool *BatchPool[TItem]) Put(batch []TItem) {
+	// Clear the buffer so the pool does not keep the selected items alive.
+	clear(batch)
+	batch = batch[:0]
+	batchPool.pool.Put(&batch)
+}
+
+func growSlice[TItem any](dst []TItem, n int) []TItem {
+	if cap(dst)-len(dst) >= n {
+		return dst
+	}
+	grown := make([]TItem, len(dst), len(dst)+n)
+	copy(grown, dst)
+	return grown
+}
@@ -0,0 +1,142 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkBatch(b *testing.B) {
+	wr := NewAliasVoseMethod(rand.New(rand.NewSource(1)),
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+	)
+	b.Run("NextN", func(b *testing.B) {
+		for b.Loop() {
+			_ = NextN(wr, 1_000)
+		}
+	})
+	b.Run("AppendNextN", func(b *testing.B) {
+		var buffer []MarbleColor
+		for b.Loop() {
+			buffer = AppendNextN(buffer[:0], wr, 1_000)
+		}
+	})
+	b.Run("Next", func(b *testing.B) {
+		for b.Loop() {
+			for range 1_000 {
+				_ = wr.Next()
+			}
+		}
+	})
+	b.Run("BatchPool", func(b *testing.B) {
+		var pool BatchPool[MarbleColor]
+		for b.Loop() {
+			pool.Put(pool.NextN(wr, 1_000))
+		}
+	})
+}
+
+// cancellingRand cancels the context once Intn has been called the given
+// number of times.
+type cancellingRand struct {
+	*rand.Rand
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (random *cancellingRand) Intn(n int) int {
+	if random.calls--; random.calls == 0 {
+		random.cancel()
+	}
+	return random.Rand.Intn(n)
+}
+
+func TestBatch(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 3},
+	}
+	t.Run("panic", func(t *testing.T) {
+		wr := NewAliasVoseMethod(nil, items...)
+		assert.Panics(t, func() {
+			NextN(wr, -1)
+		})
+		assert.Panics(t, func() {
+			Counts(wr, -1)
+		})
+	})
+	t.Run("matches sequential selections", func(t *testing.T) {
+		expected := NewAliasVoseMethod(rand.New(rand.NewSource(7)), items...)
+		actual := NewAliasVoseMethod(rand.New(rand.NewSource(7)), items...)
+		prefix := []MarbleColor{Green}
+		batch := AppendNextN(prefix, actual, 100)
+		assert.Len(t, batch, 101)
+		assert.Equal(t, Green, batch[0])
+		for _, color := range batch[1:] {
+			assert.Equal(t, expected.Next(), color)
+		}
+	})
+	t.Run("bulk matches sequential selections", func(t *testing.T) {
+		manyItems := []WeightedItem[int, int]{
+			{Item: 1, Weight: 1},
+			{Item: 2, Weight: 2},
+			{Item: 3, Weight: 7},
+			{Item: 4, Weight: 0},
+			{Item: 5, Weight: 33},
+		}
+		expected := NewAliasVoseMethod(rand.New(rand.NewSource(7)), manyItems...)
+		actual := NewAliasVoseMethod(rand.New(rand.NewSource(7)), manyItems...)
+		for _, n := range []int{64, 1_000, 5_000} {
+			for _, item := range NextN(actual, n) {
+				assert.Equal(t, expected.Next(), item)
+			}
+		}
+	})
+	t.Run("counts", func(t *testing.T) {
+		wr := NewAliasVoseMethod(rand.New(rand.NewSource(7)), items...)
+		counts := Counts(wr, 1_000)
+		assert.Equal(t, 1_000, counts[Red]+counts[Blue])
+	})
+	t.Run("pool", func(t *testing.T) {
+		var pool BatchPool[MarbleColor]
+		wr := NewAliasVoseMethod(rand.New(rand.NewSource(7)), items...)
+		batch := pool.NextN(wr, 10)
+		assert.Len(t, batch, 10)
+		pool.Put(batch)
+		assert.Len(t, pool.NextN(wr, 5), 5)
+	})
+	t.Run("context", func(t *testing.T) {
+		const n = 100_000
+		for name, wr := range map[string]func(RandIntN) WeightedRandom[MarbleColor]{
+			"bulk": func(random RandIntN) WeightedRandom[MarbleColor] {
+				return NewAliasVoseMethod(random, items...)
+			},
+			"sequential": func(random RandIntN) WeightedRandom[MarbleColor] {
+				return NewAliasVoseMethodWithOptions(random, items, WithIntegerEngine())
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				batch, err := NextNContext(context.Background(), wr(rand.New(rand.NewSource(7))), n)
+				assert.NoError(t, err)
+				assert.Len(t, batch, n)
+
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				random := &cancellingRand{Rand: rand.New(rand.NewSource(7)), calls: 10_000, cancel: cancel}
+				partial, err := NextNContext(ctx, wr(random), n)
+				assert.ErrorIs(t, err, context.Canceled)
+				assert.NotEmpty(t, partial)
+				assert.Less(t, len(partial), n)
+				assert.Equal(t, batch[:len(partial)], partial)
+
+				counts, err := CountsContext(ctx, wr(rand.New(rand.NewSource(7))), n)
+				assert.ErrorIs(t, err, context.Canceled)
+				assert.Empty(t, counts)
+			})
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package weightedrand
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// bitmapRankInterval is the number of words between the cumulative counts
+// kept for every block, trading selection speed for memory.
+const bitmapRankInterval = 8
+
+// BitmapBlock is a block of a bitset, such as a container of a roaring
+// bitmap, with an associated weight. Bit j of word i represents the
+// identifier Offset + 64*i + j. Once the block has been selected by weight,
+// each of its set bits is equally likely.
+type BitmapBlock[TWeight Weight] struct {
+	Offset uint64
+	Bits   []uint64
+	Weight TWeight
+}
+
+type bitmapBlock struct {
+	offset uint64
+	bits   []uint64
+	// ranks holds the number of set bits before every bitmapRankInterval-th
+	// word.
+	ranks []uint64
+	count int64
+}
+
+type bitmapRandom struct {
+	random RandIntN
+	blocks WeightedRandom[*bitmapBlock]
+}
+
+// NewBitmapSampler constructs a new WeightedRandom instance that selects a
+// block by weight, and then one of its set bits uniformly, returning the
+// identifier it represents. This allows sampling from huge sparse sets of
+// identifiers while only storing their bits. The bits are not copied, and
+// must not be modified while the sampler is in use.
+//
+// Blocks without any set bits are never selected. Other weights follow the
+// same rules as NewAliasVoseMethod: if no weight is provided, it is assumed
+// to be 1.
+//
+// The function panics if no block has any set bits, or if weights are
+// negative.
+//
+// Type Parameters:
+//   - TWeight: The type representing the weight of each block.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - blocks: A variadic list of BitmapBlock values.
+//
+// Example usage:
+//
+//	wr := NewBitmapSampler(randSource,
+//		BitmapBlock[int]{Offset: 0, Bits: activeUsers, Weight: 3},
+//		BitmapBlock[int]{Offset: 1 << 32, Bits: dormantUsers, Weight: 1},
+//	)
+//	userID := wr.Next()
+func NewBitmapSampler[TWeight Weight](random RandIntN, blocks ...BitmapBlock[TWeight]) WeightedRandom[uint64] {
+	items := make([]weightedItem[*bitmapBlock], 0, len(blocks))
+	for _, block := range blocks {
+		weight := effectiveWeight(block.Weight)
+		compiled := &bitmapBlock{
+			offset: block.Offset,
+			bits:   block.Bits,
+			ranks:  make([]uint64, 0, (len(block.Bits)+bitmapRankInterval-1)/bitmapRankInterval),
+		}
+		for i, word := range block.Bits {
+			if i%bitmapRankInterval == 0 {
+				compiled.ranks = append(compiled.ranks, uint64(compiled.count))
+			}
+			compiled.count += int64(bits.OnesCount64(word))
+		}
+		if compiled.count == 0 {
+			continue
+		}
+		items = append(items, weightedItem[*bitmapBlock]{
+			Item:   compiled,
+			Weight: weight,
+		})
+	}
+	if len(items) == 0 {
+		panic("at least one block with a set bit must be provided")
+	}
+	return bitmapRandom{
+		random: random,
+		blocks: newVoseAliasMethodFromDecimals(random, items),
+	}
+}
+
+func (sampler bitmapRandom) Next() uint64 {
+	block := sampler.blocks.Next()
+	return block.offset + block.selectBit(sampler.random.Int63n(block.count))
+}
+
+// selectBit returns the position of the n-th set bit (starting at zero).
+func (block *bitmapBlock) selectBit(n int64) uint64 {
+	// Find the last rank at or before the set bit, then count through the
+	// words following it.
+	interval := sort.Search(len(block.ranks), func(i int) bool {
+		return int64(block.ranks[i]) > n
+	}) - 1
+	remaining := n - int64(block.ranks[interval])
+	for i := interval * bitmapRankInterval; ; i++ {
+		word := block.bits[i]
+		if count := int64(bits.OnesCount64(word)); remaining >= count {
+			remaining -= count
+			continue
+		}
+		for range remaining {
+			// Clear the lowest set bit.
+			word &= word - 1
+		}
+		return uint64(i)*64 + uint64(bits.TrailingZeros64(word))
+	}
+}
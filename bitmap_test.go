@@ -0,0 +1,50 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitmapSampler(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewBitmapSampler[int](nil)
+		})
+		assert.Panics(t, func() {
+			NewBitmapSampler(nil, BitmapBlock[int]{Bits: make([]uint64, 10)})
+		})
+	})
+	t.Run("set bits of weighted blocks", func(t *testing.T) {
+		sparse := make([]uint64, 100)
+		sparse[0] = 1 << 3
+		sparse[17] = 1<<0 | 1<<63
+		sparse[99] = 1 << 42
+		expected := map[uint64]bool{
+			1_000 + 3:          true,
+			1_000 + 17*64:      true,
+			1_000 + 17*64 + 63: true,
+			1_000 + 99*64 + 42: true,
+			1 << 40:            true,
+		}
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewBitmapSampler(r,
+			BitmapBlock[int]{Offset: 1_000, Bits: sparse, Weight: 1},
+			BitmapBlock[int]{Offset: 1 << 40, Bits: []uint64{1}, Weight: 1},
+			BitmapBlock[int]{Offset: 1 << 50, Bits: []uint64{0}, Weight: 100},
+		)
+		const iterations = 100_000
+		counts := make(map[uint64]int)
+		for range iterations {
+			id := wr.Next()
+			assert.Truef(t, expected[id], "%d is not a set bit", id)
+			counts[id]++
+		}
+		assert.InDeltaf(t, 0.5, float64(counts[1<<40])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.125, float64(counts[1_000+17*64+63])/iterations, tolerance, "%v", counts)
+		assert.Len(t, counts, len(expected))
+	})
+}
@@ -0,0 +1,209 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// boundsPrecision is the number of decimal places the scale factor of
+// boundedItems is computed with.
+const boundsPrecision = 24
+
+// WithMinProbability ensures that no item is selected with a probability
+// below p, such as 1%. The probabilities of the other items are reduced in
+// proportion to their weights to make up for it.
+//
+// Panics:
+//   - If p is not within [0, 1].
+func WithMinProbability(p decimal.Decimal) Option {
+	validateProbability(p)
+	return func(o *options) {
+		o.bounded = true
+		o.minProbability = p
+	}
+}
+
+// WithMaxProbability ensures that no item is selected with a probability
+// above p, such as 90%. The probabilities of the other items are increased
+// in proportion to their weights to make up for it.
+//
+// Panics:
+//   - If p is not within [0, 1].
+func WithMaxProbability(p decimal.Decimal) Option {
+	validateProbability(p)
+	return func(o *options) {
+		o.bounded = true
+		o.maxProbability = p
+	}
+}
+
+func validateProbability(p decimal.Decimal) {
+	if p.IsNegative() || p.GreaterThan(One) {
+		panic(fmt.Sprintf("probability must be within [0, 1], but was %s", p.String()))
+	}
+}
+
+// BuildTableWithOptions is like BuildTable, but adjusts the distribution
+// with WithCategoryBalance, WithMinProbability and WithMaxProbability first,
+// orders items of equal weight by WithCanonicalOrder, WithTieBreak or
+// WithTieBreakKey, and reports its progress to WithBuildProgress. Other
+// options do not apply to tables, and are ignored; tables are always built
+// by the decimal engine. Use NewAliasVoseMethodWithOptionsE to build with
+// WithIntegerEngine or WithExactThresholds, and receive an error instead.
+//
+// Returns:
+//   - *Table[TItem]: The alias table, which is sampled through Sampler or NextUsing.
+//   - error:         If no items are provided, weights are negative, the
+//     probability bounds cannot be satisfied by the number of items, an
+//     item has no target in WithCategoryBalance, or TieBreakRandom is used.
+//
+// Example usage:
+//
+//	table, err := BuildTableWithOptions(variants,
+//		WithMinProbability(decimal.RequireFromString("0.01")),
+//		WithMaxProbability(decimal.RequireFromString("0.9")),
+//	)
+func BuildTableWithOptions[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight], opts ...ItemOption[TItem]) (*Table[TItem], error) {
+	o := newOptions(opts)
+	items, stable, err := tieBrokenItems(nil, items, o)
+	if err != nil {
+		return nil, err
+	}
+	build := buildContext{
+		progress: o.buildProgress,
+		stable:   stable,
+	}
+	adjusted, ok, err := adjustedItems(items, o)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return buildTable(build, items)
+	}
+	return buildTable(build, adjusted)
+}
+
+// adjustedItems applies WithCategoryBalance and then the probability bounds
+// to the items, and reports false if neither is configured.
+func adjustedItems[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight], o options) ([]WeightedItem[TItem, decimal.Decimal], bool, error) {
+	balance, balanced := categoryBalanceOption[TItem](o)
+	if !balanced && !o.bounded {
+		return nil, false, nil
+	} else if len(items) == 0 {
+		return nil, true, ErrNoItems
+	}
+	adjusted := make([]WeightedItem[TItem, decimal.Decimal], len(items))
+	for i, item := range items {
+		weight, err := decimalWeight(i, item.Weight)
+		if err != nil {
+			return nil, true, err
+		}
+		adjusted[i] = WeightedItem[TItem, decimal.Decimal]{
+			Item:   item.Item,
+			Weight: weight,
+		}
+	}
+	var err error
+	if balanced {
+		if adjusted, err = balance.balancedItems(adjusted); err != nil {
+			return nil, true, err
+		}
+	}
+	if o.bounded {
+		if adjusted, err = boundedItems(adjusted, o.minProbability, o.maxProbability); err != nil {
+			return nil, true, err
+		}
+	}
+	return adjusted, true, nil
+}
+
+// boundedItems returns the items with their probabilities as weights, after
+// clamping them within [low, high].
+func boundedItems[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight], low, high decimal.Decimal) ([]WeightedItem[TItem, decimal.Decimal], error) {
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	count := decimal.NewFromInt(int64(len(items)))
+	if low.GreaterThan(high) {
+		return nil, fmt.Errorf("minimum probability %s exceeds maximum probability %s", low.String(), high.String())
+	} else if low.Mul(count).GreaterThan(One) {
+		return nil, fmt.Errorf("minimum probability %s cannot be satisfied by %d items", low.String(), len(items))
+	} else if high.Mul(count).LessThan(One) {
+		return nil, fmt.Errorf("maximum probability %s cannot be satisfied by %d items", high.String(), len(items))
+	}
+	weights := make([]decimal.Decimal, len(items))
+	lows := make([]decimal.Decimal, len(items))
+	highs := make([]decimal.Decimal, len(items))
+	for i, item := range items {
+		weight, err := decimalWeight(i, item.Weight)
+		if err != nil {
+			return nil, err
+		}
+		weights[i], lows[i], highs[i] = weight, low, high
+	}
+	shares := clampedShares(weights, lows, highs)
+	result := make([]WeightedItem[TItem, decimal.Decimal], len(items))
+	for i, item := range items {
+		result[i] = WeightedItem[TItem, decimal.Decimal]{
+			Item:   item.Item,
+			Weight: shares[i],
+		}
+	}
+	return result, nil
+}
+
+// clampedShares returns the shares of the positive weights, after clamping
+// each within its bounds, which must be feasible. The share of every weight
+// is the weight scaled by a common factor and then clamped, where the factor
+// is chosen for the shares to sum to 1. The sum increases with the factor,
+// and is linear between the factors at which weights become clamped, so the
+// factor is found exactly by searching for the segment containing it.
+func clampedShares(weights, lows, highs []decimal.Decimal) []decimal.Decimal {
+	breakpoints := make([]decimal.Decimal, 0, 2*len(weights))
+	for i, weight := range weights {
+		breakpoints = append(breakpoints,
+			lows[i].DivRound(weight, boundsPrecision),
+			highs[i].DivRound(weight, boundsPrecision),
+		)
+	}
+	slices.SortFunc(breakpoints, decimal.Decimal.Cmp)
+	clamp := func(factor decimal.Decimal, i int) decimal.Decimal {
+		return decimal.Min(decimal.Max(factor.Mul(weights[i]), lows[i]), highs[i])
+	}
+	sum := func(factor decimal.Decimal) decimal.Decimal {
+		total := decimal.Zero
+		for i := range weights {
+			total = total.Add(clamp(factor, i))
+		}
+		return total
+	}
+	index := sort.Search(len(breakpoints), func(i int) bool {
+		return sum(breakpoints[i]).GreaterThanOrEqual(One)
+	})
+	factor := breakpoints[min(index, len(breakpoints)-1)]
+	if index > 0 && index < len(breakpoints) {
+		// Within the segment, the clamped weights contribute a constant sum,
+		// and the others contribute in proportion to the factor.
+		lower, upper := breakpoints[index-1], breakpoints[index]
+		clamped, free := decimal.Zero, decimal.Zero
+		for i, weight := range weights {
+			if lows[i].DivRound(weight, boundsPrecision).GreaterThanOrEqual(upper) {
+				clamped = clamped.Add(lows[i])
+			} else if highs[i].DivRound(weight, boundsPrecision).LessThanOrEqual(lower) {
+				clamped = clamped.Add(highs[i])
+			} else {
+				free = free.Add(weight)
+			}
+		}
+		if free.IsPositive() {
+			factor = One.Sub(clamped).DivRound(free, boundsPrecision)
+		}
+	}
+	shares := make([]decimal.Decimal, len(weights))
+	for i := range weights {
+		shares[i] = clamp(factor, i)
+	}
+	return shares
+}
@@ -0,0 +1,98 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbabilityBounds(t *testing.T) {
+	items := func(weights ...int) []WeightedItem[int, int] {
+		result := make([]WeightedItem[int, int], len(weights))
+		for i, weight := range weights {
+			result[i] = WeightedItem[int, int]{Item: i, Weight: weight}
+		}
+		return result
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithMinProbability(FixtureDecimal(t, "-0.1"))
+		})
+		assert.Panics(t, func() {
+			WithMaxProbability(FixtureDecimal(t, "1.1"))
+		})
+		assert.Panics(t, func() {
+			NewAliasVoseMethodWithOptions(nil, items(1, 1, 1), WithMinProbability(FixtureDecimal(t, "0.5")))
+		})
+	})
+	t.Run("infeasible", func(t *testing.T) {
+		_, err := BuildTableWithOptions(items(1, 1, 1), WithMinProbability(FixtureDecimal(t, "0.34")))
+		assert.ErrorContains(t, err, "cannot be satisfied by 3 items")
+		_, err = BuildTableWithOptions(items(1, 1, 1), WithMaxProbability(FixtureDecimal(t, "0.33")))
+		assert.ErrorContains(t, err, "cannot be satisfied by 3 items")
+		_, err = BuildTableWithOptions(items(1, 1),
+			WithMinProbability(FixtureDecimal(t, "0.5")),
+			WithMaxProbability(FixtureDecimal(t, "0.4")),
+		)
+		assert.ErrorContains(t, err, "exceeds maximum probability")
+		_, err = BuildTableWithOptions(items(-1), WithMaxProbability(One))
+		assert.Error(t, err)
+	})
+	for name, test := range map[string]struct {
+		weights  []int
+		opts     []ItemOption[int]
+		expected []float64
+	}{
+		"unbounded": {
+			weights:  []int{1, 3},
+			expected: []float64{0.25, 0.75},
+		},
+		"floor": {
+			weights:  []int{1, 1, 98},
+			opts:     []ItemOption[int]{WithMinProbability(FixtureDecimal(t, "0.05"))},
+			expected: []float64{0.05, 0.05, 0.9},
+		},
+		"cap": {
+			weights:  []int{1, 1, 8},
+			opts:     []ItemOption[int]{WithMaxProbability(FixtureDecimal(t, "0.5"))},
+			expected: []float64{0.25, 0.25, 0.5},
+		},
+		"floor and cap": {
+			weights: []int{1, 2, 97},
+			opts: []ItemOption[int]{
+				WithMinProbability(FixtureDecimal(t, "0.1")),
+				WithMaxProbability(FixtureDecimal(t, "0.8")),
+			},
+			expected: []float64{0.1, 0.1, 0.8},
+		},
+		"floor redistributed by weight": {
+			weights:  []int{1, 30, 60, 9},
+			opts:     []ItemOption[int]{WithMinProbability(FixtureDecimal(t, "0.05"))},
+			expected: []float64{0.05, 0.95 * 30 / 99, 0.95 * 60 / 99, 0.95 * 9 / 99},
+		},
+		"tight": {
+			weights: []int{1, 2, 3, 4},
+			opts: []ItemOption[int]{
+				WithMinProbability(FixtureDecimal(t, "0.25")),
+				WithMaxProbability(FixtureDecimal(t, "0.25")),
+			},
+			expected: []float64{0.25, 0.25, 0.25, 0.25},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			table, err := BuildTableWithOptions(items(test.weights...), test.opts...)
+			require.NoError(t, err)
+			decimalEngine := NewAliasVoseMethodWithOptions(nil, items(test.weights...), test.opts...)
+			integerEngine := NewAliasVoseMethodWithOptions(nil, items(test.weights...), append(test.opts, WithIntegerEngine())...)
+			for _, wr := range []WeightedRandom[int]{table.Sampler(nil), decimalEngine, integerEngine} {
+				distribution := DistributionOf(wr)
+				for item, expected := range test.expected {
+					actual := distribution.Probability(item).InexactFloat64()
+					assert.InDeltaf(t, expected, actual, 1e-9, "%d of %T", item, wr)
+				}
+			}
+		})
+	}
+}
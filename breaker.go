@@ -0,0 +1,200 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BreakerState is the state of the circuit breaker of an item.
+type BreakerState int
+
+const (
+	// BreakerClosed items are selected with their full weight.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen items are excluded from selection.
+	BreakerOpen
+	// BreakerHalfOpen items are selected with a reduced probe weight, which
+	// grows with every success until the item is closed again.
+	BreakerHalfOpen
+)
+
+func (state BreakerState) String() string {
+	switch state {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(state))
+	}
+}
+
+type breakerItem struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	fraction decimal.Decimal
+}
+
+// Breaker is a WeightedRandom where every item carries a circuit breaker,
+// driven by the outcomes passed to Report. After consecutive failures, an
+// item's breaker opens and the item is excluded. Once the open duration has
+// passed, the item is half-open and re-admitted with a small fraction of its
+// weight; every success doubles the fraction until the item is closed, and
+// any failure opens it again.
+//
+// Breaker is safe for concurrent use, given that the random number
+// generator is.
+type Breaker[TItem comparable] struct {
+	mutex         sync.Mutex
+	clock         Clock
+	table         *adjustableTable[TItem]
+	indices       map[TItem][]int
+	items         map[TItem]*breakerItem
+	maxFailures   int
+	openDuration  time.Duration
+	probeFraction decimal.Decimal
+}
+
+// NewBreaker constructs a new Breaker with every breaker closed. By default,
+// a breaker opens after 5 consecutive failures, stays open for 30 seconds,
+// and is re-admitted with a tenth of its weight; use WithBreakerPolicy to
+// change this.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - clock:  A Clock implementation used to determine when breakers become half-open.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	b := NewBreaker(randSource, SystemClock, WeightedItem[string, int]{Item: "a", Weight: 2}, WeightedItem[string, int]{Item: "b", Weight: 3})
+//	backend := b.Next()
+//	b.Report(backend, call(backend))
+func NewBreaker[TItem comparable, TWeight Weight](random RandIntN, clock Clock, items ...WeightedItem[TItem, TWeight]) *Breaker[TItem] {
+	breaker := &Breaker[TItem]{
+		clock:         clock,
+		table:         newAdjustableTable(random, items),
+		indices:       make(map[TItem][]int, len(items)),
+		items:         make(map[TItem]*breakerItem, len(items)),
+		maxFailures:   5,
+		openDuration:  30 * time.Second,
+		probeFraction: decimal.NewFromFloat(0.1),
+	}
+	for index, item := range items {
+		breaker.indices[item.Item] = append(breaker.indices[item.Item], index)
+		breaker.items[item.Item] = &breakerItem{}
+	}
+	return breaker
+}
+
+// WithBreakerPolicy configures the number of consecutive failures that open
+// a breaker, how long it stays open, and the fraction of its weight an item
+// is re-admitted with. It returns the Breaker to allow chaining.
+//
+// Panics:
+//   - If maxFailures or openDuration are not positive, or probeFraction is not within (0, 1].
+func (breaker *Breaker[TItem]) WithBreakerPolicy(maxFailures int, openDuration time.Duration, probeFraction decimal.Decimal) *Breaker[TItem] {
+	if maxFailures < 1 {
+		panic(fmt.Sprintf("maximum failures must be positive value, but was %d", maxFailures))
+	} else if openDuration <= 0 {
+		panic(fmt.Sprintf("open duration must be positive value, but was %s", openDuration))
+	} else if !probeFraction.IsPositive() || probeFraction.GreaterThan(One) {
+		panic(fmt.Sprintf("probe fraction must be within (0, 1], but was %s", probeFraction.String()))
+	}
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	breaker.maxFailures = maxFailures
+	breaker.openDuration = openDuration
+	breaker.probeFraction = probeFraction
+	return breaker
+}
+
+// Next selects an item from the items whose breaker is not open.
+//
+// Panics:
+//   - If every breaker is open. Use TryNext to avoid this.
+func (breaker *Breaker[TItem]) Next() TItem {
+	item, ok := breaker.TryNext()
+	if !ok {
+		panic("every circuit breaker is open")
+	}
+	return item
+}
+
+// TryNext selects an item from the items whose breaker is not open. If every
+// breaker is open, it returns false.
+func (breaker *Breaker[TItem]) TryNext() (TItem, bool) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	now := breaker.clock.Now()
+	for item, current := range breaker.items {
+		if current.state == BreakerOpen && !now.Before(current.openedAt.Add(breaker.openDuration)) {
+			breaker.transition(item, BreakerHalfOpen, breaker.probeFraction)
+		}
+	}
+	index, ok := breaker.table.next()
+	if !ok {
+		var zero TItem
+		return zero, false
+	}
+	return breaker.table.item(index), true
+}
+
+// Report records the outcome of using the item, where a nil error is a
+// success.
+func (breaker *Breaker[TItem]) Report(item TItem, err error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	current, ok := breaker.items[item]
+	if !ok {
+		return
+	}
+	if err == nil {
+		current.failures = 0
+		if current.state == BreakerHalfOpen {
+			if fraction := current.fraction.Mul(decimal.NewFromInt(2)); fraction.LessThan(One) {
+				breaker.transition(item, BreakerHalfOpen, fraction)
+			} else {
+				breaker.transition(item, BreakerClosed, One)
+			}
+		}
+		return
+	}
+	current.failures++
+	if current.state == BreakerHalfOpen || current.failures >= breaker.maxFailures {
+		current.openedAt = breaker.clock.Now()
+		breaker.transition(item, BreakerOpen, decimal.Zero)
+	}
+}
+
+// State returns the state of the breaker of the item.
+func (breaker *Breaker[TItem]) State(item TItem) BreakerState {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	if current, ok := breaker.items[item]; ok {
+		return current.state
+	}
+	return BreakerClosed
+}
+
+func (breaker *Breaker[TItem]) transition(item TItem, state BreakerState, fraction decimal.Decimal) {
+	current := breaker.items[item]
+	current.state = state
+	current.fraction = fraction
+	current.failures = 0
+	for _, index := range breaker.indices[item] {
+		breaker.table.setWeight(index, breaker.table.baseWeight(index).Mul(fraction))
+	}
+}
@@ -0,0 +1,77 @@
+package weightedrand_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1},
+	}
+	failure := errors.New("unavailable")
+	t.Run("panic", func(t *testing.T) {
+		b := NewBreaker(nil, SystemClock, items...)
+		assert.Panics(t, func() {
+			b.WithBreakerPolicy(0, time.Second, One)
+		})
+		assert.Panics(t, func() {
+			b.WithBreakerPolicy(1, 0, One)
+		})
+		assert.Panics(t, func() {
+			b.WithBreakerPolicy(1, time.Second, FixtureDecimal(t, "1.5"))
+		})
+	})
+	t.Run("lifecycle", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		b := NewBreaker(r, clock, items...).
+			WithBreakerPolicy(2, time.Minute, FixtureDecimal(t, "0.25"))
+
+		b.Report(Blue, failure)
+		b.Report(Blue, nil)
+		b.Report(Blue, failure)
+		assert.Equal(t, BreakerClosed, b.State(Blue))
+		b.Report(Blue, failure)
+		assert.Equal(t, BreakerOpen, b.State(Blue))
+		for range 100 {
+			assert.Equal(t, Red, b.Next())
+		}
+
+		clock.Advance(time.Minute)
+		b.Next()
+		assert.Equal(t, BreakerHalfOpen, b.State(Blue))
+		counts := Counts[MarbleColor](b, 100_000)
+		assert.InDeltaf(t, 0.2, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+
+		b.Report(Blue, nil)
+		assert.Equal(t, BreakerHalfOpen, b.State(Blue))
+		counts = Counts[MarbleColor](b, 100_000)
+		assert.InDeltaf(t, 1.0/3, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+
+		b.Report(Blue, failure)
+		assert.Equal(t, BreakerOpen, b.State(Blue))
+		clock.Advance(time.Minute)
+		b.Next()
+		b.Report(Blue, nil)
+		b.Report(Blue, nil)
+		assert.Equal(t, BreakerClosed, b.State(Blue))
+	})
+	t.Run("every breaker open", func(t *testing.T) {
+		b := NewBreaker(rand.New(rand.NewSource(1)), NewFixtureClock(), items...).
+			WithBreakerPolicy(1, time.Minute, One)
+		b.Report(Red, failure)
+		b.Report(Blue, failure)
+		_, ok := b.TryNext()
+		assert.False(t, ok)
+		assert.Panics(t, func() {
+			b.Next()
+		})
+	})
+}
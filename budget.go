@@ -0,0 +1,151 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Budgeted is a WeightedRandom where items may have a budget that is spent
+// each time they are selected. Once an item can no longer afford the cost of
+// a selection, it is excluded and the distribution is renormalized over the
+// remaining items. Selection and budget accounting happen atomically, so an
+// item is never selected beyond its budget.
+//
+// Budgeted is safe for concurrent use, given that the random number
+// generator is.
+type Budgeted[TItem comparable] struct {
+	mutex     sync.Mutex
+	table     *adjustableTable[TItem]
+	indices   map[TItem][]int
+	remaining map[TItem]decimal.Decimal
+	cost      func(item TItem) decimal.Decimal
+}
+
+// NewBudgeted constructs a new Budgeted instance. Items are unlimited until
+// a budget is configured with WithBudget, and every selection costs 1 until
+// a cost function is configured with WithCost.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	b := NewBudgeted(randSource, WeightedItem[string, int]{Item: "coupon-10", Weight: 1}, WeightedItem[string, int]{Item: "coupon-5", Weight: 4}).
+//		WithBudget("coupon-10", decimal.NewFromInt(500))
+func NewBudgeted[TItem comparable, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) *Budgeted[TItem] {
+	budgeted := &Budgeted[TItem]{
+		table:     newAdjustableTable(random, items),
+		indices:   make(map[TItem][]int, len(items)),
+		remaining: make(map[TItem]decimal.Decimal),
+		cost: func(TItem) decimal.Decimal {
+			return One
+		},
+	}
+	for index, item := range items {
+		budgeted.indices[item.Item] = append(budgeted.indices[item.Item], index)
+	}
+	return budgeted
+}
+
+// WithBudget sets the remaining budget of the item. It returns the Budgeted
+// to allow chaining.
+//
+// Panics:
+//   - If the budget is negative.
+func (budgeted *Budgeted[TItem]) WithBudget(item TItem, budget decimal.Decimal) *Budgeted[TItem] {
+	if budget.IsNegative() {
+		panic(fmt.Sprintf("budget must be non-negative value, but was %s", budget.String()))
+	}
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	budgeted.remaining[item] = budget
+	budgeted.refresh(item)
+	return budgeted
+}
+
+// WithCost sets the function that determines how much of an item's budget
+// is spent when it is selected. It returns the Budgeted to allow chaining.
+func (budgeted *Budgeted[TItem]) WithCost(cost func(item TItem) decimal.Decimal) *Budgeted[TItem] {
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	budgeted.cost = cost
+	for item := range budgeted.remaining {
+		budgeted.refresh(item)
+	}
+	return budgeted
+}
+
+// Next selects an item from the items that can afford to be selected.
+//
+// Panics:
+//   - If every item has exhausted its budget. Use TryNext to avoid this.
+func (budgeted *Budgeted[TItem]) Next() TItem {
+	item, ok := budgeted.TryNext()
+	if !ok {
+		panic("every item has exhausted its budget")
+	}
+	return item
+}
+
+// TryNext selects an item from the items that can afford to be selected,
+// and spends its budget. If every item has exhausted its budget, it returns
+// false.
+func (budgeted *Budgeted[TItem]) TryNext() (TItem, bool) {
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	index, ok := budgeted.table.next()
+	if !ok {
+		var zero TItem
+		return zero, false
+	}
+	item := budgeted.table.item(index)
+	if remaining, ok := budgeted.remaining[item]; ok {
+		budgeted.remaining[item] = remaining.Sub(budgeted.cost(item))
+		budgeted.refresh(item)
+	}
+	return item, true
+}
+
+// Remaining reports the remaining budget of the item. It returns false if
+// the item has no budget, and is therefore unlimited.
+func (budgeted *Budgeted[TItem]) Remaining(item TItem) (decimal.Decimal, bool) {
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	remaining, ok := budgeted.remaining[item]
+	return remaining, ok
+}
+
+// Refill adds the amount to the remaining budget of the item, which may
+// allow an exhausted item to be selected again. Items without a budget are
+// unaffected.
+func (budgeted *Budgeted[TItem]) Refill(item TItem, amount decimal.Decimal) {
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	if remaining, ok := budgeted.remaining[item]; ok {
+		budgeted.remaining[item] = remaining.Add(amount)
+		budgeted.refresh(item)
+	}
+}
+
+// refresh excludes or restores the item depending on whether its remaining
+// budget can afford the cost of a selection.
+func (budgeted *Budgeted[TItem]) refresh(item TItem) {
+	remaining, ok := budgeted.remaining[item]
+	affordable := !ok || remaining.GreaterThanOrEqual(budgeted.cost(item))
+	for _, index := range budgeted.indices[item] {
+		if affordable {
+			budgeted.table.restore(index)
+		} else {
+			budgeted.table.exclude(index)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgeted(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1_000},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewBudgeted(nil, items...).WithBudget(Blue, decimal.NewFromInt(-1))
+		})
+	})
+	t.Run("exhausted items drop out", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		b := NewBudgeted(r, items...).
+			WithBudget(Blue, decimal.NewFromInt(5))
+		counts := make(MarbleColorCounts)
+		for range 1_000 {
+			counts[b.Next()] += 1
+		}
+		assert.Equalf(t, int64(5), counts[Blue], "%s", counts)
+		remaining, ok := b.Remaining(Blue)
+		assert.True(t, ok)
+		assert.True(t, remaining.IsZero())
+		_, ok = b.Remaining(Red)
+		assert.False(t, ok)
+	})
+	t.Run("cost and refill", func(t *testing.T) {
+		b := NewBudgeted(rand.New(rand.NewSource(1)), items...).
+			WithBudget(Red, FixtureDecimal(t, "2.5")).
+			WithBudget(Blue, FixtureDecimal(t, "1")).
+			WithCost(func(item MarbleColor) decimal.Decimal {
+				if item == Red {
+					return FixtureDecimal(t, "1.25")
+				}
+				return One
+			})
+		counts := make(MarbleColorCounts)
+		for {
+			item, ok := b.TryNext()
+			if !ok {
+				break
+			}
+			counts[item] += 1
+		}
+		assert.Equalf(t, int64(2), counts[Red], "%s", counts)
+		assert.Equalf(t, int64(1), counts[Blue], "%s", counts)
+		assert.Panics(t, func() {
+			b.Next()
+		})
+		b.Refill(Blue, One)
+		assert.Equal(t, Blue, b.Next())
+	})
+}
@@ -0,0 +1,80 @@
+package weightedrand
+
+import (
+	"slices"
+	"sync"
+)
+
+// Builder accumulates items from several places, such as configuration files
+// and feature registries, before the alias table is built, without
+// collecting them into a slice first. Items are kept in the order they were
+// added, and duplicated items are not combined.
+//
+// Builder is safe for concurrent use, and may keep accumulating items after
+// a table is built, to build another.
+type Builder[TItem any, TWeight Weight] struct {
+	mutex  sync.Mutex
+	random RandIntN
+	opts   []ItemOption[TItem]
+	items  []WeightedItem[TItem, TWeight]
+}
+
+// NewBuilder constructs a new, empty Builder, whose tables are built with
+// the options, as by NewAliasVoseMethodWithOptions.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - opts:   A variadic list of Option and ItemOption values.
+//
+// Example usage:
+//
+//	builder := NewBuilder[string, int](randSource)
+//	builder.Add("A", 2).Add("B", 3)
+//	builder.AddAll(itemsFromConfig...)
+//	wr := builder.Build()
+func NewBuilder[TItem any, TWeight Weight](random RandIntN, opts ...ItemOption[TItem]) *Builder[TItem, TWeight] {
+	return &Builder[TItem, TWeight]{
+		random: random,
+		opts:   opts,
+	}
+}
+
+// Add adds the item with the weight, and returns the Builder to allow
+// chaining. Weights are validated when the table is built.
+func (builder *Builder[TItem, TWeight]) Add(item TItem, weight TWeight) *Builder[TItem, TWeight] {
+	return builder.AddAll(WeightedItem[TItem, TWeight]{
+		Item:   item,
+		Weight: weight,
+	})
+}
+
+// AddAll adds the items, and returns the Builder to allow chaining.
+func (builder *Builder[TItem, TWeight]) AddAll(items ...WeightedItem[TItem, TWeight]) *Builder[TItem, TWeight] {
+	builder.mutex.Lock()
+	defer builder.mutex.Unlock()
+	builder.items = append(builder.items, items...)
+	return builder
+}
+
+// Len returns the number of items added.
+func (builder *Builder[TItem, TWeight]) Len() int {
+	builder.mutex.Lock()
+	defer builder.mutex.Unlock()
+	return len(builder.items)
+}
+
+// Build builds the alias table from the items added so far.
+//
+// Panics:
+//   - Under the same conditions as NewAliasVoseMethodWithOptions, such as
+//     when no items were added, or weights are negative.
+func (builder *Builder[TItem, TWeight]) Build() WeightedRandom[TItem] {
+	builder.mutex.Lock()
+	items := slices.Clone(builder.items)
+	builder.mutex.Unlock()
+	return NewAliasVoseMethodWithOptions(builder.random, items, builder.opts...)
+}
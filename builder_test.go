@@ -0,0 +1,40 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewBuilder[MarbleColor, int](nil).Build()
+		})
+		assert.Panics(t, func() {
+			NewBuilder[MarbleColor, int](nil).Add(Red, -1).Build()
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		builder := NewBuilder[MarbleColor, int](r, WithIntegerEngine())
+		builder.Add(Red, 1).AddAll(
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 2},
+		)
+		assert.Equal(t, 3, builder.Len())
+		wr := builder.Build()
+		counts := Counts(wr, 100_000)
+		assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+
+		// Items added afterwards only affect the tables built afterwards.
+		builder.Add(Yellow, 4)
+		counts = Counts(wr, 100_000)
+		assert.Zero(t, counts[Yellow])
+		counts = Counts(builder.Build(), 100_000)
+		assert.InDeltaf(t, 0.5, float64(counts[Yellow])/100_000, tolerance, "%v", counts)
+	})
+}
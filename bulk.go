@@ -0,0 +1,65 @@
+package weightedrand
+
+import "context"
+
+// bulkThreshold is the number of selections from which AppendNextN uses the
+// bulk path of a chooser, which has a setup cost proportional to the number
+// of items.
+const bulkThreshold = 64
+
+// bulkChunk is the number of selections whose random numbers are generated
+// at once by the bulk path.
+const bulkChunk = 512
+
+// bulkSampler is implemented by the choosers with a faster path for many
+// selections at once. The selections must be the same as those of as many
+// calls to Next. The context is checked between chunks, and the selections
+// made until it is done are returned with its error.
+type bulkSampler[TItem any] interface {
+	appendNextN(ctx context.Context, dst []TItem, n int) ([]TItem, error)
+}
+
+// appendNextN resolves the coin tosses of the alias table against flat
+// integer thresholds rather than decimals. Next tosses k out of 100 and
+// selects the primary item if k/100 is less than the probability, which is
+// when k is less than the probability times 100 rounded up. The random
+// numbers are generated in chunks, in the same order as Next does, and then
+// resolved in a separate loop over flat arrays that is free of interface
+// calls.
+func (aliasMethod voseAliasMethodRandom[TItem]) appendNextN(ctx context.Context, dst []TItem, n int) ([]TItem, error) {
+	tuples := aliasMethod.tuples
+	thresholds := make([]int64, len(tuples))
+	primaries := make([]TItem, len(tuples))
+	aliases := make([]TItem, len(tuples))
+	for i, tuple := range tuples {
+		thresholds[i] = min(tuple.probability.Shift(2).Ceil().IntPart(), 100)
+		primaries[i] = tuple.primaryItem
+		aliases[i] = tuple.primaryItem
+		if tuple.aliasedItem != nil {
+			aliases[i] = *tuple.aliasedItem
+		}
+	}
+	chunk := min(n, bulkChunk)
+	buckets := make([]int, chunk)
+	tosses := make([]int64, chunk)
+	for n > 0 {
+		if err := ctx.Err(); err != nil {
+			return dst, err
+		}
+		size := min(n, chunk)
+		for i := range size {
+			buckets[i] = aliasMethod.random.Intn(len(tuples))
+			tosses[i] = aliasMethod.random.Int63n(100)
+		}
+		for i := range size {
+			bucket := buckets[i]
+			if tosses[i] < thresholds[bucket] {
+				dst = append(dst, primaries[bucket])
+			} else {
+				dst = append(dst, aliases[bucket])
+			}
+		}
+		n -= size
+	}
+	return dst, nil
+}
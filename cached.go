@@ -0,0 +1,96 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Cached is a WeightedRandom that pins selections to keys, such as routing
+// every request of a session to the same backend. A key keeps its selection
+// until it expires, and is then selected again, which rebalances keys over
+// time as weights would have them.
+//
+// Cached is safe for concurrent use, given that the wrapped instance is.
+type Cached[TItem any] struct {
+	wr      WeightedRandom[TItem]
+	clock   Clock
+	mutex   sync.Mutex
+	entries map[string]cachedSelection[TItem]
+	// sweepAt is the number of entries at which expired entries are next
+	// discarded, so that keys that are never used again do not accumulate.
+	sweepAt int
+}
+
+type cachedSelection[TItem any] struct {
+	item    TItem
+	expires time.Time
+}
+
+// NewCached constructs a new Cached instance over the WeightedRandom
+// instance.
+//
+// Example usage:
+//
+//	c := NewCached(wr, SystemClock)
+//	backend := c.NextCached(sessionID, 10*time.Minute)
+func NewCached[TItem any](wr WeightedRandom[TItem], clock Clock) *Cached[TItem] {
+	return &Cached[TItem]{
+		wr:      wr,
+		clock:   clock,
+		entries: make(map[string]cachedSelection[TItem]),
+		sweepAt: 64,
+	}
+}
+
+// Next selects an item from the wrapped instance, without pinning it.
+func (cached *Cached[TItem]) Next() TItem {
+	return cached.wr.Next()
+}
+
+// NextCached returns the selection of the key, if it has not expired, or
+// selects an item for the key which is kept for the ttl.
+//
+// Panics:
+//   - If the ttl is not positive.
+func (cached *Cached[TItem]) NextCached(key string, ttl time.Duration) TItem {
+	if ttl <= 0 {
+		panic(fmt.Sprintf("ttl must be positive value, but was %s", ttl))
+	}
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+	now := cached.clock.Now()
+	if entry, ok := cached.entries[key]; ok && now.Before(entry.expires) {
+		return entry.item
+	}
+	item := cached.wr.Next()
+	cached.entries[key] = cachedSelection[TItem]{
+		item:    item,
+		expires: now.Add(ttl),
+	}
+	if len(cached.entries) >= cached.sweepAt {
+		for key, entry := range cached.entries {
+			if !now.Before(entry.expires) {
+				delete(cached.entries, key)
+			}
+		}
+		cached.sweepAt = max(64, 2*len(cached.entries))
+	}
+	return item
+}
+
+// Forget discards the selection of the key, so that the next call to
+// NextCached selects again.
+func (cached *Cached[TItem]) Forget(key string) {
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+	delete(cached.entries, key)
+}
+
+// Len returns the number of keys with a selection, including those that
+// expired but were not yet discarded.
+func (cached *Cached[TItem]) Len() int {
+	cached.mutex.Lock()
+	defer cached.mutex.Unlock()
+	return len(cached.entries)
+}
@@ -0,0 +1,58 @@
+package weightedrand_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCached(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	items := make([]WeightedItem[int, int], 100)
+	for i := range items {
+		items[i] = WeightedItem[int, int]{Item: i, Weight: 1}
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCached(NewAliasVoseMethod(r, items...), SystemClock).NextCached("a", 0)
+		})
+	})
+	t.Run("pinned until expiry", func(t *testing.T) {
+		clock := NewFixtureClock()
+		c := NewCached(NewAliasVoseMethod(r, items...), clock)
+		first := c.NextCached("session", time.Minute)
+		for range 100 {
+			assert.Equal(t, first, c.NextCached("session", time.Minute))
+		}
+		clock.Advance(59 * time.Second)
+		assert.Equal(t, first, c.NextCached("session", time.Minute))
+		// After expiry, the key is selected again and is unlikely to keep
+		// the same item every time.
+		changed := false
+		for range 10 {
+			clock.Advance(time.Minute)
+			changed = changed || c.NextCached("session", time.Minute) != first
+		}
+		assert.True(t, changed)
+	})
+	t.Run("forget", func(t *testing.T) {
+		c := NewCached(NewAliasVoseMethod(r, items...), NewFixtureClock())
+		c.NextCached("session", time.Minute)
+		assert.Equal(t, 1, c.Len())
+		c.Forget("session")
+		assert.Zero(t, c.Len())
+	})
+	t.Run("expired keys are discarded", func(t *testing.T) {
+		clock := NewFixtureClock()
+		c := NewCached(NewAliasVoseMethod(r, items...), clock)
+		for i := range 1_000 {
+			c.NextCached(fmt.Sprint(i), time.Second)
+			clock.Advance(time.Second)
+		}
+		assert.Less(t, c.Len(), 100)
+	})
+}
@@ -0,0 +1,147 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Canary routes a share of selections to a canary item, and the rest to a
+// baseline item. Outcomes of the canary are reported back with Report, and
+// when its failure rate exceeds a threshold, a rollback callback is invoked
+// so that the canary's share can be shrunk or removed.
+//
+// Canary is safe for concurrent use, given that the random number generator
+// is.
+type Canary[TItem comparable] struct {
+	mutex     sync.Mutex
+	random    RandIntN
+	baseline  TItem
+	canary    TItem
+	weight    decimal.Decimal
+	threshold int64
+
+	maxFailureRate  decimal.Decimal
+	minObservations int
+	onRollback      func(canary *Canary[TItem], failureRate decimal.Decimal)
+	successes       int
+	failures        int
+}
+
+// NewCanary constructs a new Canary, routing the weight (a probability
+// between 0 and 1) of selections to the canary item. No rollback happens
+// until one is configured with WithRollback.
+//
+// Panics:
+//   - If the weight is not between 0 and 1.
+//
+// Example usage:
+//
+//	c := NewCanary(randSource, "v1", "v2", decimal.RequireFromString("0.05")).
+//		WithRollback(decimal.RequireFromString("0.02"), 100, func(c *Canary[string], rate decimal.Decimal) {
+//			c.SetWeight(decimal.Zero)
+//		})
+//	version := c.Next()
+//	c.Report(version, err == nil)
+func NewCanary[TItem comparable](random RandIntN, baseline, canary TItem, weight decimal.Decimal) *Canary[TItem] {
+	return &Canary[TItem]{
+		random:         random,
+		baseline:       baseline,
+		canary:         canary,
+		weight:         weight,
+		threshold:      probabilityThreshold(weight),
+		maxFailureRate: One,
+		onRollback:     func(*Canary[TItem], decimal.Decimal) {},
+	}
+}
+
+// WithRollback configures the callback invoked when the failure rate of the
+// canary exceeds maxFailureRate, once at least minObservations outcomes of
+// the canary have been reported. The observations are reset after every
+// rollback, so the callback is invoked again only if the canary keeps
+// failing. The callback is invoked without holding any lock, and may call
+// SetWeight. It returns the Canary to allow chaining.
+//
+// Panics:
+//   - If maxFailureRate is not between 0 and 1, or minObservations is not positive.
+func (canary *Canary[TItem]) WithRollback(
+	maxFailureRate decimal.Decimal, minObservations int, onRollback func(canary *Canary[TItem], failureRate decimal.Decimal),
+) *Canary[TItem] {
+	probabilityThreshold(maxFailureRate)
+	if minObservations < 1 {
+		panic(fmt.Sprintf("minimum observations must be positive value, but was %d", minObservations))
+	}
+	canary.mutex.Lock()
+	defer canary.mutex.Unlock()
+	canary.maxFailureRate = maxFailureRate
+	canary.minObservations = minObservations
+	canary.onRollback = onRollback
+	return canary
+}
+
+// Next selects the canary item with the probability of its weight, and the
+// baseline item otherwise.
+func (canary *Canary[TItem]) Next() TItem {
+	canary.mutex.Lock()
+	defer canary.mutex.Unlock()
+	if bernoulli(canary.random, canary.threshold) {
+		return canary.canary
+	}
+	return canary.baseline
+}
+
+// Report records whether a request routed to the item succeeded. Only
+// outcomes of the canary item are tracked.
+func (canary *Canary[TItem]) Report(item TItem, ok bool) {
+	if item != canary.canary {
+		return
+	}
+	canary.mutex.Lock()
+	if ok {
+		canary.successes++
+	} else {
+		canary.failures++
+	}
+	observations := canary.successes + canary.failures
+	if canary.minObservations == 0 || observations < canary.minObservations {
+		canary.mutex.Unlock()
+		return
+	}
+	failureRate := decimal.NewFromInt(int64(canary.failures)).Div(decimal.NewFromInt(int64(observations)))
+	if !failureRate.GreaterThan(canary.maxFailureRate) {
+		canary.mutex.Unlock()
+		return
+	}
+	canary.successes, canary.failures = 0, 0
+	onRollback := canary.onRollback
+	canary.mutex.Unlock()
+	onRollback(canary, failureRate)
+}
+
+// SetWeight changes the share of selections routed to the canary item.
+//
+// Panics:
+//   - If the weight is not between 0 and 1.
+func (canary *Canary[TItem]) SetWeight(weight decimal.Decimal) {
+	threshold := probabilityThreshold(weight)
+	canary.mutex.Lock()
+	defer canary.mutex.Unlock()
+	canary.weight = weight
+	canary.threshold = threshold
+}
+
+// Weight returns the share of selections routed to the canary item.
+func (canary *Canary[TItem]) Weight() decimal.Decimal {
+	canary.mutex.Lock()
+	defer canary.mutex.Unlock()
+	return canary.weight
+}
+
+// Observations returns the number of successful and failed outcomes of the
+// canary reported since the last rollback.
+func (canary *Canary[TItem]) Observations() (successes int, failures int) {
+	canary.mutex.Lock()
+	defer canary.mutex.Unlock()
+	return canary.successes, canary.failures
+}
@@ -0,0 +1,55 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanary(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCanary(nil, Red, Blue, decimal.NewFromInt(2))
+		})
+		assert.Panics(t, func() {
+			NewCanary(nil, Red, Blue, decimal.Zero).WithRollback(decimal.Zero, 0, nil)
+		})
+	})
+	t.Run("routes by weight", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		c := NewCanary(r, Red, Blue, FixtureDecimal(t, "0.2"))
+		counts := Counts[MarbleColor](c, 100_000)
+		assert.InDeltaf(t, 0.2, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("rollback", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		rollbacks := 0
+		c := NewCanary(r, Red, Blue, FixtureDecimal(t, "0.5")).
+			WithRollback(FixtureDecimal(t, "0.25"), 4, func(c *Canary[MarbleColor], failureRate decimal.Decimal) {
+				rollbacks++
+				assert.Equal(t, "0.5", failureRate.String())
+				c.SetWeight(decimal.Zero)
+			})
+		c.Report(Red, false)
+		c.Report(Red, false)
+		c.Report(Blue, true)
+		c.Report(Blue, false)
+		c.Report(Blue, true)
+		successes, failures := c.Observations()
+		assert.Equal(t, 2, successes)
+		assert.Equal(t, 1, failures)
+		assert.Zero(t, rollbacks)
+		c.Report(Blue, false)
+		assert.Equal(t, 1, rollbacks)
+		assert.True(t, c.Weight().IsZero())
+		for range 1_000 {
+			assert.Equal(t, Red, c.Next())
+		}
+		successes, failures = c.Observations()
+		assert.Zero(t, successes+failures)
+	})
+}
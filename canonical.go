@@ -0,0 +1,21 @@
+package weightedrand
+
+// WithCanonicalOrder arranges the items in a canonical order before the alias
+// table is built, so that the table, and the selections under a fixed seed,
+// do not depend on the order the items were provided in. Items of equal
+// weight are ordered by their formatted representation (%v), as if by
+// WithTieBreakKey. Without it, the distribution is the same regardless of
+// order, but reordering items of equal weight changes which item each bucket
+// holds, and thus the sequence of selections.
+//
+// Items with the same weight and representation, such as distinct pointers
+// printed alike, are not ordered by it; their order must not matter.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(seed)), itemsFromYAML, WithCanonicalOrder())
+func WithCanonicalOrder() Option {
+	return func(o *options) {
+		o.tieBreak = tieBreakCanonical
+	}
+}
@@ -0,0 +1,50 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalOrder(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 2},
+		{Item: Orange, Weight: 1},
+		{Item: Yellow, Weight: 2},
+		{Item: Green, Weight: 1},
+		{Item: Blue, Weight: 3},
+	}
+	reordered := []WeightedItem[MarbleColor, int]{
+		items[3], items[4], items[2], items[0], items[1],
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewAliasVoseMethodWithOptions(nil, []WeightedItem[MarbleColor, int]{{Item: Red, Weight: -1}}, WithCanonicalOrder())
+		})
+		_, err := BuildTableWithOptions([]WeightedItem[MarbleColor, int]{{Item: Red, Weight: -1}}, WithCanonicalOrder())
+		var negativeErr *ErrNegativeWeight
+		assert.ErrorAs(t, err, &negativeErr)
+	})
+	for name, opts := range map[string][]ItemOption[MarbleColor]{
+		"decimal": {WithCanonicalOrder()},
+		"integer": {WithCanonicalOrder(), WithIntegerEngine()},
+		"bounded": {WithCanonicalOrder(), WithMaxProbability(decimal.RequireFromString("0.3"))},
+	} {
+		t.Run(name, func(t *testing.T) {
+			a := NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(1)), items, opts...)
+			b := NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(1)), reordered, opts...)
+			assert.Equal(t, NextN(a, 1_000), NextN(b, 1_000))
+		})
+	}
+	t.Run("table", func(t *testing.T) {
+		a, err := BuildTableWithOptions(items, WithCanonicalOrder())
+		require.NoError(t, err)
+		b, err := BuildTableWithOptions(reordered, WithCanonicalOrder())
+		require.NoError(t, err)
+		assert.True(t, EqualTables(a, b))
+	})
+}
@@ -0,0 +1,170 @@
+package weightedrand
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/shopspring/decimal"
+)
+
+// CategoryWeighting determines how the weight of a Category is derived when
+// selecting between categories.
+type CategoryWeighting int
+
+const (
+	// ByCategoryWeight selects a category by its weight alone, regardless of
+	// how many items it contains.
+	ByCategoryWeight CategoryWeighting = iota
+	// ByCategoryWeightAndSize selects a category by its weight multiplied by
+	// the number of items it contains, so that each item's overall chance is
+	// proportional to the weight of its category.
+	ByCategoryWeightAndSize
+)
+
+// Category is a group of items that share a single weight. When a category
+// is selected, one of its items is chosen uniformly at random.
+type Category[TItem any, TWeight Weight] struct {
+	Items  []TItem
+	Weight TWeight
+}
+
+type categoryRandom[TItem any] struct {
+	random     RandIntN
+	categories WeightedRandom[[]TItem]
+}
+
+// NewCategorySampler constructs a new WeightedRandom instance that first
+// selects a category by weight, and then selects an item uniformly from the
+// items of that category. This is commonly used for loot pools, where a
+// rarity is chosen first and then any item of that rarity.
+//
+// The function panics if no categories are provided, if a category has no
+// items, or if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each category.
+//
+// Parameters:
+//   - random:     A RandIntN implementation used for random number generation.
+//   - weighting:  How the weight of each category is derived.
+//   - categories: A variadic list of Category values.
+//
+// Example usage:
+//
+//	wr := NewCategorySampler(randSource, ByCategoryWeight,
+//		Category[string, int]{Items: []string{"sword", "shield"}, Weight: 9},
+//		Category[string, int]{Items: []string{"crown"}, Weight: 1},
+//	)
+func NewCategorySampler[TItem any, TWeight Weight](
+	random RandIntN, weighting CategoryWeighting, categories ...Category[TItem, TWeight],
+) WeightedRandom[TItem] {
+	if len(categories) == 0 {
+		panic("at least one category must be provided")
+	}
+	items := make([]WeightedItem[[]TItem, decimal.Decimal], 0, len(categories))
+	for _, category := range categories {
+		if len(category.Items) == 0 {
+			panic("every category must have at least one item")
+		}
+		weight := effectiveWeight(category.Weight)
+		if weighting == ByCategoryWeightAndSize {
+			weight = weight.Mul(decimal.NewFromInt(int64(len(category.Items))))
+		}
+		items = append(items, WeightedItem[[]TItem, decimal.Decimal]{
+			Item:   category.Items,
+			Weight: weight,
+		})
+	}
+	return categoryRandom[TItem]{
+		random:     random,
+		categories: NewAliasVoseMethod(random, items...),
+	}
+}
+
+func (category categoryRandom[TItem]) Next() TItem {
+	items := category.categories.Next()
+	return items[category.random.Intn(len(items))]
+}
+
+// categoryBalance is the configuration of WithCategoryBalance.
+type categoryBalance[TItem any] struct {
+	category func(TItem) string
+	targets  map[string]decimal.Decimal
+}
+
+// WithCategoryBalance allocates the probability of selecting each category
+// by its target, and then distributes it between the items of the category
+// by their weights. Targets are relative to each other, such as shares of
+// exposure for each seller of a marketplace, and categories without items
+// are ignored. The category function is for items of the same type as the
+// items of the instance it is used with.
+//
+// The option may be combined with WithMinProbability and
+// WithMaxProbability, which then apply to the balanced probabilities.
+//
+// Panics:
+//   - If a target is not positive.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, listings, WithCategoryBalance(
+//		func(l Listing) string { return l.Seller },
+//		map[string]decimal.Decimal{"acme": decimal.NewFromInt(1), "globex": decimal.NewFromInt(1)},
+//	))
+func WithCategoryBalance[TItem any](category func(TItem) string, targets map[string]decimal.Decimal) ItemOption[TItem] {
+	for name, target := range targets {
+		if !target.IsPositive() {
+			panic(fmt.Sprintf("target for category %q must be positive value, but was %s", name, target.String()))
+		}
+	}
+	return func(o *options) {
+		o.categoryBalance = categoryBalance[TItem]{
+			category: category,
+			targets:  maps.Clone(targets),
+		}
+	}
+}
+
+// categoryBalanceOption returns the configuration of WithCategoryBalance,
+// which ItemOption ensures is for items of type TItem, and returns false if
+// there is none.
+func categoryBalanceOption[TItem any](o options) (categoryBalance[TItem], bool) {
+	if o.categoryBalance == nil {
+		return categoryBalance[TItem]{}, false
+	}
+	return o.categoryBalance.(categoryBalance[TItem]), true
+}
+
+// balancedItems returns the items, which must have positive weights, with
+// their balanced probabilities as weights.
+func (balance categoryBalance[TItem]) balancedItems(items []WeightedItem[TItem, decimal.Decimal]) ([]WeightedItem[TItem, decimal.Decimal], error) {
+	categories := make([]string, len(items))
+	categoryTotals := make(map[string]decimal.Decimal)
+	for i, item := range items {
+		categories[i] = balance.category(item.Item)
+		if _, ok := balance.targets[categories[i]]; !ok {
+			return nil, fmt.Errorf("category %q of item %v has no target", categories[i], item.Item)
+		}
+		categoryTotals[categories[i]] = categoryTotals[categories[i]].Add(item.Weight)
+	}
+	targetTotal := decimal.Zero
+	for category := range categoryTotals {
+		targetTotal = targetTotal.Add(balance.targets[category])
+	}
+	result := make([]WeightedItem[TItem, decimal.Decimal], len(items))
+	for i, item := range items {
+		share := balance.targets[categories[i]].DivRound(targetTotal, boundsPrecision)
+		weight := share.Mul(item.Weight).DivRound(categoryTotals[categories[i]], boundsPrecision)
+		if weight.IsZero() {
+			// A weight of zero would default to 1, so keep the smallest
+			// weight the precision allows instead.
+			weight = decimal.New(1, -boundsPrecision)
+		}
+		result[i] = WeightedItem[TItem, decimal.Decimal]{
+			Item:   item.Item,
+			Weight: weight,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,104 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategorySampler(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		t.Run("no categories", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewCategorySampler[MarbleColor, int](nil, ByCategoryWeight)
+			})
+		})
+		t.Run("empty category", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewCategorySampler(nil, ByCategoryWeight, Category[MarbleColor, int]{Weight: 1})
+			})
+		})
+	})
+	categories := []Category[MarbleColor, int]{
+		{Items: []MarbleColor{Red, Orange, Yellow}, Weight: 1},
+		{Items: []MarbleColor{Blue}, Weight: 1},
+	}
+	testcases := map[CategoryWeighting]float64{
+		ByCategoryWeight:        0.5,
+		ByCategoryWeightAndSize: 0.25,
+	}
+	for weighting, expectedBlue := range testcases {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewCategorySampler(r, weighting, categories...)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		actualBlue := float64(counts[Blue]) / iterations
+		assert.InDeltaf(t, expectedBlue, actualBlue, tolerance, "weighting %d gave %s", weighting, counts)
+		assert.InDeltaf(t, (1-expectedBlue)/3, float64(counts[Orange])/iterations, tolerance,
+			"weighting %d gave %s", weighting, counts)
+	}
+}
+
+func TestCategoryBalance(t *testing.T) {
+	seller := func(item int) string {
+		return string(rune('a' + item/10))
+	}
+	targets := map[string]decimal.Decimal{
+		"a": FixtureDecimal(t, "1"),
+		"b": FixtureDecimal(t, "1"),
+		"c": FixtureDecimal(t, "3"),
+	}
+	items := []WeightedItem[int, int]{
+		{Item: 1, Weight: 1},
+		{Item: 2, Weight: 3},
+		{Item: 11, Weight: 100},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithCategoryBalance(seller, map[string]decimal.Decimal{"a": FixtureDecimal(t, "0")})
+		})
+		assert.Panics(t, func() {
+			NewAliasVoseMethodWithOptions(nil, items, WithCategoryBalance(seller, map[string]decimal.Decimal{"a": One}))
+		})
+	})
+	t.Run("missing target", func(t *testing.T) {
+		_, err := BuildTableWithOptions(items, WithCategoryBalance(seller, map[string]decimal.Decimal{"a": One}))
+		assert.ErrorContains(t, err, `category "b" of item 11 has no target`)
+	})
+	for name, test := range map[string]struct {
+		opts     []ItemOption[int]
+		expected map[int]float64
+	}{
+		"balanced": {
+			opts:     []ItemOption[int]{WithCategoryBalance(seller, targets)},
+			expected: map[int]float64{1: 0.125, 2: 0.375, 11: 0.5},
+		},
+		"balanced and capped": {
+			opts:     []ItemOption[int]{WithCategoryBalance(seller, targets), WithMaxProbability(FixtureDecimal(t, "0.4"))},
+			expected: map[int]float64{1: 0.2, 2: 0.4, 11: 0.4},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			table, err := BuildTableWithOptions(items, test.opts...)
+			require.NoError(t, err)
+			for _, wr := range []WeightedRandom[int]{
+				table.Sampler(nil),
+				NewAliasVoseMethodWithOptions(nil, items, test.opts...),
+				NewAliasVoseMethodWithOptions(nil, items, append(test.opts, WithIntegerEngine())...),
+			} {
+				distribution := DistributionOf(wr)
+				for item, expected := range test.expected {
+					assert.InDeltaf(t, expected, distribution.Probability(item).InexactFloat64(), 1e-9, "%d of %T", item, wr)
+				}
+			}
+		})
+	}
+}
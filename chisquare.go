@@ -0,0 +1,127 @@
+package weightedrand
+
+import (
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// expectedFrequencies holds the distinct items of a distribution, together
+// with their probabilities, for testing selections against it.
+type expectedFrequencies[TItem comparable] struct {
+	items    []TItem
+	indices  map[TItem]int
+	expected []float64
+}
+
+func newExpectedFrequencies[TItem comparable](wr WeightedRandom[TItem]) expectedFrequencies[TItem] {
+	weights := mergedWeights(distributionOf(wr))
+	frequencies := expectedFrequencies[TItem]{
+		items:    make([]TItem, 0, len(weights)),
+		indices:  make(map[TItem]int, len(weights)),
+		expected: make([]float64, 0, len(weights)),
+	}
+	total := decimal.Zero
+	for _, weight := range weights {
+		total = total.Add(weight)
+	}
+	for item, weight := range weights {
+		frequencies.indices[item] = len(frequencies.items)
+		frequencies.items = append(frequencies.items, item)
+		frequencies.expected = append(frequencies.expected, weight.Div(total).InexactFloat64())
+	}
+	return frequencies
+}
+
+// test runs a chi-square goodness-of-fit test of the counts, indexed like
+// the items, which sum up to the size.
+func (frequencies expectedFrequencies[TItem]) test(counts []int, size int) Deviation[TItem] {
+	statistic := chiSquareStatistic(counts, frequencies.expected, size)
+	return Deviation[TItem]{
+		Statistic: statistic,
+		PValue:    chiSquarePValue(statistic, len(frequencies.items)-1),
+	}
+}
+
+// describe fills in the observed and expected counts of the deviation.
+func (frequencies expectedFrequencies[TItem]) describe(deviation Deviation[TItem], counts []int, size int) Deviation[TItem] {
+	deviation.Observed = make(map[TItem]int, len(frequencies.items))
+	deviation.Expected = make(map[TItem]float64, len(frequencies.items))
+	for i, item := range frequencies.items {
+		deviation.Observed[item] = counts[i]
+		deviation.Expected[item] = frequencies.expected[i] * float64(size)
+	}
+	return deviation
+}
+
+// chiSquareStatistic returns Pearson's chi-square statistic of the observed
+// counts against the expected probabilities, which are indexed alike.
+func chiSquareStatistic(observed []int, expected []float64, total int) float64 {
+	statistic := 0.0
+	for i, probability := range expected {
+		expectedCount := probability * float64(total)
+		if expectedCount == 0 {
+			continue
+		}
+		difference := float64(observed[i]) - expectedCount
+		statistic += difference * difference / expectedCount
+	}
+	return statistic
+}
+
+// chiSquarePValue returns the probability of a chi-square statistic at least
+// as large as the given one, with the degrees of freedom.
+func chiSquarePValue(statistic float64, degrees int) float64 {
+	if degrees <= 0 || statistic <= 0 {
+		return 1
+	}
+	return upperRegularizedGamma(float64(degrees)/2, statistic/2)
+}
+
+// upperRegularizedGamma returns Q(a, x), computed by its series for small x
+// and by its continued fraction otherwise.
+func upperRegularizedGamma(a, x float64) float64 {
+	const (
+		epsilon    = 1e-15
+		tiny       = 1e-300
+		iterations = 1000
+	)
+	logGamma, _ := math.Lgamma(a)
+	prefix := math.Exp(a*math.Log(x) - x - logGamma)
+	if x < a+1 {
+		term := 1 / a
+		sum := term
+		for n := 1; n < iterations; n++ {
+			term *= x / (a + float64(n))
+			sum += term
+			if math.Abs(term) < math.Abs(sum)*epsilon {
+				break
+			}
+		}
+		return max(0, 1-prefix*sum)
+	}
+	// Modified Lentz's method.
+	b := x + 1 - a
+	c := 1 / tiny
+	d := 1 / b
+	fraction := d
+	for n := 1; n < iterations; n++ {
+		an := -float64(n) * (float64(n) - a)
+		b += 2
+		d = an*d + b
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = b + an/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		fraction *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return prefix * fraction
+}
@@ -0,0 +1,83 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Chunk is a partition of a larger data set, such as a file or a table
+// partition, with the number of records it contains.
+type Chunk[TChunk any] struct {
+	Chunk TChunk
+	Size  int64
+}
+
+// ChunkReader reads the record at the offset within the chunk. The offset is
+// always within [0, size) of the chunk.
+type ChunkReader[TChunk any, TRecord any] func(chunk TChunk, offset int64) (TRecord, error)
+
+// ChunkSampler samples records uniformly across a data set that is split into
+// chunks, without reading the chunks up front. It first selects a chunk in
+// proportion to its size, and then a uniform offset within the chunk, so that
+// every record of the data set is equally likely.
+type ChunkSampler[TChunk any, TRecord any] struct {
+	random RandIntN
+	chunks WeightedRandom[Chunk[TChunk]]
+	read   ChunkReader[TChunk, TRecord]
+}
+
+// NewChunkSampler constructs a new ChunkSampler. Empty chunks are never
+// selected.
+//
+// The function panics if every chunk is empty, or if sizes are negative.
+//
+// Type Parameters:
+//   - TChunk:  The type identifying a chunk, such as a file path.
+//   - TRecord: The type of the records read from the chunks.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - read:   A ChunkReader used to read the selected record.
+//   - chunks: A variadic list of Chunk values, each containing a chunk and its size.
+//
+// Example usage:
+//
+//	cs := NewChunkSampler(randSource, readLine,
+//		Chunk[string]{Chunk: "part-0000.csv", Size: 1_000_000},
+//		Chunk[string]{Chunk: "part-0001.csv", Size: 250_000},
+//	)
+//	record, err := cs.Next()
+func NewChunkSampler[TChunk any, TRecord any](
+	random RandIntN, read ChunkReader[TChunk, TRecord], chunks ...Chunk[TChunk],
+) *ChunkSampler[TChunk, TRecord] {
+	// Sizes are used directly as weights, except that empty chunks are
+	// dropped rather than being assumed to have a weight of 1.
+	items := make([]weightedItem[Chunk[TChunk]], 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.Size < 0 {
+			panic(fmt.Sprintf("size must be non-negative value, but was %d", chunk.Size))
+		} else if chunk.Size == 0 {
+			continue
+		}
+		items = append(items, weightedItem[Chunk[TChunk]]{
+			Item:   chunk,
+			Weight: decimal.NewFromInt(chunk.Size),
+		})
+	}
+	if len(items) == 0 {
+		panic("at least one non-empty chunk must be provided")
+	}
+	return &ChunkSampler[TChunk, TRecord]{
+		random: random,
+		chunks: newVoseAliasMethodFromDecimals(random, items),
+		read:   read,
+	}
+}
+
+// Next selects a record uniformly across every chunk, and reads it. Errors
+// from the ChunkReader are returned as-is.
+func (sampler *ChunkSampler[TChunk, TRecord]) Next() (TRecord, error) {
+	chunk := sampler.chunks.Next()
+	return sampler.read(chunk.Chunk, sampler.random.Int63n(chunk.Size))
+}
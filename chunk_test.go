@@ -0,0 +1,57 @@
+package weightedrand_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkSampler(t *testing.T) {
+	partitions := map[string][]MarbleColor{
+		"small": {Red},
+		"large": {Blue, Blue, Green},
+		"empty": {},
+	}
+	read := func(chunk string, offset int64) (MarbleColor, error) {
+		return partitions[chunk][offset], nil
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewChunkSampler(nil, read, Chunk[string]{Chunk: "empty", Size: 0})
+		})
+		assert.Panics(t, func() {
+			NewChunkSampler(nil, read, Chunk[string]{Chunk: "small", Size: -1})
+		})
+	})
+	t.Run("uniform over records", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		chunks := make([]Chunk[string], 0, len(partitions))
+		for name, records := range partitions {
+			chunks = append(chunks, Chunk[string]{Chunk: name, Size: int64(len(records))})
+		}
+		cs := NewChunkSampler(r, read, chunks...)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			record, err := cs.Next()
+			require.NoError(t, err)
+			counts[record] += 1
+		}
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.50, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.25, float64(counts[Green])/iterations, tolerance, "%s", counts)
+	})
+	t.Run("reader error", func(t *testing.T) {
+		expected := errors.New("unreadable")
+		cs := NewChunkSampler(rand.New(rand.NewSource(1)), func(string, int64) (MarbleColor, error) {
+			return "", expected
+		}, Chunk[string]{Chunk: "small", Size: 1})
+		_, err := cs.Next()
+		assert.ErrorIs(t, err, expected)
+	})
+}
@@ -0,0 +1,19 @@
+package weightedrand
+
+import "time"
+
+// Clock provides the current time to the choosers whose behavior depends on
+// the passage of time. Tests may provide their own implementation to control
+// time, in the same way RandIntN controls randomness.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is a Clock that reports the current system time.
+var SystemClock Clock = systemClock{}
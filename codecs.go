@@ -0,0 +1,107 @@
+package weightedrand
+
+import (
+	"fmt"
+)
+
+// Codec is a named encoder, such as JSON or gob, for the values of round-trip
+// and interoperability tests.
+type Codec[TValue any] struct {
+	// Name identifies the codec, so that the decoder can be matched to it.
+	Name string
+	// Encode encodes the value.
+	Encode func(value TValue) ([]byte, error)
+}
+
+// CodecMix selects a Codec by weight for every value it encodes, so that
+// round-trip tests exercise a mix of encodings, such as 80% JSON, 15% gob
+// and 5% malformed. It implements WeightedRandom, selecting codecs.
+//
+// CodecMix is safe for concurrent use, given that the random number
+// generator is.
+type CodecMix[TValue any] struct {
+	codecs WeightedRandom[Codec[TValue]]
+}
+
+// NewCodecMix constructs a new CodecMix using the Alias Method (Vose's
+// algorithm).
+//
+// The function panics if no codecs are provided, if a codec has no name or
+// no encoder, if names are duplicated, or if weights are negative.
+//
+// Type Parameters:
+//   - TValue:  The type of the values to be encoded.
+//   - TWeight: The type representing the weight of each codec.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - codecs: The WeightedItem values, each containing a codec and its associated weight.
+//
+// Example usage:
+//
+//	jsonCodec := Codec[Order]{Name: "json", Encode: func(o Order) ([]byte, error) { return json.Marshal(o) }}
+//	mix := NewCodecMix(randSource,
+//		WeightedItem[Codec[Order], int]{Item: jsonCodec, Weight: 80},
+//		WeightedItem[Codec[Order], int]{Item: gobCodec, Weight: 15},
+//		WeightedItem[Codec[Order], int]{Item: MalformedCodec(randSource, jsonCodec), Weight: 5},
+//	)
+//	name, data, err := mix.Encode(order)
+func NewCodecMix[TValue any, TWeight Weight](random RandIntN, codecs ...WeightedItem[Codec[TValue], TWeight]) *CodecMix[TValue] {
+	names := make(map[string]struct{}, len(codecs))
+	for _, codec := range codecs {
+		if codec.Item.Name == "" {
+			panic("every codec must have a name")
+		} else if codec.Item.Encode == nil {
+			panic(fmt.Sprintf("codec %q must have an encoder", codec.Item.Name))
+		} else if _, ok := names[codec.Item.Name]; ok {
+			panic(fmt.Sprintf("codec %q was provided more than once", codec.Item.Name))
+		}
+		names[codec.Item.Name] = struct{}{}
+	}
+	return &CodecMix[TValue]{
+		codecs: NewAliasVoseMethod(random, codecs...),
+	}
+}
+
+// Next selects a codec by weight.
+func (mix *CodecMix[TValue]) Next() Codec[TValue] {
+	return mix.codecs.Next()
+}
+
+// Encode encodes the value with a codec selected by weight, and returns the
+// name of the codec with the encoded value, or the error of the codec.
+func (mix *CodecMix[TValue]) Encode(value TValue) (string, []byte, error) {
+	codec := mix.Next()
+	data, err := codec.Encode(value)
+	if err != nil {
+		return codec.Name, nil, fmt.Errorf("could not encode with codec %q: %w", codec.Name, err)
+	}
+	return codec.Name, data, nil
+}
+
+// MalformedCodec wraps the codec to corrupt every value it encodes, either
+// by truncating it or by flipping the bits of one of its bytes, for testing
+// decoders with malformed input. Not every corrupted value is invalid, as a
+// flipped byte within a JSON string still decodes. Its name is the name of
+// the codec, prefixed by "malformed-".
+//
+// Example usage:
+//
+//	malformed := MalformedCodec(randSource, jsonCodec) // named "malformed-json"
+func MalformedCodec[TValue any](random RandIntN, codec Codec[TValue]) Codec[TValue] {
+	return Codec[TValue]{
+		Name: "malformed-" + codec.Name,
+		Encode: func(value TValue) ([]byte, error) {
+			data, err := codec.Encode(value)
+			if err != nil || len(data) == 0 {
+				return data, err
+			}
+			if random.Intn(2) == 0 {
+				return data[:random.Intn(len(data))], nil
+			}
+			index := random.Intn(len(data))
+			data[index] = ^data[index]
+			return data, nil
+		},
+	}
+}
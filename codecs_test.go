@@ -0,0 +1,77 @@
+package weightedrand_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecMix(t *testing.T) {
+	jsonCodec := Codec[int]{Name: "json", Encode: func(value int) ([]byte, error) {
+		return json.Marshal(map[string]int{"value": value})
+	}}
+	textCodec := Codec[int]{Name: "text", Encode: func(value int) ([]byte, error) {
+		return []byte(strconv.Itoa(value)), nil
+	}}
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "every codec must have a name", func() {
+			NewCodecMix[int](nil, WeightedItem[Codec[int], int]{Item: Codec[int]{Encode: textCodec.Encode}})
+		})
+		assert.PanicsWithValue(t, `codec "text" must have an encoder`, func() {
+			NewCodecMix[int](nil, WeightedItem[Codec[int], int]{Item: Codec[int]{Name: "text"}})
+		})
+		assert.PanicsWithValue(t, `codec "text" was provided more than once`, func() {
+			NewCodecMix[int](nil, WeightedItem[Codec[int], int]{Item: textCodec}, WeightedItem[Codec[int], int]{Item: textCodec})
+		})
+		assert.Panics(t, func() {
+			NewCodecMix[int, int](nil)
+		})
+	})
+	t.Run("mix", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		mix := NewCodecMix(r,
+			WeightedItem[Codec[int], int]{Item: jsonCodec, Weight: 80},
+			WeightedItem[Codec[int], int]{Item: textCodec, Weight: 15},
+			WeightedItem[Codec[int], int]{Item: MalformedCodec(r, jsonCodec), Weight: 5},
+		)
+		counts := map[string]int{}
+		for i := range 100_000 {
+			name, data, err := mix.Encode(i)
+			require.NoError(t, err)
+			counts[name]++
+			var decoded map[string]int
+			switch name {
+			case "json":
+				require.NoError(t, json.Unmarshal(data, &decoded))
+				assert.Equal(t, i, decoded["value"])
+			case "text":
+				assert.Equal(t, strconv.Itoa(i), string(data))
+			case "malformed-json":
+				wellFormed, _ := jsonCodec.Encode(i)
+				assert.NotEqual(t, wellFormed, data)
+			default:
+				assert.Fail(t, "unknown codec", name)
+			}
+		}
+		assert.InDeltaf(t, 0.8, float64(counts["json"])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.05, float64(counts["malformed-json"])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("encode error", func(t *testing.T) {
+		failure := errors.New("failure")
+		mix := NewCodecMix(rand.New(rand.NewSource(1)), WeightedItem[Codec[int], int]{Item: Codec[int]{
+			Name:   "failing",
+			Encode: func(int) ([]byte, error) { return nil, failure },
+		}})
+		name, data, err := mix.Encode(1)
+		assert.Equal(t, "failing", name)
+		assert.Nil(t, data)
+		assert.ErrorIs(t, err, failure)
+	})
+}
@@ -0,0 +1,102 @@
+package weightedrand
+
+import (
+	"fmt"
+)
+
+// Column is a read-only, indexable column of values. It is satisfied by the
+// typed arrays of Apache Arrow (such as *array.String and *array.Int64),
+// which also back the records read from Arrow IPC streams and Parquet files,
+// so that a chooser can be built from a column pair without converting it
+// into Go slices first.
+type Column[TValue any] interface {
+	Len() int
+	Value(i int) TValue
+}
+
+// nullableColumn is implemented by columns that may contain nulls, such as
+// the arrays of Apache Arrow.
+type nullableColumn interface {
+	IsNull(i int) bool
+}
+
+// NewFromColumns constructs a new WeightedRandom instance using the Alias
+// Method (Vose's algorithm) from a pair of columns, where row i has the item
+// items.Value(i) and the weight weights.Value(i). Rows where either column
+// is null are skipped, if the columns report nulls with an IsNull method.
+//
+// The function panics if the columns have different lengths, if no rows
+// remain, or if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - items:   The column of items.
+//   - weights: The column of weights.
+//
+// Example usage:
+//
+//	record := reader.Record()
+//	wr := NewFromColumns[string, int64](randSource,
+//		record.Column(0).(*array.String),
+//		record.Column(1).(*array.Int64),
+//	)
+func NewFromColumns[TItem any, TWeight Weight](random RandIntN, items Column[TItem], weights Column[TWeight]) WeightedRandom[TItem] {
+	if items.Len() != weights.Len() {
+		panic(fmt.Sprintf("%d items were provided with %d weights", items.Len(), weights.Len()))
+	}
+	itemNulls, _ := items.(nullableColumn)
+	weightNulls, _ := weights.(nullableColumn)
+	buffer := make([]weightedItem[TItem], 0, items.Len())
+	for i := range items.Len() {
+		if (itemNulls != nil && itemNulls.IsNull(i)) || (weightNulls != nil && weightNulls.IsNull(i)) {
+			continue
+		}
+		buffer = append(buffer, weightedItem[TItem]{
+			Item:   items.Value(i),
+			Weight: effectiveWeight(weights.Value(i)),
+		})
+	}
+	if len(buffer) == 0 {
+		panic("at least one item must be provided")
+	}
+	return newVoseAliasMethodFromDecimals(random, buffer)
+}
+
+// sliceColumn is a Column backed by a slice.
+type sliceColumn[TValue any] []TValue
+
+func (column sliceColumn[TValue]) Len() int {
+	return len(column)
+}
+
+func (column sliceColumn[TValue]) Value(i int) TValue {
+	return column[i]
+}
+
+// NewFromSlices constructs a new WeightedRandom instance using the Alias
+// Method (Vose's algorithm) from a pair of parallel slices, where items[i]
+// has the weight weights[i], like NewFromColumns, without zipping them into
+// WeightedItem values first.
+//
+// The function panics if the slices have different lengths, if they are
+// empty, or if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - items:   The items.
+//   - weights: The weights of the items, in the same order.
+//
+// Example usage:
+//
+//	wr := NewFromSlices(randSource, []string{"A", "B"}, []int{2, 3})
+func NewFromSlices[TItem any, TWeight Weight](random RandIntN, items []TItem, weights []TWeight) WeightedRandom[TItem] {
+	return NewFromColumns[TItem, TWeight](random, sliceColumn[TItem](items), sliceColumn[TWeight](weights))
+}
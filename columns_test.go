@@ -0,0 +1,77 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureColumn mimics the typed arrays of Apache Arrow, where a nil value
+// is null.
+type fixtureColumn[TValue any] []*TValue
+
+func (column fixtureColumn[TValue]) Len() int {
+	return len(column)
+}
+
+func (column fixtureColumn[TValue]) Value(i int) TValue {
+	if column[i] == nil {
+		var zero TValue
+		return zero
+	}
+	return *column[i]
+}
+
+func (column fixtureColumn[TValue]) IsNull(i int) bool {
+	return column[i] == nil
+}
+
+func pointer[TValue any](value TValue) *TValue {
+	return &value
+}
+
+func TestNewFromColumns(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewFromColumns[MarbleColor, int64](nil, fixtureColumn[MarbleColor]{pointer(Red)}, fixtureColumn[int64]{})
+		})
+		assert.Panics(t, func() {
+			NewFromColumns[MarbleColor, int64](nil, fixtureColumn[MarbleColor]{pointer(Red)}, fixtureColumn[int64]{nil})
+		})
+	})
+	t.Run("skips nulls", func(t *testing.T) {
+		items := fixtureColumn[MarbleColor]{pointer(Red), pointer(Green), nil, pointer(Blue)}
+		weights := fixtureColumn[int64]{pointer[int64](1), nil, pointer[int64](100), pointer[int64](3)}
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromColumns[MarbleColor, int64](r, items, weights)
+		counts := Counts(wr, 100_000)
+		assert.Zero(t, counts[Green])
+		assert.Zero(t, counts[""])
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+}
+
+func TestNewFromSlices(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "2 items were provided with 1 weights", func() {
+			NewFromSlices(nil, []MarbleColor{Red, Blue}, []int{1})
+		})
+		assert.Panics(t, func() {
+			NewFromSlices[MarbleColor, int](nil, nil, nil)
+		})
+		assert.Panics(t, func() {
+			NewFromSlices(nil, []MarbleColor{Red}, []int{-1})
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromSlices(r, []MarbleColor{Red, Blue}, []int{1, 3})
+		counts := Counts(wr, 100_000)
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+}
@@ -0,0 +1,100 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Conditional is a two-stage sampler: it first selects a value of type A
+// from one weighted table, and then selects a value of type B from the
+// weighted table that belongs to the chosen A. A typical use is choosing a
+// region and then a datacenter within that region.
+type Conditional[A comparable, B comparable] struct {
+	first               WeightedRandom[A]
+	second              map[A]WeightedRandom[B]
+	firstProbability    map[A]decimal.Decimal
+	secondProbabilities map[A]map[B]decimal.Decimal
+}
+
+// NewConditional constructs a new Conditional sampler. The first stage is
+// built from the first items, and every item of the first stage must have a
+// table of second stage items associated with it. Both stages use Vose's
+// alias method and share the same random number generator.
+//
+// The function panics if no items are provided for either stage, if a first
+// stage item has no second stage items, or if any weight is negative.
+//
+// Type Parameters:
+//   - A:       The type of the items selected by the first stage.
+//   - B:       The type of the items selected by the second stage.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - first:  The WeightedItem values of the first stage.
+//   - second: The WeightedItem values of the second stage, keyed by the first stage item they belong to.
+//
+// Example usage:
+//
+//	c := NewConditional(randSource,
+//		[]WeightedItem[string, int]{{Item: "us", Weight: 3}, {Item: "eu", Weight: 1}},
+//		map[string][]WeightedItem[string, int]{
+//			"us": {{Item: "us-east", Weight: 1}, {Item: "us-west", Weight: 1}},
+//			"eu": {{Item: "eu-central", Weight: 1}},
+//		},
+//	)
+//	region, datacenter := c.Next()
+func NewConditional[A comparable, B comparable, TWeight Weight](
+	random RandIntN, first []WeightedItem[A, TWeight], second map[A][]WeightedItem[B, TWeight],
+) *Conditional[A, B] {
+	conditional := &Conditional[A, B]{
+		first:               NewAliasVoseMethod(random, first...),
+		second:              make(map[A]WeightedRandom[B], len(first)),
+		firstProbability:    make(map[A]decimal.Decimal, len(first)),
+		secondProbabilities: make(map[A]map[B]decimal.Decimal, len(first)),
+	}
+	for _, item := range normalizedItems(first) {
+		conditional.firstProbability[item.Item] = conditional.firstProbability[item.Item].Add(item.Weight)
+		if _, ok := conditional.second[item.Item]; ok {
+			continue
+		}
+		secondItems, ok := second[item.Item]
+		if !ok || len(secondItems) == 0 {
+			panic(fmt.Sprintf("no second stage items were provided for %v", item.Item))
+		}
+		conditional.second[item.Item] = NewAliasVoseMethod(random, secondItems...)
+		probabilities := make(map[B]decimal.Decimal, len(secondItems))
+		for _, secondItem := range normalizedItems(secondItems) {
+			probabilities[secondItem.Item] = probabilities[secondItem.Item].Add(secondItem.Weight)
+		}
+		conditional.secondProbabilities[item.Item] = probabilities
+	}
+	return conditional
+}
+
+// Next selects the first stage item, and then the second stage item from
+// the table belonging to it.
+func (conditional *Conditional[A, B]) Next() (A, B) {
+	a := conditional.first.Next()
+	return a, conditional.second[a].Next()
+}
+
+// Probability reports the combined probability of selecting a and then b,
+// which is the product of the first stage probability of a and the
+// probability of b within the table of a. Unknown pairs have a probability
+// of zero.
+func (conditional *Conditional[A, B]) Probability(a A, b B) decimal.Decimal {
+	return conditional.ProbabilityOf(a).Mul(conditional.ConditionalProbability(a, b))
+}
+
+// ProbabilityOf reports the probability of the first stage selecting a.
+func (conditional *Conditional[A, B]) ProbabilityOf(a A) decimal.Decimal {
+	return conditional.firstProbability[a]
+}
+
+// ConditionalProbability reports the probability of the second stage
+// selecting b, given that the first stage selected a.
+func (conditional *Conditional[A, B]) ConditionalProbability(a A, b B) decimal.Decimal {
+	return conditional.secondProbabilities[a][b]
+}
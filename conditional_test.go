@@ -0,0 +1,47 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditional(t *testing.T) {
+	first := []WeightedItem[string, int]{
+		{Item: "us", Weight: 3},
+		{Item: "eu", Weight: 1},
+	}
+	second := map[string][]WeightedItem[MarbleColor, int]{
+		"us": {{Item: Red, Weight: 1}, {Item: Blue, Weight: 1}},
+		"eu": {{Item: Green, Weight: 1}},
+	}
+	t.Run("panic", func(t *testing.T) {
+		t.Run("missing second stage", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewConditional(nil, first, map[string][]WeightedItem[MarbleColor, int]{
+					"us": second["us"],
+				})
+			})
+		})
+	})
+	t.Run("probability", func(t *testing.T) {
+		c := NewConditional(nil, first, second)
+		assert.Equal(t, "0.375", c.Probability("us", Red).String())
+		assert.Equal(t, "0.25", c.Probability("eu", Green).String())
+		assert.Equal(t, "0", c.Probability("eu", Red).String())
+		assert.Equal(t, "0.75", c.ProbabilityOf("us").String())
+		assert.Equal(t, "0.5", c.ConditionalProbability("us", Blue).String())
+	})
+	t.Run("pairs belong to their first stage", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		c := NewConditional(r, first, second)
+		for range 1_000 {
+			region, color := c.Next()
+			assert.Containsf(t, second[region], WeightedItem[MarbleColor, int]{Item: color, Weight: 1},
+				"%s was selected for region %s", color, region)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// weightOverridesKey is the context key of the weight overrides of items of
+// type TItem.
+type weightOverridesKey[TItem comparable] struct{}
+
+// WithWeightOverrides returns a copy of the context carrying weight
+// multipliers for items, which are honored by Contextual.NextCtx. A
+// multiplier of zero excludes the item, and items without a multiplier keep
+// their weight. If the context already carries overrides for the same item
+// type, the multipliers are combined by multiplying them, so that
+// middlewares can each contribute their own.
+//
+// Panics:
+//   - If any multiplier is negative.
+//
+// Example usage:
+//
+//	ctx = WithWeightOverrides(ctx, map[string]decimal.Decimal{"beta-feature": decimal.NewFromInt(3)})
+func WithWeightOverrides[TItem comparable](ctx context.Context, overrides map[TItem]decimal.Decimal) context.Context {
+	combined := make(map[TItem]decimal.Decimal, len(overrides))
+	if existing, ok := ctx.Value(weightOverridesKey[TItem]{}).(map[TItem]decimal.Decimal); ok {
+		maps.Copy(combined, existing)
+	}
+	for item, multiplier := range overrides {
+		if multiplier.IsNegative() {
+			panic(fmt.Sprintf("multiplier must be non-negative value, but was %s", multiplier.String()))
+		}
+		if existing, ok := combined[item]; ok {
+			multiplier = existing.Mul(multiplier)
+		}
+		combined[item] = multiplier
+	}
+	return context.WithValue(ctx, weightOverridesKey[TItem]{}, combined)
+}
+
+// Contextual is a WeightedRandom whose distribution can be adjusted per call
+// by weight overrides carried in a context. Selections without overrides use
+// an alias table in constant time; selections with overrides make a single
+// linear pass over the items, as the adjusted distribution is only used
+// once.
+//
+// Contextual is safe for concurrent use, given that the random number
+// generator is.
+type Contextual[TItem comparable] struct {
+	mutex       sync.Mutex
+	random      RandIntN
+	items       []weightedItem[TItem]
+	aliasMethod voseAliasMethodRandom[TItem]
+}
+
+// NewContextual constructs a new Contextual instance.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be selected.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its weight.
+//
+// Example usage:
+//
+//	c := NewContextual(randSource, WeightedItem[string, int]{Item: "control", Weight: 9}, WeightedItem[string, int]{Item: "beta-feature", Weight: 1})
+//	variant := c.NextCtx(ctx)
+func NewContextual[TItem comparable, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) *Contextual[TItem] {
+	aliasMethod := newVoseAliasMethod(random, items)
+	contextual := &Contextual[TItem]{
+		random:      random,
+		items:       make([]weightedItem[TItem], 0, len(items)),
+		aliasMethod: aliasMethod,
+	}
+	for _, item := range items {
+		contextual.items = append(contextual.items, weightedItem[TItem]{
+			Item:   item.Item,
+			Weight: effectiveWeight(item.Weight),
+		})
+	}
+	return contextual
+}
+
+// Next selects an item by weight, without any overrides.
+func (contextual *Contextual[TItem]) Next() TItem {
+	contextual.mutex.Lock()
+	defer contextual.mutex.Unlock()
+	return contextual.aliasMethod.Next()
+}
+
+// NextCtx selects an item by weight, with the weights multiplied by the
+// overrides carried in the context.
+//
+// Panics:
+//   - If the overrides exclude every item.
+func (contextual *Contextual[TItem]) NextCtx(ctx context.Context) TItem {
+	overrides, ok := ctx.Value(weightOverridesKey[TItem]{}).(map[TItem]decimal.Decimal)
+	if !ok || len(overrides) == 0 {
+		return contextual.Next()
+	}
+	contextual.mutex.Lock()
+	defer contextual.mutex.Unlock()
+	// Weighted reservoir sampling, as in Sample, except that a weight of
+	// zero excludes the item.
+	var selected TItem
+	found := false
+	totalWeight := decimal.Zero
+	for _, item := range contextual.items {
+		weight := item.Weight
+		if multiplier, ok := overrides[item.Item]; ok {
+			weight = weight.Mul(multiplier)
+		}
+		if !weight.IsPositive() {
+			continue
+		}
+		totalWeight = totalWeight.Add(weight)
+		if !found || uniformDecimal(contextual.random).Mul(totalWeight).LessThan(weight) {
+			selected, found = item.Item, true
+		}
+	}
+	if !found {
+		panic("the weight overrides exclude every item")
+	}
+	return selected
+}
@@ -0,0 +1,62 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextual(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	c := NewContextual(r,
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Green, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 2},
+	)
+	countsCtx := func(ctx context.Context) MarbleColorCounts {
+		counts := make(MarbleColorCounts)
+		for range 100_000 {
+			counts[c.NextCtx(ctx)] += 1
+		}
+		return counts
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithWeightOverrides(context.Background(), map[MarbleColor]decimal.Decimal{Red: decimal.NewFromInt(-1)})
+		})
+		ctx := WithWeightOverrides(context.Background(), map[MarbleColor]decimal.Decimal{
+			Red: decimal.Zero, Green: decimal.Zero, Blue: decimal.Zero,
+		})
+		assert.Panics(t, func() {
+			c.NextCtx(ctx)
+		})
+	})
+	t.Run("without overrides", func(t *testing.T) {
+		counts := countsCtx(context.Background())
+		assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+	})
+	t.Run("with overrides", func(t *testing.T) {
+		ctx := WithWeightOverrides(context.Background(), map[MarbleColor]decimal.Decimal{
+			Red:  decimal.NewFromInt(2),
+			Blue: decimal.Zero,
+		})
+		ctx = WithWeightOverrides(ctx, map[MarbleColor]decimal.Decimal{
+			Red: decimal.NewFromInt(3),
+		})
+		counts := countsCtx(ctx)
+		assert.Zero(t, counts[Blue])
+		assert.InDeltaf(t, 6.0/7, float64(counts[Red])/100_000, tolerance, "%s", counts)
+	})
+	t.Run("overrides of other item types are ignored", func(t *testing.T) {
+		ctx := WithWeightOverrides(context.Background(), map[string]decimal.Decimal{
+			string(Blue): decimal.Zero,
+		})
+		counts := countsCtx(ctx)
+		assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+	})
+}
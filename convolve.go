@@ -0,0 +1,45 @@
+package weightedrand
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// Convolve returns a WeightedRandom over the sum of the outcomes of a and
+// b. The distribution of the sum is computed exactly from the distributions
+// of a and b, rather than by sampling them, so the result can itself be
+// convolved, and queried for the probability of each sum. For example,
+// convolving two fair six-sided dice results in the distribution of 2d6,
+// where 7 is six times as likely as 2.
+//
+// The result uses the random number generator of a.
+//
+// Panics:
+//   - If the distribution of a or b cannot be determined, because it was not
+//     constructed by this package.
+//
+// Example usage:
+//
+//	d6 := NewAliasVoseMethod(randSource, faces...)
+//	twoD6 := Convolve(d6, d6)
+//	roll := twoD6.Next()
+func Convolve(a, b WeightedRandom[int]) WeightedRandom[int] {
+	first, second := distributionOf(a), distributionOf(b)
+	firstWeights, secondWeights := mergedWeights(first), mergedWeights(second)
+	sums := make(map[int]decimal.Decimal, len(firstWeights)+len(secondWeights))
+	for x, xWeight := range firstWeights {
+		for y, yWeight := range secondWeights {
+			sums[x+y] = sums[x+y].Add(xWeight.Mul(yWeight))
+		}
+	}
+	items := make([]weightedItem[int], 0, len(sums))
+	for _, sum := range slices.Sorted(maps.Keys(sums)) {
+		items = append(items, weightedItem[int]{
+			Item:   sum,
+			Weight: sums[sum],
+		})
+	}
+	return newVoseAliasMethodFromDecimals(first.randomSource(), items)
+}
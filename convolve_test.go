@@ -0,0 +1,56 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+type opaqueWeightedRandom struct{}
+
+func (opaqueWeightedRandom) Next() int {
+	return 0
+}
+
+func TestConvolve(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	faces := make([]WeightedItem[int, int], 0, 6)
+	for face := 1; face <= 6; face++ {
+		faces = append(faces, WeightedItem[int, int]{Item: face, Weight: 1})
+	}
+	d6 := NewAliasVoseMethod(r, faces...)
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Convolve(d6, opaqueWeightedRandom{})
+		})
+	})
+	t.Run("two dice", func(t *testing.T) {
+		twoD6 := Convolve(d6, d6)
+		const iterations = 360_000
+		counts := make(map[int]int)
+		for range iterations {
+			counts[twoD6.Next()] += 1
+		}
+		assert.Len(t, counts, 11)
+		for sum := 2; sum <= 12; sum++ {
+			ways := 6 - max(sum-7, 7-sum)
+			assert.InDeltaf(t, float64(ways)/36, float64(counts[sum])/iterations, tolerance/5, "%d: %v", sum, counts)
+		}
+	})
+	t.Run("chained with other engines", func(t *testing.T) {
+		coin := NewFloat64AliasMethod(r, []int{0, 1}, []float64{0.5, 0.5})
+		threeD6 := Convolve(Convolve(d6, d6), NewAliasVoseMethodWithOptions(r, faces, WithIntegerEngine()))
+		total := Convolve(threeD6, coin)
+		const iterations = 100_000
+		counts := make(map[int]int)
+		for range iterations {
+			counts[total.Next()] += 1
+		}
+		assert.Len(t, counts, 17)
+		assert.Contains(t, counts, 3)
+		assert.Contains(t, counts, 19)
+	})
+}
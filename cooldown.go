@@ -0,0 +1,121 @@
+package weightedrand
+
+import (
+	"sync"
+	"time"
+)
+
+// Cooldown is a WeightedRandom that temporarily excludes items after they
+// are selected. While an item is cooling down, the remaining items are
+// selected in proportion to their weights. This is useful for frequency
+// capping, such as not showing the same advertisement twice in a row.
+//
+// Cooldown is safe for concurrent use, given that the random number
+// generator is.
+type Cooldown[TItem comparable] struct {
+	mutex     sync.Mutex
+	clock     Clock
+	table     *adjustableTable[TItem]
+	indices   map[TItem][]int
+	durations map[TItem]time.Duration
+	expiries  map[TItem]time.Time
+}
+
+// NewCooldown constructs a new Cooldown instance. No item has a cooldown
+// until one is configured with WithCooldown.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - clock:  A Clock implementation used to determine when cooldowns expire.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	c := NewCooldown(randSource, SystemClock, WeightedItem[string, int]{Item: "A", Weight: 2}, WeightedItem[string, int]{Item: "B", Weight: 3}).
+//		WithCooldown("A", time.Minute)
+func NewCooldown[TItem comparable, TWeight Weight](random RandIntN, clock Clock, items ...WeightedItem[TItem, TWeight]) *Cooldown[TItem] {
+	cooldown := &Cooldown[TItem]{
+		clock:     clock,
+		table:     newAdjustableTable(random, items),
+		indices:   make(map[TItem][]int, len(items)),
+		durations: make(map[TItem]time.Duration),
+		expiries:  make(map[TItem]time.Time),
+	}
+	for index, item := range items {
+		cooldown.indices[item.Item] = append(cooldown.indices[item.Item], index)
+	}
+	return cooldown
+}
+
+// WithCooldown configures the item to be excluded for the duration after
+// each time it is selected. A duration of zero removes the cooldown. It
+// returns the Cooldown to allow chaining.
+func (cooldown *Cooldown[TItem]) WithCooldown(item TItem, duration time.Duration) *Cooldown[TItem] {
+	cooldown.mutex.Lock()
+	defer cooldown.mutex.Unlock()
+	if duration <= 0 {
+		delete(cooldown.durations, item)
+	} else {
+		cooldown.durations[item] = duration
+	}
+	return cooldown
+}
+
+// Next selects an item from the items that are not cooling down.
+//
+// Panics:
+//   - If every item is cooling down. Use TryNext to avoid this.
+func (cooldown *Cooldown[TItem]) Next() TItem {
+	item, ok := cooldown.TryNext()
+	if !ok {
+		panic("every item is cooling down")
+	}
+	return item
+}
+
+// TryNext selects an item from the items that are not cooling down. If
+// every item is cooling down, it returns false.
+func (cooldown *Cooldown[TItem]) TryNext() (TItem, bool) {
+	cooldown.mutex.Lock()
+	defer cooldown.mutex.Unlock()
+	now := cooldown.clock.Now()
+	for item, expiry := range cooldown.expiries {
+		if !now.Before(expiry) {
+			delete(cooldown.expiries, item)
+			for _, index := range cooldown.indices[item] {
+				cooldown.table.restore(index)
+			}
+		}
+	}
+	index, ok := cooldown.table.next()
+	if !ok {
+		var zero TItem
+		return zero, false
+	}
+	item := cooldown.table.item(index)
+	if duration, ok := cooldown.durations[item]; ok {
+		cooldown.expiries[item] = now.Add(duration)
+		for _, index := range cooldown.indices[item] {
+			cooldown.table.exclude(index)
+		}
+	}
+	return item, true
+}
+
+// CoolingDown reports whether the item is currently excluded, and if so,
+// until when.
+func (cooldown *Cooldown[TItem]) CoolingDown(item TItem) (time.Time, bool) {
+	cooldown.mutex.Lock()
+	defer cooldown.mutex.Unlock()
+	expiry, ok := cooldown.expiries[item]
+	if !ok || !cooldown.clock.Now().Before(expiry) {
+		return time.Time{}, false
+	}
+	return expiry, true
+}
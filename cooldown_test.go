@@ -0,0 +1,62 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCooldown(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1_000},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewCooldown[MarbleColor, int](nil, SystemClock)
+		})
+	})
+	t.Run("excluded until expiry", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		c := NewCooldown(r, clock, items...).
+			WithCooldown(Blue, time.Minute)
+		for c.Next() != Blue {
+		}
+		expiry, ok := c.CoolingDown(Blue)
+		assert.True(t, ok)
+		assert.Equal(t, clock.Now().Add(time.Minute), expiry)
+		for range 100 {
+			assert.Equal(t, Red, c.Next())
+		}
+		clock.Advance(time.Minute)
+		_, ok = c.CoolingDown(Blue)
+		assert.False(t, ok)
+		counts := make(MarbleColorCounts)
+		for range 100 {
+			counts[c.Next()] += 1
+		}
+		assert.Equalf(t, int64(1), counts[Blue], "%s", counts)
+	})
+	t.Run("every item cooling down", func(t *testing.T) {
+		clock := NewFixtureClock()
+		c := NewCooldown(rand.New(rand.NewSource(1)), clock, items...).
+			WithCooldown(Red, time.Second).
+			WithCooldown(Blue, time.Second)
+		_, ok := c.TryNext()
+		assert.True(t, ok)
+		_, ok = c.TryNext()
+		assert.True(t, ok)
+		_, ok = c.TryNext()
+		assert.False(t, ok)
+		assert.Panics(t, func() {
+			c.Next()
+		})
+		clock.Advance(time.Second)
+		_, ok = c.TryNext()
+		assert.True(t, ok)
+	})
+}
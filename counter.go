@@ -0,0 +1,126 @@
+package weightedrand
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// CounterBased is a WeightedRandom whose selections are derived from a seed
+// and the index of the selection, rather than from a sequential random
+// number generator. Because selection #n only depends on the seed and n, any
+// selection can be computed directly with NthSelection, and distributed
+// workers sharing a seed agree on every selection without coordination.
+type CounterBased[TItem any] struct {
+	key         uint64
+	counter     atomic.Uint64
+	aliasMethod RandomInjectable[TItem]
+}
+
+// NewCounterBased constructs a new CounterBased instance using the Alias
+// Method (Vose's algorithm), with the randomness of each selection derived
+// from hash(seed, n).
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	cb := NewCounterBased("experiment-7", WeightedItem[string, int]{Item: "A", Weight: 2}, WeightedItem[string, int]{Item: "B", Weight: 3})
+//	first := cb.Next()               // same as cb.NthSelection(0)
+//	thousandth := cb.NthSelection(999)
+func NewCounterBased[TItem any, TWeight Weight](seed string, items ...WeightedItem[TItem, TWeight]) *CounterBased[TItem] {
+	return newCounterBased(seed, newVoseAliasMethod[TItem](nil, items))
+}
+
+// NewCounterBasedWithOptions constructs a new CounterBased instance like
+// NewCounterBased, but with the alias table configured by the options, such
+// as WithExactThresholds to select items with tiny weights exactly as often
+// as their weights warrant.
+//
+// The function panics under the same conditions as
+// NewAliasVoseMethodWithOptions.
+//
+// Example usage:
+//
+//	cb := NewCounterBasedWithOptions("experiment-7", items, WithExactThresholds())
+func NewCounterBasedWithOptions[TItem any, TWeight Weight](seed string, items []WeightedItem[TItem, TWeight], opts ...ItemOption[TItem]) *CounterBased[TItem] {
+	aliasMethod, ok := NewAliasVoseMethodWithOptions(nil, items, opts...).(RandomInjectable[TItem])
+	if !ok {
+		panic("the options do not build a table that can select with another random number generator")
+	}
+	return newCounterBased(seed, aliasMethod)
+}
+
+func newCounterBased[TItem any](seed string, aliasMethod RandomInjectable[TItem]) *CounterBased[TItem] {
+	digest := sha256.Sum256([]byte(seed))
+	return &CounterBased[TItem]{
+		key:         binary.BigEndian.Uint64(digest[:8]),
+		aliasMethod: aliasMethod,
+	}
+}
+
+// Next returns the selection for the current counter, and advances the
+// counter. It is safe for concurrent use.
+func (counterBased *CounterBased[TItem]) Next() TItem {
+	return counterBased.NthSelection(counterBased.counter.Add(1) - 1)
+}
+
+// NthSelection returns the n-th selection (starting at zero) without
+// iterating over, or advancing past, the selections before it.
+func (counterBased *CounterBased[TItem]) NthSelection(n uint64) TItem {
+	return counterBased.aliasMethod.NextUsing(newCounterRandom(counterBased.key, n))
+}
+
+// Counter returns the index of the selection the next call to Next will
+// return.
+func (counterBased *CounterBased[TItem]) Counter() uint64 {
+	return counterBased.counter.Load()
+}
+
+// Seek sets the index of the selection the next call to Next will return.
+func (counterBased *CounterBased[TItem]) Seek(n uint64) {
+	counterBased.counter.Store(n)
+}
+
+// counterRandom is a SplitMix64 generator whose initial state is derived
+// from a key and a counter, so that each counter has an independent stream.
+type counterRandom struct {
+	state uint64
+}
+
+func newCounterRandom(key, counter uint64) *counterRandom {
+	return &counterRandom{
+		state: mix64(key ^ mix64(counter)),
+	}
+}
+
+func (random *counterRandom) next() uint64 {
+	random.state += 0x9e3779b97f4a7c15
+	return mix64(random.state)
+}
+
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (random *counterRandom) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int63n")
+	}
+	// Reject values from the incomplete final interval to avoid modulo bias.
+	max := int64((1 << 63) - 1 - (1<<63)%uint64(n))
+	v := int64(random.next() >> 1)
+	for v > max {
+		v = int64(random.next() >> 1)
+	}
+	return v % n
+}
+
+func (random *counterRandom) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	return int(random.Int63n(int64(n)))
+}
@@ -0,0 +1,53 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterBased(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 3},
+	}
+	t.Run("next matches nth selection", func(t *testing.T) {
+		sequential := NewCounterBased("workers", items...)
+		direct := NewCounterBased("workers", items...)
+		for n := range uint64(200) {
+			assert.Equal(t, direct.NthSelection(n), sequential.Next())
+		}
+		assert.Equal(t, uint64(200), sequential.Counter())
+	})
+	t.Run("seek", func(t *testing.T) {
+		cb := NewCounterBased("workers", items...)
+		expected := cb.NthSelection(1_000_000)
+		cb.Seek(1_000_000)
+		assert.Equal(t, expected, cb.Next())
+	})
+	t.Run("distribution", func(t *testing.T) {
+		cb := NewCounterBased("distribution", items...)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[cb.Next()] += 1
+		}
+		assert.InDeltaf(t, 1.0/6, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 3.0/6, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+	t.Run("options", func(t *testing.T) {
+		cb := NewCounterBasedWithOptions("distribution", items, WithIntegerEngine())
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[cb.Next()] += 1
+		}
+		assert.InDeltaf(t, 1.0/6, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.Equal(t, cb.NthSelection(42), NewCounterBasedWithOptions("distribution", items, WithIntegerEngine()).NthSelection(42))
+		assert.Panics(t, func() {
+			NewCounterBasedWithOptions("distribution", items, WithMaxProbability(FixtureDecimal(t, "0.1")))
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCDFMethod(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "at least one item must be provided", func() {
+			NewCDFMethod[MarbleColor, int](nil)
+		})
+		assert.Panics(t, func() {
+			NewCDFMethod(nil, WeightedItem[MarbleColor, int]{Item: Red, Weight: -1})
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewCDFMethod(r,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Green},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 2},
+		)
+		counts := Counts(wr, 100_000)
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.25, float64(counts[Green])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+		assert.NoError(t, SelfTest(wr, 100_000))
+	})
+	t.Run("cross-check alias method", func(t *testing.T) {
+		items := []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Orange, Weight: 7},
+			{Item: Yellow, Weight: 13},
+			{Item: Green, Weight: 29},
+			{Item: Blue, Weight: 50},
+		}
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		cdf := NewCDFMethod(r, items...)
+		alias := NewAliasVoseMethod(r, items...)
+		assert.Equal(t, Fingerprint(alias), Fingerprint(cdf))
+		cdfCounts, aliasCounts := Counts(cdf, 100_000), Counts(alias, 100_000)
+		for _, item := range items {
+			assert.InDeltaf(t, float64(aliasCounts[item.Item])/100_000, float64(cdfCounts[item.Item])/100_000, tolerance, "%v %v", aliasCounts, cdfCounts)
+		}
+		injectable, ok := cdf.(RandomInjectable[MarbleColor])
+		require.True(t, ok)
+		assert.Equal(t, injectable.NextUsing(rand.New(rand.NewSource(1))), injectable.NextUsing(rand.New(rand.NewSource(1))))
+	})
+}
@@ -0,0 +1,531 @@
+// Package dice parses dice notation, such as "3d6+2" or "2d20 keep highest",
+// into exact distributions of the outcome, and into weighted samplers that
+// roll the expression in constant time.
+//
+// An expression is a sum of terms, separated by + or -. A term is either a
+// constant, or a roll of NdM: N dice (1 if omitted) with M faces numbered 1
+// to M. A roll may keep only its K highest or lowest dice (1 if omitted),
+// written as "4d6kh3", "4d6 keep highest 3", "2d20kl" or
+// "2d20 keep lowest".
+package dice
+
+import (
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// MaxDice is the largest number of dice a single term may roll.
+	MaxDice = 100
+	// MaxFaces is the largest number of faces a die may have.
+	MaxFaces = 1000
+	// MaxComplexity bounds the number of steps needed to compute the
+	// distribution of an expression, so that parsing untrusted input cannot
+	// stall the caller.
+	MaxComplexity = 10_000_000
+)
+
+// term is a single constant or roll of an expression.
+type term struct {
+	negative bool
+	// constant is the value of the term if it has no dice.
+	constant int
+	count    int
+	faces    int
+	keep     int
+	lowest   bool
+}
+
+// span is the number of outcomes between the lowest and highest outcome of
+// the term.
+func (t term) span() int {
+	if t.faces == 0 {
+		return 1
+	}
+	return t.keep*(t.faces-1) + 1
+}
+
+// complexity estimates the number of steps needed to compute the
+// distribution of the term.
+func (t term) complexity() int {
+	if t.faces == 0 {
+		return 1
+	} else if t.keep == t.count {
+		return t.count * t.span()
+	}
+	return t.faces * t.keep * t.count * t.span()
+}
+
+func (t term) String() string {
+	if t.faces == 0 {
+		return strconv.Itoa(t.constant)
+	}
+	notation := fmt.Sprintf("%dd%d", t.count, t.faces)
+	if t.keep == t.count {
+		return notation
+	} else if t.lowest {
+		return fmt.Sprintf("%skl%d", notation, t.keep)
+	}
+	return fmt.Sprintf("%skh%d", notation, t.keep)
+}
+
+// outcomes counts the number of ways every outcome can be rolled, for the
+// consecutive outcomes starting at lowest, and the total number of ways the
+// dice can fall.
+type outcomes struct {
+	lowest int
+	ways   []*big.Int
+	total  *big.Int
+}
+
+// combine counts the ways every sum of the outcomes of both can be rolled.
+func (a outcomes) combine(b outcomes) outcomes {
+	combined := outcomes{
+		lowest: a.lowest + b.lowest,
+		ways:   zeros(len(a.ways) + len(b.ways) - 1),
+		total:  new(big.Int).Mul(a.total, b.total),
+	}
+	product := new(big.Int)
+	for x, xWays := range a.ways {
+		if xWays.Sign() == 0 {
+			continue
+		}
+		for y, yWays := range b.ways {
+			product.Mul(xWays, yWays)
+			combined.ways[x+y].Add(combined.ways[x+y], product)
+		}
+	}
+	return combined
+}
+
+func zeros(n int) []*big.Int {
+	values := make([]big.Int, n)
+	result := make([]*big.Int, n)
+	for i := range values {
+		result[i] = &values[i]
+	}
+	return result
+}
+
+// outcomes counts the number of ways every outcome of the term can be rolled.
+func (t term) outcomes() outcomes {
+	if t.faces == 0 {
+		constant := t.constant
+		if t.negative {
+			constant = -constant
+		}
+		return outcomes{
+			lowest: constant,
+			ways:   []*big.Int{big.NewInt(1)},
+			total:  big.NewInt(1),
+		}
+	}
+	var ways []*big.Int
+	if t.keep == t.count {
+		ways = sums(t.count, t.faces)
+	} else {
+		ways = keptSums(t.count, t.faces, t.keep, t.lowest)
+	}
+	result := outcomes{
+		lowest: t.keep,
+		ways:   ways,
+		total:  new(big.Int).Exp(big.NewInt(int64(t.faces)), big.NewInt(int64(t.count)), nil),
+	}
+	if t.negative {
+		result.lowest = -(t.keep + len(ways) - 1)
+		slices.Reverse(result.ways)
+	}
+	return result
+}
+
+// sums counts the ways the sum of every die can be rolled, with faces
+// numbered from zero. Each die is added by summing the ways over a sliding
+// window of its faces.
+func sums(count, faces int) []*big.Int {
+	ways := []*big.Int{big.NewInt(1)}
+	for range count {
+		next := zeros(len(ways) + faces - 1)
+		window := new(big.Int)
+		for sum := range next {
+			if sum < len(ways) {
+				window.Add(window, ways[sum])
+			}
+			if sum >= faces {
+				window.Sub(window, ways[sum-faces])
+			}
+			next[sum].Set(window)
+		}
+		ways = next
+	}
+	return ways
+}
+
+// keptSums counts the ways the sum of the kept dice can be rolled, with
+// faces numbered from zero. The faces are visited from the first kept to the
+// last, and for each face, every number of the remaining dice showing it is
+// considered: the dice visited first are the ones that are kept, and the
+// number of ways to choose which of the remaining dice show the face is a
+// binomial coefficient. Once every kept die is assigned a face, the
+// remaining dice may show any of the faces not visited yet, without changing
+// the sum.
+func keptSums(count, faces, keep int, lowest bool) []*big.Int {
+	binomials := binomialTable(count)
+	span := keep*(faces-1) + 1
+	counts := zeros(span)
+	// states[n][s] is the number of ways n dice, fewer than the number
+	// kept, can show the faces visited so far with the kept dice summing
+	// to s.
+	states := make([][]*big.Int, keep)
+	states[0] = zeros(span)
+	states[0][0].SetInt64(1)
+	product := new(big.Int)
+	rest := new(big.Int)
+	for step := range faces {
+		face := faces - 1 - step
+		if lowest {
+			face = step
+		}
+		unvisited := big.NewInt(int64(faces - step - 1))
+		next := make([][]*big.Int, keep)
+		for assigned, sums := range states {
+			if sums == nil {
+				continue
+			}
+			remaining := count - assigned
+			for showing := 0; showing <= remaining; showing++ {
+				kept := min(showing, keep-assigned)
+				ways := binomials[remaining][showing]
+				var destination []*big.Int
+				if assigned+showing >= keep {
+					destination = counts
+					rest.Exp(unvisited, big.NewInt(int64(remaining-showing)), nil)
+					if rest.Sign() == 0 {
+						continue
+					}
+					ways = new(big.Int).Mul(ways, rest)
+				} else {
+					if next[assigned+showing] == nil {
+						next[assigned+showing] = zeros(span)
+					}
+					destination = next[assigned+showing]
+				}
+				for sum, sumWays := range sums {
+					if sumWays.Sign() == 0 {
+						continue
+					}
+					product.Mul(sumWays, ways)
+					destination[sum+kept*face].Add(destination[sum+kept*face], product)
+				}
+			}
+		}
+		states = next
+	}
+	return counts
+}
+
+func binomialTable(n int) [][]*big.Int {
+	table := make([][]*big.Int, n+1)
+	for i := range table {
+		table[i] = zeros(i + 1)
+		table[i][0].SetInt64(1)
+		table[i][i].SetInt64(1)
+		for j := 1; j < i; j++ {
+			table[i][j].Add(table[i-1][j-1], table[i-1][j])
+		}
+	}
+	return table
+}
+
+// Expression is a parsed dice expression. It is immutable, and safe for
+// concurrent use.
+type Expression struct {
+	terms []term
+}
+
+// Parse parses an expression in dice notation.
+//
+// Example usage:
+//
+//	expression, err := dice.Parse("4d6 keep highest 3")
+//	if err != nil {
+//		return err
+//	}
+//	roller := expression.Sampler(randSource)
+//	strength := roller.Next()
+func Parse(expression string) (*Expression, error) {
+	p := parser{
+		input: expression,
+	}
+	terms, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("could not parse %q: %w", expression, err)
+	}
+	// Every term is computed on its own, and then combined with the terms
+	// before it.
+	complexity, span := 0, 1
+	for _, t := range terms {
+		complexity += t.complexity() + span*t.span()
+		span += t.span() - 1
+	}
+	if complexity > MaxComplexity {
+		return nil, fmt.Errorf("could not parse %q: the expression is too complex", expression)
+	}
+	return &Expression{
+		terms: terms,
+	}, nil
+}
+
+// MustParse is like Parse, but panics if the expression cannot be parsed. It
+// simplifies the initialization of expressions known at compile time.
+func MustParse(expression string) *Expression {
+	parsed, err := Parse(expression)
+	if err != nil {
+		panic(err.Error())
+	}
+	return parsed
+}
+
+// String returns the expression in canonical dice notation, such as
+// "2d20kh1+3".
+func (expression *Expression) String() string {
+	var builder strings.Builder
+	for i, t := range expression.terms {
+		if t.negative {
+			builder.WriteString("-")
+		} else if i > 0 {
+			builder.WriteString("+")
+		}
+		builder.WriteString(t.String())
+	}
+	return builder.String()
+}
+
+// ways counts the number of ways every outcome of the expression can be
+// rolled, in increasing order of outcome, and the total number of ways the
+// dice can fall. Outcomes that cannot be rolled are omitted.
+func (expression *Expression) ways() ([]weightedrand.WeightedItem[int, decimal.Decimal], decimal.Decimal) {
+	result := outcomes{
+		ways:  []*big.Int{big.NewInt(1)},
+		total: big.NewInt(1),
+	}
+	for _, t := range expression.terms {
+		result = result.combine(t.outcomes())
+	}
+	items := make([]weightedrand.WeightedItem[int, decimal.Decimal], 0, len(result.ways))
+	for offset, ways := range result.ways {
+		if ways.Sign() == 0 {
+			continue
+		}
+		items = append(items, weightedrand.WeightedItem[int, decimal.Decimal]{
+			Item:   result.lowest + offset,
+			Weight: decimal.NewFromBigInt(ways, 0),
+		})
+	}
+	return items, decimal.NewFromBigInt(result.total, 0)
+}
+
+// Probabilities returns the probability of every possible outcome of the
+// expression, in increasing order of outcome. The probabilities are computed
+// from exact counts, and rounded to decimal.DivisionPrecision digits.
+func (expression *Expression) Probabilities() []weightedrand.WeightedItem[int, decimal.Decimal] {
+	probabilities, total := expression.ways()
+	for i := range probabilities {
+		probabilities[i].Weight = probabilities[i].Weight.Div(total)
+	}
+	return probabilities
+}
+
+// samplerBits bounds the total weight of the sampler, so that it fits the
+// integer engine.
+const samplerBits = 62
+
+// Sampler returns a WeightedRandom that rolls the expression, using the
+// exact distribution of its outcomes rather than rolling every die. The
+// table is built by the integer engine from the number of ways every outcome
+// can be rolled, so that even the rarest outcomes are selected with exactly
+// their probability. Only when the dice can fall in more than 2^62 ways are
+// the counts scaled down, which keeps every outcome selectable, but makes
+// the probabilities of the rarest ones approximate.
+func (expression *Expression) Sampler(random weightedrand.RandIntN) weightedrand.WeightedRandom[int] {
+	// The exact counts are used as weights, as the rounded probabilities of
+	// unlikely outcomes may be zero.
+	ways, total := expression.ways()
+	shift := uint(max(0, total.BigInt().BitLen()-samplerBits))
+	items := make([]weightedrand.WeightedItem[int, uint64], len(ways))
+	for i, outcome := range ways {
+		weight := new(big.Int).Rsh(outcome.Weight.BigInt(), shift)
+		items[i] = weightedrand.WeightedItem[int, uint64]{
+			Item: outcome.Item,
+			// Every outcome that can be rolled remains selectable.
+			Weight: max(weight.Uint64(), 1),
+		}
+	}
+	return weightedrand.NewAliasVoseMethodWithOptions(random, items, weightedrand.WithIntegerEngine())
+}
+
+// Roll rolls every die of the expression, and returns the outcome.
+func (expression *Expression) Roll(random weightedrand.RandIntN) int {
+	outcome := 0
+	for _, t := range expression.terms {
+		value := t.constant
+		if t.faces > 0 {
+			rolls := make([]int, t.count)
+			for i := range rolls {
+				rolls[i] = random.Intn(t.faces) + 1
+			}
+			slices.Sort(rolls)
+			if !t.lowest {
+				slices.Reverse(rolls)
+			}
+			value = 0
+			for _, roll := range rolls[:t.keep] {
+				value += roll
+			}
+		}
+		if t.negative {
+			value = -value
+		}
+		outcome += value
+	}
+	return outcome
+}
+
+type parser struct {
+	input    string
+	position int
+}
+
+func (p *parser) parse() ([]term, error) {
+	var terms []term
+	for {
+		p.skipSpaces()
+		negative := false
+		if p.consume("-") {
+			negative = true
+		} else if len(terms) > 0 && !p.consume("+") {
+			return nil, p.unexpected("+ or -")
+		}
+		p.skipSpaces()
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		t.negative = negative
+		terms = append(terms, t)
+		p.skipSpaces()
+		if p.position == len(p.input) {
+			return terms, nil
+		}
+	}
+}
+
+func (p *parser) term() (term, error) {
+	number, hasNumber, err := p.number()
+	if err != nil {
+		return term{}, err
+	}
+	if !p.consume("d") {
+		if !hasNumber {
+			return term{}, p.unexpected("a number or dice")
+		}
+		return term{constant: number}, nil
+	}
+	t := term{count: 1, keep: 1}
+	if hasNumber {
+		t.count = number
+	}
+	faces, hasFaces, err := p.number()
+	if err != nil {
+		return term{}, err
+	} else if !hasFaces {
+		return term{}, p.unexpected("the number of faces")
+	}
+	t.faces = faces
+	if t.count < 1 || t.count > MaxDice {
+		return term{}, fmt.Errorf("the number of dice must be between 1 and %d, but was %d", MaxDice, t.count)
+	} else if t.faces < 1 || t.faces > MaxFaces {
+		return term{}, fmt.Errorf("the number of faces must be between 1 and %d, but was %d", MaxFaces, t.faces)
+	}
+	keep, err := p.keep(&t)
+	if err != nil {
+		return term{}, err
+	} else if !keep {
+		t.keep = t.count
+	} else if t.keep < 1 || t.keep > t.count {
+		return term{}, fmt.Errorf("the number of dice kept must be between 1 and %d, but was %d", t.count, t.keep)
+	}
+	return t, nil
+}
+
+// keep parses the optional modifier keeping the highest or lowest dice of a
+// roll, and reports whether it was present.
+func (p *parser) keep(t *term) (bool, error) {
+	start := p.position
+	p.skipSpaces()
+	switch {
+	case p.consume("kh"):
+	case p.consume("kl"):
+		t.lowest = true
+	case p.consume("keep"):
+		p.skipSpaces()
+		if p.consume("lowest") {
+			t.lowest = true
+		} else if !p.consume("highest") {
+			return false, p.unexpected("highest or lowest")
+		}
+		p.skipSpaces()
+	default:
+		p.position = start
+		return false, nil
+	}
+	keep, hasKeep, err := p.number()
+	if err != nil {
+		return false, err
+	} else if hasKeep {
+		t.keep = keep
+	}
+	return true, nil
+}
+
+func (p *parser) number() (int, bool, error) {
+	start := p.position
+	for p.position < len(p.input) && p.input[p.position] >= '0' && p.input[p.position] <= '9' {
+		p.position++
+	}
+	if start == p.position {
+		return 0, false, nil
+	}
+	number, err := strconv.Atoi(p.input[start:p.position])
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid number %q at offset %d", p.input[start:p.position], start)
+	}
+	return number, true, nil
+}
+
+func (p *parser) consume(token string) bool {
+	end := p.position + len(token)
+	if end <= len(p.input) && strings.EqualFold(p.input[p.position:end], token) {
+		p.position = end
+		return true
+	}
+	return false
+}
+
+func (p *parser) skipSpaces() {
+	for p.position < len(p.input) && p.input[p.position] == ' ' {
+		p.position++
+	}
+}
+
+func (p *parser) unexpected(expected string) error {
+	if p.position == len(p.input) {
+		return fmt.Errorf("expected %s at the end of the expression", expected)
+	}
+	return fmt.Errorf("expected %s at offset %d, but found %q", expected, p.position, p.input[p.position:p.position+1])
+}
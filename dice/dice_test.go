@@ -0,0 +1,177 @@
+package dice_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand/dice"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enumerate counts the outcomes of keeping the highest or lowest dice of
+// every possible roll.
+func enumerate(count, faces, keep int, lowest bool) map[int]int {
+	counts := make(map[int]int)
+	rolls := make([]int, count)
+	var visit func(int)
+	visit = func(die int) {
+		if die == count {
+			sorted := slices.Clone(rolls)
+			slices.Sort(sorted)
+			if !lowest {
+				slices.Reverse(sorted)
+			}
+			sum := 0
+			for _, roll := range sorted[:keep] {
+				sum += roll
+			}
+			counts[sum] += 1
+			return
+		}
+		for face := 1; face <= faces; face++ {
+			rolls[die] = face
+			visit(die + 1)
+		}
+	}
+	visit(0)
+	return counts
+}
+
+func TestParse(t *testing.T) {
+	t.Run("canonical form", func(t *testing.T) {
+		for expression, expected := range map[string]string{
+			"3d6+2":                   "3d6+2",
+			"d20":                     "1d20",
+			"2d20 keep highest":       "2d20kh1",
+			"2D20 Keep Lowest":        "2d20kl1",
+			"4d6 keep highest 3 - 1":  "4d6kh3-1",
+			" -1d4 + 2d8kl1 + 4d6kh4": "-1d4+2d8kl1+4d6",
+			"5":                       "5",
+		} {
+			parsed, err := dice.Parse(expression)
+			if assert.NoError(t, err, expression) {
+				assert.Equal(t, expected, parsed.String(), expression)
+			}
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		for _, expression := range []string{
+			"",
+			"3d",
+			"d",
+			"3d6 +",
+			"3d6 2",
+			"3d6 keep most",
+			"0d6",
+			"3d0",
+			"101d6",
+			"1d1001",
+			"100d100kh50",
+			"100d100+100d100",
+			"2d6kh3",
+			"2d6kh0",
+			"99999999999999999999d6",
+			"3d6 × 2",
+		} {
+			_, err := dice.Parse(expression)
+			assert.Error(t, err, expression)
+		}
+		assert.Panics(t, func() {
+			dice.MustParse("3d")
+		})
+	})
+}
+
+func TestProbabilities(t *testing.T) {
+	t.Run("sum", func(t *testing.T) {
+		probabilities := dice.MustParse("2d6+1").Probabilities()
+		require.Len(t, probabilities, 11)
+		assert.Equal(t, 3, probabilities[0].Item)
+		assert.Equal(t, 13, probabilities[10].Item)
+		assert.True(t, decimal.NewFromInt(6).Div(decimal.NewFromInt(36)).Equal(probabilities[5].Weight), probabilities[5].Weight)
+	})
+	t.Run("keep matches enumeration", func(t *testing.T) {
+		for _, expression := range []string{"4d6kh3", "4d6kl3", "2d20kh1", "2d20kl1", "5d4kh2", "3d6kh3"} {
+			parsed := dice.MustParse(expression)
+			var count, faces, keep int
+			lowest := false
+			switch expression {
+			case "4d6kh3":
+				count, faces, keep = 4, 6, 3
+			case "4d6kl3":
+				count, faces, keep, lowest = 4, 6, 3, true
+			case "2d20kh1":
+				count, faces, keep = 2, 20, 1
+			case "2d20kl1":
+				count, faces, keep, lowest = 2, 20, 1, true
+			case "5d4kh2":
+				count, faces, keep = 5, 4, 2
+			case "3d6kh3":
+				count, faces, keep = 3, 6, 3
+			}
+			expected := enumerate(count, faces, keep, lowest)
+			total := 0
+			for _, ways := range expected {
+				total += ways
+			}
+			probabilities := parsed.Probabilities()
+			assert.Len(t, probabilities, len(expected), expression)
+			for _, probability := range probabilities {
+				want := decimal.NewFromInt(int64(expected[probability.Item])).Div(decimal.NewFromInt(int64(total)))
+				assert.Truef(t, want.Equal(probability.Weight), "%s: %d: %s != %s", expression, probability.Item, want, probability.Weight)
+			}
+		}
+	})
+	t.Run("subtraction", func(t *testing.T) {
+		probabilities := dice.MustParse("1d4-1d4").Probabilities()
+		require.Len(t, probabilities, 7)
+		assert.Equal(t, -3, probabilities[0].Item)
+		assert.Equal(t, 3, probabilities[6].Item)
+	})
+	t.Run("large expressions", func(t *testing.T) {
+		probabilities := dice.MustParse("20d20 + 10d20kh5").Probabilities()
+		assert.Equal(t, 25, probabilities[0].Item)
+		assert.Equal(t, 500, probabilities[len(probabilities)-1].Item)
+	})
+}
+
+func TestSampler(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	const iterations = 100_000
+	expression := dice.MustParse("2d20 keep highest")
+	sampled := make(map[int]int)
+	rolled := make(map[int]int)
+	sampler := expression.Sampler(r)
+	for range iterations {
+		sampled[sampler.Next()] += 1
+		rolled[expression.Roll(r)] += 1
+	}
+	for _, probability := range expression.Probabilities() {
+		assert.InDelta(t, probability.Weight.InexactFloat64(), float64(sampled[probability.Item])/iterations, 0.01, probability.Item)
+		assert.InDelta(t, probability.Weight.InexactFloat64(), float64(rolled[probability.Item])/iterations, 0.01, probability.Item)
+	}
+}
+
+func TestSamplerRareOutcomes(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	const iterations = 1_000_000
+	sampler := dice.MustParse("3d6").Sampler(r)
+	lowest := 0
+	for range iterations {
+		if sampler.Next() == 3 {
+			lowest++
+		}
+	}
+	// Three ones are rolled 1 in 216 times, about 0.00463, which a coin
+	// toss of 1/100 resolution would overweight to about 0.005.
+	assert.InDelta(t, 1.0/216, float64(lowest)/iterations, 0.0002)
+
+	large := dice.MustParse("20d20 + 10d20kh5").Sampler(r)
+	for range 1_000 {
+		assert.GreaterOrEqual(t, large.Next(), 25)
+	}
+}
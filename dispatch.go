@@ -0,0 +1,159 @@
+package weightedrand
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Handler is a function selected and called by a SafeDispatcher.
+type Handler[TArg any, TResult any] func(TArg) (TResult, error)
+
+// PanicError reports that a handler called by a SafeDispatcher panicked.
+type PanicError struct {
+	// Value is the value the handler panicked with.
+	Value any
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", err.Value)
+}
+
+// Unwrap returns the value the handler panicked with, if it is an error.
+func (err *PanicError) Unwrap() error {
+	if cause, ok := err.Value.(error); ok {
+		return cause
+	}
+	return nil
+}
+
+// SafeDispatcher selects a handler by weight and calls it, recovering from
+// any panic. If the handler panics, the draw is repeated over the handlers
+// that have not panicked yet, up to a bounded number of attempts, which
+// keeps a plugin system running when one of its weighted providers is
+// faulty.
+//
+// Only panics cause a retry: errors returned by a handler are returned to
+// the caller as is.
+//
+// SafeDispatcher is safe for concurrent use, given that the random number
+// generator and the handlers are.
+type SafeDispatcher[TArg any, TResult any] struct {
+	mutex       sync.Mutex
+	random      RandIntN
+	handlers    []Handler[TArg, TResult]
+	aliasMethod voseAliasMethodRandom[int]
+	maxAttempts int
+}
+
+// NewSafeDispatcher constructs a new SafeDispatcher, which makes up to three
+// attempts by default.
+//
+// The function panics if no handlers are provided, weights are negative, or
+// a handler is nil.
+//
+// Type Parameters:
+//   - TArg:    The type of the argument passed to the handlers.
+//   - TResult: The type of the result returned by the handlers.
+//   - TWeight: The type representing the weight of each handler.
+//
+// Parameters:
+//   - random:   A RandIntN implementation used for random number generation.
+//   - handlers: A variadic list of WeightedItem values, each containing a handler and its weight.
+//
+// Example usage:
+//
+//	d := NewSafeDispatcher(randSource,
+//		WeightedItem[Handler[Request, Response], int]{Item: stableProvider, Weight: 9},
+//		WeightedItem[Handler[Request, Response], int]{Item: pluginProvider, Weight: 1},
+//	).WithMaxAttempts(2)
+//	response, err := d.Dispatch(request)
+func NewSafeDispatcher[TArg any, TResult any, TWeight Weight](random RandIntN, handlers ...WeightedItem[Handler[TArg, TResult], TWeight]) *SafeDispatcher[TArg, TResult] {
+	if len(handlers) == 0 {
+		panic("at least one item must be provided")
+	}
+	dispatcher := &SafeDispatcher[TArg, TResult]{
+		random:      random,
+		handlers:    make([]Handler[TArg, TResult], 0, len(handlers)),
+		maxAttempts: 3,
+	}
+	indices := make([]weightedItem[int], 0, len(handlers))
+	for index, handler := range handlers {
+		if handler.Item == nil {
+			panic(fmt.Sprintf("handler at index %d must not be nil", index))
+		}
+		dispatcher.handlers = append(dispatcher.handlers, handler.Item)
+		indices = append(indices, weightedItem[int]{
+			Item:   index,
+			Weight: effectiveWeight(handler.Weight),
+		})
+	}
+	dispatcher.aliasMethod = newVoseAliasMethodFromDecimals(random, indices)
+	return dispatcher
+}
+
+// WithMaxAttempts sets the maximum number of handlers called by a single
+// dispatch. It returns the SafeDispatcher to allow chaining.
+//
+// Panics:
+//   - If maxAttempts is not positive.
+func (dispatcher *SafeDispatcher[TArg, TResult]) WithMaxAttempts(maxAttempts int) *SafeDispatcher[TArg, TResult] {
+	if maxAttempts <= 0 {
+		panic(fmt.Sprintf("attempts must be positive value, but was %d", maxAttempts))
+	}
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	dispatcher.maxAttempts = maxAttempts
+	return dispatcher
+}
+
+// Dispatch selects a handler by weight and calls it with the argument. If
+// the handler panics, another handler is selected among those that have not
+// panicked during this dispatch. If every attempt panics, or every handler
+// has panicked, the returned error joins a PanicError for each attempt.
+func (dispatcher *SafeDispatcher[TArg, TResult]) Dispatch(arg TArg) (TResult, error) {
+	dispatcher.mutex.Lock()
+	maxAttempts := dispatcher.maxAttempts
+	dispatcher.mutex.Unlock()
+	panicked := make(map[int]struct{})
+	var panics []error
+	for range maxAttempts {
+		index, ok := dispatcher.next(panicked)
+		if !ok {
+			break
+		}
+		result, panicErr, err := dispatch(dispatcher.handlers[index], arg)
+		if panicErr == nil {
+			return result, err
+		}
+		panicked[index] = struct{}{}
+		panics = append(panics, panicErr)
+	}
+	var zero TResult
+	return zero, errors.Join(panics...)
+}
+
+func (dispatcher *SafeDispatcher[TArg, TResult]) next(panicked map[int]struct{}) (int, bool) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+	return dispatcher.aliasMethod.nextExcluding(dispatcher.random, func(index int) bool {
+		_, ok := panicked[index]
+		return ok
+	})
+}
+
+func dispatch[TArg any, TResult any](handler Handler[TArg, TResult], arg TArg) (result TResult, panicErr *PanicError, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			panicErr = &PanicError{
+				Value: value,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+	result, err = handler(arg)
+	return result, nil, err
+}
@@ -0,0 +1,90 @@
+package weightedrand_test
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeDispatcher(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	healthy := func(color MarbleColor) Handler[int, MarbleColor] {
+		return func(int) (MarbleColor, error) {
+			return color, nil
+		}
+	}
+	faulty := func(int) (MarbleColor, error) {
+		panic("plugin crashed")
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSafeDispatcher[int, MarbleColor, int](r)
+		})
+		assert.Panics(t, func() {
+			NewSafeDispatcher(r, WeightedItem[Handler[int, MarbleColor], int]{Item: nil, Weight: 1})
+		})
+		assert.Panics(t, func() {
+			NewSafeDispatcher(r, WeightedItem[Handler[int, MarbleColor], int]{Item: healthy(Red), Weight: 1}).WithMaxAttempts(0)
+		})
+	})
+	t.Run("distribution", func(t *testing.T) {
+		d := NewSafeDispatcher(r,
+			WeightedItem[Handler[int, MarbleColor], int]{Item: healthy(Red), Weight: 1},
+			WeightedItem[Handler[int, MarbleColor], int]{Item: healthy(Blue), Weight: 3},
+		)
+		counts := make(MarbleColorCounts)
+		for range 100_000 {
+			color, err := d.Dispatch(0)
+			assert.NoError(t, err)
+			counts[color] += 1
+		}
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+	})
+	t.Run("panicking handler is retried over the others", func(t *testing.T) {
+		d := NewSafeDispatcher(r,
+			WeightedItem[Handler[int, MarbleColor], int]{Item: faulty, Weight: 100},
+			WeightedItem[Handler[int, MarbleColor], int]{Item: healthy(Green), Weight: 1},
+		).WithMaxAttempts(2)
+		for range 1_000 {
+			color, err := d.Dispatch(0)
+			assert.NoError(t, err)
+			assert.Equal(t, Green, color)
+		}
+	})
+	t.Run("errors are not retried", func(t *testing.T) {
+		failure := errors.New("failure")
+		d := NewSafeDispatcher(r,
+			WeightedItem[Handler[int, MarbleColor], int]{Item: func(int) (MarbleColor, error) { return Red, failure }, Weight: 1},
+		)
+		color, err := d.Dispatch(0)
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, Red, color)
+	})
+	t.Run("every attempt panics", func(t *testing.T) {
+		cause := errors.New("cause")
+		d := NewSafeDispatcher(r,
+			WeightedItem[Handler[int, MarbleColor], int]{Item: faulty, Weight: 1},
+			WeightedItem[Handler[int, MarbleColor], int]{Item: func(int) (MarbleColor, error) { panic(cause) }, Weight: 1},
+			WeightedItem[Handler[int, MarbleColor], int]{Item: healthy(Red), Weight: 1},
+		).WithMaxAttempts(2)
+		var exhausted bool
+		for range 100 {
+			color, err := d.Dispatch(0)
+			if err == nil {
+				assert.Equal(t, Red, color)
+				continue
+			}
+			exhausted = true
+			var panicErr *PanicError
+			assert.ErrorAs(t, err, &panicErr)
+			assert.NotEmpty(t, panicErr.Stack)
+			assert.ErrorIs(t, err, cause)
+			assert.Zero(t, color)
+		}
+		assert.True(t, exhausted)
+	})
+}
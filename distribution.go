@@ -0,0 +1,133 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// distribution is implemented by the choosers that can report the
+// distribution they select from, which allows distributions to be combined
+// and queried rather than only sampled.
+type distribution[TItem any] interface {
+	WeightedRandom[TItem]
+	// weights reports the weight every item contributes to the table,
+	// relative to the other items. An item may be reported more than once,
+	// and items that cannot be selected are not reported.
+	weights() []weightedItem[TItem]
+	// randomSource reports the random number generator the chooser uses.
+	randomSource() RandIntN
+}
+
+// distributionOf asserts that the chooser reports its distribution.
+func distributionOf[TItem any](wr WeightedRandom[TItem]) distribution[TItem] {
+	d, ok := wr.(distribution[TItem])
+	if !ok {
+		panic(fmt.Sprintf("the distribution of %T cannot be determined", wr))
+	}
+	return d
+}
+
+// mergedWeights sums the weights reported for each distinct item.
+func mergedWeights[TItem comparable](d distribution[TItem]) map[TItem]decimal.Decimal {
+	merged := make(map[TItem]decimal.Decimal)
+	for _, item := range d.weights() {
+		merged[item.Item] = merged[item.Item].Add(item.Weight)
+	}
+	return merged
+}
+
+// Every bucket contributes its probability to its primary item, and the
+// remainder to its alias. Only the relative contributions matter, so they
+// are not divided by the number of buckets.
+func (aliasMethod voseAliasMethodRandom[TItem]) weights() []weightedItem[TItem] {
+	weights := make([]weightedItem[TItem], 0, 2*len(aliasMethod.tuples))
+	for _, tuple := range aliasMethod.tuples {
+		if tuple.probability.IsPositive() {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   tuple.primaryItem,
+				Weight: tuple.probability,
+			})
+		}
+		if tuple.aliasedItem != nil && tuple.probability.LessThan(One) {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   *tuple.aliasedItem,
+				Weight: One.Sub(tuple.probability),
+			})
+		}
+	}
+	return weights
+}
+
+func (aliasMethod voseAliasMethodRandom[TItem]) randomSource() RandIntN {
+	return aliasMethod.random
+}
+
+func (aliasMethod integerAliasMethodRandom[TItem]) weights() []weightedItem[TItem] {
+	weights := make([]weightedItem[TItem], 0, 2*len(aliasMethod.items))
+	for i, threshold := range aliasMethod.thresholds {
+		if threshold > 0 {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[i],
+				Weight: decimal.NewFromInt(threshold),
+			})
+		}
+		if remainder := aliasMethod.total - threshold; remainder > 0 {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[aliasMethod.aliases[i]],
+				Weight: decimal.NewFromInt(remainder),
+			})
+		}
+	}
+	return weights
+}
+
+func (aliasMethod integerAliasMethodRandom[TItem]) randomSource() RandIntN {
+	return aliasMethod.random
+}
+
+func (aliasMethod float64AliasMethodRandom[TItem]) weights() []weightedItem[TItem] {
+	weights := make([]weightedItem[TItem], 0, 2*len(aliasMethod.items))
+	for i, probability := range aliasMethod.probabilities {
+		if probability > 0 {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[i],
+				Weight: decimal.NewFromFloat(probability),
+			})
+		}
+		if probability < 1 {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[aliasMethod.aliases[i]],
+				Weight: decimal.NewFromFloat(1 - probability),
+			})
+		}
+	}
+	return weights
+}
+
+func (aliasMethod float64AliasMethodRandom[TItem]) randomSource() RandIntN {
+	return aliasMethod.random
+}
+
+func (aliasMethod wideIntegerAliasMethodRandom[TItem]) weights() []weightedItem[TItem] {
+	weights := make([]weightedItem[TItem], 0, 2*len(aliasMethod.items))
+	for i, threshold := range aliasMethod.thresholds {
+		if threshold != (uint128{}) {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[i],
+				Weight: decimal.NewFromBigInt(threshold.big(), 0),
+			})
+		}
+		if threshold.less(aliasMethod.total) {
+			weights = append(weights, weightedItem[TItem]{
+				Item:   aliasMethod.items[aliasMethod.aliases[i]],
+				Weight: decimal.NewFromBigInt(aliasMethod.total.sub(threshold).big(), 0),
+			})
+		}
+	}
+	return weights
+}
+
+func (aliasMethod wideIntegerAliasMethodRandom[TItem]) randomSource() RandIntN {
+	return aliasMethod.random
+}
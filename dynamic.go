@@ -0,0 +1,352 @@
+package weightedrand
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// emptyBehavior determines what Dynamic.Next does when there are no items.
+type emptyBehavior int
+
+const (
+	emptyPanic emptyBehavior = iota
+	emptyBlock
+	emptyFallback
+)
+
+// WithEmptyBlock configures Dynamic.Next to block until an item is added,
+// when there are no items.
+func WithEmptyBlock() Option {
+	return func(o *options) {
+		o.emptyBehavior = emptyBlock
+	}
+}
+
+// WithEmptyFallback configures Dynamic.Next to return the fallback item,
+// when there are no items. The fallback is of the same type as the items of
+// the Dynamic instance it is used with.
+func WithEmptyFallback[TItem any](fallback TItem) ItemOption[TItem] {
+	return func(o *options) {
+		o.emptyBehavior = emptyFallback
+		o.fallback = fallback
+	}
+}
+
+// Dynamic is a WeightedRandom whose items can be added and removed after
+// construction. Changes are applied by rebuilding the alias table on the
+// next selection, so a burst of changes only costs a single rebuild. Use
+// Update to apply several changes at once, without concurrent selections
+// observing the intermediate states.
+//
+// When all items are removed, the behavior of Next is configured at
+// construction: by default it panics, WithEmptyBlock makes it block until
+// an item is added, and WithEmptyFallback makes it return a designated
+// item. TryNext never blocks, and returns the zero value with false instead.
+//
+// Dynamic is safe for concurrent use, given that the random number
+// generator is.
+type Dynamic[TItem comparable, TWeight Weight] struct {
+	mutex    sync.Mutex
+	added    *sync.Cond
+	random   RandIntN
+	items    []weightedItem[TItem]
+	indices  map[TItem]int
+	table    *voseAliasMethodRandom[TItem]
+	behavior emptyBehavior
+	fallback TItem
+	total    decimal.Decimal
+	onSelect func(TItem, decimal.Decimal)
+	onBuild  func(BuildInfo)
+}
+
+// NewDynamic constructs a new Dynamic instance, which may be empty.
+// Duplicated items are combined, with their weights summed.
+//
+// The function panics if weights are negative. Hooks are called while the
+// instance is locked, so they must not call its methods.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - items:  The initial WeightedItem values, each containing an item and its associated weight.
+//   - opts:   A variadic list of Option and ItemOption values.
+//
+// Example usage:
+//
+//	d := NewDynamic(randSource, []WeightedItem[string, int]{{Item: "A", Weight: 2}}, WithEmptyFallback("maintenance"))
+//	d.Add("B", 3)
+//	d.Remove("A")
+func NewDynamic[TItem comparable, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], opts ...ItemOption[TItem]) *Dynamic[TItem, TWeight] {
+	o := newOptions(opts)
+	dynamic := &Dynamic[TItem, TWeight]{
+		random:   random,
+		items:    make([]weightedItem[TItem], 0, len(items)),
+		indices:  make(map[TItem]int, len(items)),
+		behavior: o.emptyBehavior,
+		onSelect: onSelectHook[TItem](o),
+		onBuild:  o.onBuild,
+	}
+	dynamic.added = sync.NewCond(&dynamic.mutex)
+	if o.emptyBehavior == emptyFallback {
+		// ItemOption ensures the fallback is of the item type.
+		dynamic.fallback = o.fallback.(TItem)
+	}
+	for _, item := range items {
+		weight := effectiveWeight(item.Weight)
+		if index, ok := dynamic.indices[item.Item]; ok {
+			dynamic.items[index].Weight = dynamic.items[index].Weight.Add(weight)
+			continue
+		}
+		dynamic.set(item.Item, weight)
+	}
+	return dynamic
+}
+
+// Add adds the item with the weight, or replaces the weight of the item if
+// it was already added.
+//
+// Panics:
+//   - If the weight is negative.
+func (dynamic *Dynamic[TItem, TWeight]) Add(item TItem, weight TWeight) {
+	currentWeight := effectiveWeight(weight)
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	dynamic.set(item, currentWeight)
+	dynamic.added.Broadcast()
+}
+
+// Remove removes the item, and reports whether it was present.
+func (dynamic *Dynamic[TItem, TWeight]) Remove(item TItem) bool {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	items, ok := removeItem(dynamic.items, dynamic.indices, item)
+	if !ok {
+		return false
+	}
+	dynamic.items = items
+	dynamic.table = nil
+	return true
+}
+
+// WeightTx stages changes to the items of a Dynamic instance within Update.
+// The changes are only visible to the transaction until Update returns.
+type WeightTx[TItem comparable, TWeight Weight] struct {
+	items   []weightedItem[TItem]
+	indices map[TItem]int
+}
+
+// Add adds the item with the weight, or replaces the weight of the item if
+// it was already added.
+//
+// Panics:
+//   - If the weight is negative.
+func (tx *WeightTx[TItem, TWeight]) Add(item TItem, weight TWeight) {
+	currentWeight := effectiveWeight(weight)
+	if index, ok := tx.indices[item]; ok {
+		tx.items[index].Weight = currentWeight
+		return
+	}
+	tx.indices[item] = len(tx.items)
+	tx.items = append(tx.items, weightedItem[TItem]{
+		Item:   item,
+		Weight: currentWeight,
+	})
+}
+
+// SetWeight replaces the weight of the item, and reports whether it was
+// present. Unlike Add, it does not add missing items.
+//
+// Panics:
+//   - If the weight is negative.
+func (tx *WeightTx[TItem, TWeight]) SetWeight(item TItem, weight TWeight) bool {
+	currentWeight := effectiveWeight(weight)
+	index, ok := tx.indices[item]
+	if ok {
+		tx.items[index].Weight = currentWeight
+	}
+	return ok
+}
+
+// Remove removes the item, and reports whether it was present.
+func (tx *WeightTx[TItem, TWeight]) Remove(item TItem) bool {
+	items, ok := removeItem(tx.items, tx.indices, item)
+	tx.items = items
+	return ok
+}
+
+// Len returns the number of items, including the staged changes.
+func (tx *WeightTx[TItem, TWeight]) Len() int {
+	return len(tx.items)
+}
+
+// Update applies the changes of the function atomically: concurrent
+// selections either see none of them or all of them, and the alias table is
+// rebuilt once on the next selection, however many changes were made. If
+// the function panics, none of its changes are applied.
+//
+// The instance is locked while the function runs, so it must not call the
+// methods of the instance.
+//
+// Example usage:
+//
+//	d.Update(func(tx *WeightTx[string, int]) {
+//		tx.Remove("A")
+//		tx.Add("B", 3)
+//		tx.SetWeight("C", 5)
+//	})
+func (dynamic *Dynamic[TItem, TWeight]) Update(fn func(tx *WeightTx[TItem, TWeight])) {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	tx := &WeightTx[TItem, TWeight]{
+		items:   slices.Clone(dynamic.items),
+		indices: maps.Clone(dynamic.indices),
+	}
+	fn(tx)
+	dynamic.items, dynamic.indices = tx.items, tx.indices
+	dynamic.table = nil
+	if len(dynamic.items) > 0 {
+		dynamic.added.Broadcast()
+	}
+}
+
+// Len returns the number of items.
+func (dynamic *Dynamic[TItem, TWeight]) Len() int {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	return len(dynamic.items)
+}
+
+// Next selects an item by weight. When there are no items, it behaves as
+// configured at construction.
+func (dynamic *Dynamic[TItem, TWeight]) Next() TItem {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	for len(dynamic.items) == 0 {
+		switch dynamic.behavior {
+		case emptyBlock:
+			dynamic.added.Wait()
+		case emptyFallback:
+			return dynamic.fallback
+		default:
+			panic("there are no items to select from")
+		}
+	}
+	return dynamic.next()
+}
+
+// TryNext selects an item by weight. When there are no items, it returns
+// the zero value and false, regardless of the configured behavior.
+func (dynamic *Dynamic[TItem, TWeight]) TryNext() (TItem, bool) {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	if len(dynamic.items) == 0 {
+		var zero TItem
+		return zero, false
+	}
+	return dynamic.next(), true
+}
+
+func (dynamic *Dynamic[TItem, TWeight]) set(item TItem, weight decimal.Decimal) {
+	if index, ok := dynamic.indices[item]; ok {
+		dynamic.items[index].Weight = weight
+	} else {
+		dynamic.indices[item] = len(dynamic.items)
+		dynamic.items = append(dynamic.items, weightedItem[TItem]{
+			Item:   item,
+			Weight: weight,
+		})
+	}
+	dynamic.table = nil
+}
+
+// removeItem removes the item from the items and their indices, moving the
+// last item into its place, and reports whether it was present.
+func removeItem[TItem comparable](items []weightedItem[TItem], indices map[TItem]int, item TItem) ([]weightedItem[TItem], bool) {
+	index, ok := indices[item]
+	if !ok {
+		return items, false
+	}
+	last := len(items) - 1
+	items[index] = items[last]
+	indices[items[index].Item] = index
+	delete(indices, item)
+	return items[:last], true
+}
+
+// Snapshot returns an immutable view of the current items and weights,
+// which is unaffected by later changes, for consumers such as batch jobs
+// that must select from the same distribution throughout. The view shares
+// the random number generator and the hook of WithOnSelect of the instance,
+// and building it costs at most a single build of the alias table.
+//
+// When there are no items, the view of an instance configured with
+// WithEmptyFallback always returns the fallback.
+//
+// Panics:
+//   - If there are no items and no fallback, including when configured with
+//     WithEmptyBlock, as the view could never select an item.
+//
+// Example usage:
+//
+//	snapshot := d.Snapshot()
+//	for _, job := range batch {
+//		job.Assign(snapshot.Next())
+//	}
+func (dynamic *Dynamic[TItem, TWeight]) Snapshot() WeightedRandom[TItem] {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	if len(dynamic.items) == 0 {
+		if dynamic.behavior != emptyFallback {
+			panic("there are no items to select from")
+		}
+		return newVoseAliasMethodFromDecimals(dynamic.random, []weightedItem[TItem]{{
+			Item:   dynamic.fallback,
+			Weight: One,
+		}})
+	}
+	if dynamic.onSelect != nil {
+		items := make([]WeightedItem[TItem, decimal.Decimal], len(dynamic.items))
+		for i, item := range dynamic.items {
+			items[i] = WeightedItem[TItem, decimal.Decimal](item)
+		}
+		return newHookedAliasMethod(buildContext{}, dynamic.random, items, false, dynamic.onSelect)
+	}
+	dynamic.build()
+	return *dynamic.table
+}
+
+// build rebuilds the alias table, if the items changed since it was last
+// built.
+func (dynamic *Dynamic[TItem, TWeight]) build() {
+	if dynamic.table != nil {
+		return
+	}
+	start := time.Now()
+	table := newVoseAliasMethodFromDecimals(dynamic.random, dynamic.items)
+	dynamic.table = &table
+	dynamic.total = totalWeight(dynamic.items)
+	if dynamic.onBuild != nil {
+		dynamic.onBuild(BuildInfo{
+			Items:       len(dynamic.items),
+			TotalWeight: dynamic.total,
+			Engine:      "decimal",
+			Duration:    time.Since(start),
+		})
+	}
+}
+
+func (dynamic *Dynamic[TItem, TWeight]) next() TItem {
+	dynamic.build()
+	item := dynamic.table.Next()
+	if dynamic.onSelect != nil {
+		dynamic.onSelect(item, dynamic.items[dynamic.indices[item]].Weight.Div(dynamic.total))
+	}
+	return item
+}
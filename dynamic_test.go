@@ -0,0 +1,155 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamic(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		t.Run("empty", func(t *testing.T) {
+			d := NewDynamic[MarbleColor, int](nil, nil)
+			assert.Panics(t, func() {
+				d.Next()
+			})
+		})
+		t.Run("negative weight", func(t *testing.T) {
+			d := NewDynamic[MarbleColor, int](nil, nil)
+			assert.Panics(t, func() {
+				d.Add(Red, -1)
+			})
+		})
+	})
+	t.Run("add and remove", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		d := NewDynamic(r, []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 2},
+		})
+		assert.Equal(t, 2, d.Len())
+		counts := Counts[MarbleColor](d, 100_000)
+		assert.InDeltaf(t, 0.5, float64(counts[Red])/100_000, tolerance, "%v", counts)
+
+		d.Add(Green, 2)
+		assert.True(t, d.Remove(Red))
+		assert.False(t, d.Remove(Red))
+		counts = Counts[MarbleColor](d, 100_000)
+		assert.Zero(t, counts[Red])
+		assert.InDeltaf(t, 0.5, float64(counts[Green])/100_000, tolerance, "%v", counts)
+
+		d.Add(Green, 6)
+		counts = Counts[MarbleColor](d, 100_000)
+		assert.InDeltaf(t, 0.75, float64(counts[Green])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("update", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		d := NewDynamic(r, []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 1},
+		})
+		d.Update(func(tx *WeightTx[MarbleColor, int]) {
+			assert.True(t, tx.Remove(Red))
+			assert.False(t, tx.Remove(Red))
+			assert.False(t, tx.SetWeight(Red, 1))
+			tx.Add(Green, 1)
+			assert.True(t, tx.SetWeight(Green, 3))
+			assert.Equal(t, 2, tx.Len())
+		})
+		assert.Equal(t, 2, d.Len())
+		counts := Counts[MarbleColor](d, 100_000)
+		assert.Zero(t, counts[Red])
+		assert.InDeltaf(t, 0.75, float64(counts[Green])/100_000, tolerance, "%v", counts)
+
+		assert.Panics(t, func() {
+			d.Update(func(tx *WeightTx[MarbleColor, int]) {
+				tx.Remove(Blue)
+				tx.Add(Yellow, -1)
+			})
+		})
+		assert.Equal(t, 2, d.Len())
+		counts = Counts[MarbleColor](d, 100_000)
+		assert.Zero(t, counts[Yellow])
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("update unblocks", func(t *testing.T) {
+		d := NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithEmptyBlock())
+		selected := make(chan MarbleColor)
+		go func() {
+			selected <- d.Next()
+		}()
+		d.Update(func(tx *WeightTx[MarbleColor, int]) {
+			tx.Add(Red, 1)
+			tx.Remove(Red)
+			tx.Add(Blue, 1)
+		})
+		assert.Equal(t, Blue, <-selected)
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		d := NewDynamic(r, []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 3},
+		})
+		snapshot := d.Snapshot()
+		d.Remove(Red)
+		d.Add(Green, 100)
+		counts := Counts(snapshot, 100_000)
+		assert.Zero(t, counts[Green])
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.Equal(t, 2, d.Len())
+
+		var selected []MarbleColor
+		d = NewDynamic(r, []WeightedItem[MarbleColor, int]{{Item: Red, Weight: 1}},
+			WithOnSelect(func(item MarbleColor, probability decimal.Decimal) {
+				assert.True(t, probability.Equal(One))
+				selected = append(selected, item)
+			}))
+		snapshot = d.Snapshot()
+		d.Add(Blue, 1)
+		assert.Equal(t, Red, snapshot.Next())
+		assert.Equal(t, []MarbleColor{Red}, selected)
+	})
+	t.Run("empty snapshot", func(t *testing.T) {
+		d := NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithEmptyBlock())
+		assert.PanicsWithValue(t, "there are no items to select from", func() {
+			d.Snapshot()
+		})
+		d = NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithEmptyFallback(Yellow))
+		snapshot := d.Snapshot()
+		d.Add(Red, 1)
+		assert.Equal(t, Yellow, snapshot.Next())
+	})
+	t.Run("try next", func(t *testing.T) {
+		d := NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithEmptyBlock())
+		color, ok := d.TryNext()
+		assert.False(t, ok)
+		assert.Zero(t, color)
+	})
+	t.Run("fallback", func(t *testing.T) {
+		d := NewDynamic(rand.New(rand.NewSource(1)), []WeightedItem[MarbleColor, int]{{Item: Red}},
+			WithEmptyFallback(Yellow))
+		assert.Equal(t, Red, d.Next())
+		d.Remove(Red)
+		assert.Equal(t, Yellow, d.Next())
+	})
+	t.Run("block", func(t *testing.T) {
+		d := NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithEmptyBlock())
+		selected := make(chan MarbleColor)
+		go func() {
+			selected <- d.Next()
+		}()
+		select {
+		case <-selected:
+			assert.Fail(t, "next did not block")
+		case <-time.After(10 * time.Millisecond):
+		}
+		d.Add(Blue, 1)
+		assert.Equal(t, Blue, <-selected)
+	})
+}
@@ -0,0 +1,139 @@
+package weightedrand
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Election elects an owner for each resource key among weighted nodes, such
+// as the leader of a partition or the replica holding a shard, using
+// weighted rendezvous hashing. Each node is elected for a share of the keys
+// proportional to its weight, such as its capacity, and the owner of a key
+// only depends on the key and the nodes with their weights, so every process
+// that agrees on the membership agrees on every owner without coordination.
+//
+// An Election is immutable; construct a new one whenever the membership or
+// the weights change. The churn is minimal: when a node joins, it only takes
+// over keys from the others, when a node leaves, only its keys are elected
+// anew, and when the weight of a node changes, keys only move to or from
+// that node.
+//
+// Election is safe for concurrent use.
+type Election[TNode any] struct {
+	nodes []electionNode[TNode]
+}
+
+type electionNode[TNode any] struct {
+	node   TNode
+	id     string
+	weight float64
+}
+
+// NewElection constructs a new Election among the nodes, weighted by their
+// capacity. Nodes are identified by their formatted representation (%v), so
+// that the election does not depend on the order they are provided in, and
+// the same node is recognized across memberships.
+//
+// The function panics if no nodes are provided, weights are negative, or
+// two nodes have the same representation.
+//
+// Type Parameters:
+//   - TNode:   The type of the nodes to be elected.
+//   - TWeight: The type representing the weight of each node.
+//
+// Parameters:
+//   - nodes: The WeightedItem values, each containing a node and its associated weight.
+//
+// Example usage:
+//
+//	election := NewElection(
+//		WeightedItem[string, int]{Item: "node-a", Weight: 16},
+//		WeightedItem[string, int]{Item: "node-b", Weight: 32},
+//	)
+//	leader := election.Owner("partition-7")
+func NewElection[TNode any, TWeight Weight](nodes ...WeightedItem[TNode, TWeight]) *Election[TNode] {
+	if len(nodes) == 0 {
+		panic("at least one node must be provided")
+	}
+	election := &Election[TNode]{
+		nodes: make([]electionNode[TNode], len(nodes)),
+	}
+	ids := make(map[string]bool, len(nodes))
+	for i, node := range nodes {
+		id := fmt.Sprintf("%v", node.Item)
+		if ids[id] {
+			panic(fmt.Sprintf("node %q was provided more than once", id))
+		}
+		ids[id] = true
+		election.nodes[i] = electionNode[TNode]{
+			node:   node.Item,
+			id:     id,
+			weight: effectiveWeight(node.Weight).InexactFloat64(),
+		}
+	}
+	return election
+}
+
+// Owner returns the node elected for the key.
+func (election *Election[TNode]) Owner(key string) TNode {
+	best, bestScore := 0, math.Inf(-1)
+	for i, node := range election.nodes {
+		score := node.score(key)
+		if score > bestScore || (score == bestScore && node.id < election.nodes[best].id) {
+			best, bestScore = i, score
+		}
+	}
+	return election.nodes[best].node
+}
+
+// Owners returns up to n distinct nodes for the key, ordered by preference,
+// such as the replicas of a shard. The first is the Owner of the key, and
+// each of the others would be elected if those before it left.
+//
+// Panics:
+//   - If n is negative.
+func (election *Election[TNode]) Owners(key string, n int) []TNode {
+	if n < 0 {
+		panic(fmt.Sprintf("number of owners must be non-negative, but was %d", n))
+	}
+	type scoredNode struct {
+		index int
+		score float64
+	}
+	scored := make([]scoredNode, len(election.nodes))
+	for i, node := range election.nodes {
+		scored[i] = scoredNode{index: i, score: node.score(key)}
+	}
+	slices.SortFunc(scored, func(a, b scoredNode) int {
+		if order := cmp.Compare(b.score, a.score); order != 0 {
+			return order
+		}
+		return cmp.Compare(election.nodes[a.index].id, election.nodes[b.index].id)
+	})
+	owners := make([]TNode, 0, min(n, len(scored)))
+	for _, node := range scored[:cap(owners)] {
+		owners = append(owners, election.nodes[node.index].node)
+	}
+	return owners
+}
+
+// score is the weighted rendezvous score of the node for the key, -w/ln(u)
+// for a uniform u in (0, 1) hashed from both, so that the node with the
+// highest score is elected with a probability proportional to its weight.
+// Both are prefixed by their length before hashing, so that no other pair of
+// node and key produces the same input.
+func (node electionNode[TNode]) score(key string) float64 {
+	hash := sha256.New()
+	for _, part := range []string{node.id, key} {
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(len(part))))
+		hash.Write([]byte(part))
+	}
+	digest := hash.Sum(nil)
+	// The top 53 bits, centered in their interval, are never 0 or 1.
+	uniform := (float64(binary.BigEndian.Uint64(digest[:8])>>11) + 0.5) / (1 << 53)
+	return -node.weight / math.Log(uniform)
+}
@@ -0,0 +1,110 @@
+package weightedrand_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElection(t *testing.T) {
+	const keys = 100_000
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "at least one node must be provided", func() {
+			NewElection[MarbleColor, int]()
+		})
+		assert.Panics(t, func() {
+			NewElection(WeightedItem[MarbleColor, int]{Item: Red, Weight: -1})
+		})
+		assert.PanicsWithValue(t, `node "RED" was provided more than once`, func() {
+			NewElection(
+				WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+				WeightedItem[MarbleColor, int]{Item: Red, Weight: 2},
+			)
+		})
+		assert.Panics(t, func() {
+			NewElection(WeightedItem[MarbleColor, int]{Item: Red}).Owners("key", -1)
+		})
+	})
+	t.Run("proportional to weights", func(t *testing.T) {
+		election := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		counts := MarbleColorCounts{}
+		for i := range keys {
+			counts[election.Owner(strconv.Itoa(i))]++
+		}
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/keys, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/keys, tolerance, "%v", counts)
+	})
+	t.Run("deterministic", func(t *testing.T) {
+		election := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 2},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		reordered := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 2},
+		)
+		for i := range 1_000 {
+			key := strconv.Itoa(i)
+			assert.Equal(t, election.Owner(key), reordered.Owner(key))
+			assert.Equal(t, election.Owners(key, 3), reordered.Owners(key, 3))
+		}
+	})
+	t.Run("owners", func(t *testing.T) {
+		election := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 2},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		owners := election.Owners("key", 5)
+		assert.ElementsMatch(t, []MarbleColor{Red, Green, Blue}, owners)
+		assert.Equal(t, election.Owner("key"), owners[0])
+		assert.Equal(t, owners[:2], election.Owners("key", 2))
+		assert.Empty(t, election.Owners("key", 0))
+
+		// Without the owner, the runner-up is elected.
+		var remaining []WeightedItem[MarbleColor, int]
+		for _, node := range []WeightedItem[MarbleColor, int]{{Item: Red, Weight: 1}, {Item: Green, Weight: 2}, {Item: Blue, Weight: 3}} {
+			if node.Item != owners[0] {
+				remaining = append(remaining, node)
+			}
+		}
+		assert.Equal(t, owners[1], NewElection(remaining...).Owner("key"))
+	})
+	t.Run("minimal churn", func(t *testing.T) {
+		before := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+		)
+		joined := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 2},
+		)
+		resized := NewElection(
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		moved := 0
+		for i := range keys {
+			key := strconv.Itoa(i)
+			owner := before.Owner(key)
+			if newOwner := joined.Owner(key); newOwner != owner {
+				// Keys only move to the node that joined.
+				assert.Equal(t, Green, newOwner)
+				moved++
+			}
+			if newOwner := resized.Owner(key); newOwner != owner {
+				// Keys only move to the node whose weight grew.
+				assert.Equal(t, Blue, newOwner)
+			}
+		}
+		assert.InDelta(t, 0.5, float64(moved)/keys, tolerance)
+	})
+}
@@ -0,0 +1,68 @@
+package weightedrand
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNoItems is returned when no items are provided.
+var ErrNoItems = errors.New("at least one item must be provided")
+
+// ErrAllZeroWeights is returned by the constructors for which a weight of
+// zero excludes an item, rather than being assumed to be 1, when no item
+// has a positive weight.
+var ErrAllZeroWeights = errors.New("at least one item must have a positive weight")
+
+// ErrOverflow is returned when the total weight overflows the arithmetic of
+// the integer engine.
+var ErrOverflow = errors.New("the total weight overflows the integer engine")
+
+// ErrNegativeWeight is returned when the weight of an item is negative.
+type ErrNegativeWeight struct {
+	// Index is the index of the item among the items provided.
+	Index int
+	// Weight is the negative weight.
+	Weight decimal.Decimal
+}
+
+func (err *ErrNegativeWeight) Error() string {
+	return fmt.Sprintf("weight of item %d must be non-negative value, but was %s", err.Index, err.Weight.String())
+}
+
+// ErrUnknownProfile is returned by Profiles when a profile, or a profile it
+// inherits from, is not defined.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// ErrProfileCycle is returned by Profiles when profiles inherit from each
+// other in a cycle.
+type ErrProfileCycle struct {
+	// Cycle is the names of the profiles in the cycle, in the order they
+	// inherit from each other, starting and ending with the same profile.
+	Cycle []string
+}
+
+func (err *ErrProfileCycle) Error() string {
+	return fmt.Sprintf("profiles inherit from each other in a cycle: %s", strings.Join(err.Cycle, " -> "))
+}
+
+// ErrSwapConflict is returned by Staged when the Swappable was swapped since
+// the table was staged or committed, so that the change would overwrite
+// another.
+var ErrSwapConflict = errors.New("the swappable was swapped concurrently")
+
+// ErrStageFinished is returned by Staged when it was already committed or
+// rolled back.
+var ErrStageFinished = errors.New("the stage was already finished")
+
+// ErrNonFiniteWeight is returned when the weight of an item is a
+// floating-point number that is not finite, such as NaN.
+var ErrNonFiniteWeight = errors.New("weight must be a finite number")
+
+// ErrNonPositiveQuantity is returned by the unit helpers, such as
+// WeightFromRate, when the quantity is zero or negative. A weight of zero
+// would otherwise be assumed to be 1 by the constructors, so an idle
+// backend or an empty order would be selected rather than excluded.
+var ErrNonPositiveQuantity = errors.New("quantity must be positive")
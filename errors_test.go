@@ -0,0 +1,91 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("no items", func(t *testing.T) {
+		_, err := BuildTable[MarbleColor, int]()
+		assert.ErrorIs(t, err, ErrNoItems)
+		wr, err := NewAliasVoseMethodE[MarbleColor, int](rand.New(rand.NewSource(1)))
+		assert.ErrorIs(t, err, ErrNoItems)
+		assert.Nil(t, wr)
+		_, err = FairWeights[MarbleColor, int](nil, nil, nil)
+		assert.ErrorIs(t, err, ErrNoItems)
+		_, err = NewRefresher(context.Background(), rand.New(rand.NewSource(1)), func(context.Context) ([]WeightedItem[MarbleColor, int], error) {
+			return nil, nil
+		})
+		assert.ErrorIs(t, err, ErrNoItems)
+	})
+	t.Run("negative weight", func(t *testing.T) {
+		items := []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Green, Weight: 0},
+			{Item: Blue, Weight: -3},
+		}
+		_, err := BuildTableWithOptions(items, WithMinProbability(decimal.Zero))
+		var negativeErr *ErrNegativeWeight
+		if assert.ErrorAs(t, err, &negativeErr) {
+			assert.Equal(t, 2, negativeErr.Index)
+			assert.Equal(t, "-3", negativeErr.Weight.String())
+		}
+		_, err = NewAliasVoseMethodE(rand.New(rand.NewSource(1)), items...)
+		assert.ErrorAs(t, err, &negativeErr)
+		wr, err := NewAliasVoseMethodE(rand.New(rand.NewSource(1)), items[:2]...)
+		if assert.NoError(t, err) {
+			assert.Contains(t, []MarbleColor{Red, Green}, wr.Next())
+		}
+		_, err = NewRefresher(context.Background(), rand.New(rand.NewSource(1)), func(context.Context) ([]WeightedItem[MarbleColor, int], error) {
+			return items, nil
+		})
+		assert.ErrorAs(t, err, &negativeErr)
+		assert.EqualError(t, err, "could not list items: weight of item 2 must be non-negative value, but was -3")
+	})
+	t.Run("overflow", func(t *testing.T) {
+		items := []WeightedItem[MarbleColor, uint64]{
+			{Item: Red, Weight: math.MaxUint64},
+			{Item: Blue, Weight: math.MaxUint64},
+		}
+		wr, err := NewAliasVoseMethodWithOptionsE(rand.New(rand.NewSource(1)), items, WithIntegerEngine())
+		if assert.NoError(t, err) {
+			assert.Contains(t, []MarbleColor{Red, Blue}, wr.Next())
+		}
+		_, err = NewAliasVoseMethodWithOptionsE(rand.New(rand.NewSource(1)), []WeightedItem[MarbleColor, decimal.Decimal]{
+			{Item: Red, Weight: FixtureDecimal(t, "100000000000000000000")},
+		}, WithIntegerEngine())
+		assert.ErrorIs(t, err, ErrOverflow)
+		_, err = NewAliasVoseMethodWithOptionsE(rand.New(rand.NewSource(1)), []WeightedItem[MarbleColor, decimal.Decimal]{
+			{Item: Red, Weight: FixtureDecimal(t, "0.000000000000000000000000000001")},
+			{Item: Blue, Weight: FixtureDecimal(t, "1")},
+		}, WithExactThresholds())
+		assert.ErrorIs(t, err, ErrOverflow)
+		_, err = NewAliasVoseMethodWithOptionsE(rand.New(rand.NewSource(1)), []WeightedItem[MarbleColor, int]{{Item: Red, Weight: -1}}, WithIntegerEngine())
+		var negativeErr *ErrNegativeWeight
+		assert.ErrorAs(t, err, &negativeErr)
+		_, err = NewAliasVoseMethodWithOptionsE[MarbleColor, int](rand.New(rand.NewSource(1)), nil, WithOnSelect(func(MarbleColor, decimal.Decimal) {}))
+		assert.ErrorIs(t, err, ErrNoItems)
+
+		defer func() {
+			recovered, ok := recover().(error)
+			if assert.True(t, ok) {
+				assert.ErrorIs(t, recovered, ErrOverflow)
+			}
+		}()
+		NewAliasVoseMethodWithOptions(nil, []WeightedItem[MarbleColor, decimal.Decimal]{
+			{Item: Red, Weight: FixtureDecimal(t, "100000000000000000000")},
+		}, WithIntegerEngine())
+	})
+	t.Run("all zero weights", func(t *testing.T) {
+		weighter := NewMetricWeighter(rand.New(rand.NewSource(1)), nil)
+		assert.ErrorIs(t, weighter.Update(map[string]float64{"us-east": 0, "eu-west": 0}), ErrAllZeroWeights)
+		assert.NoError(t, weighter.Update(map[string]float64{"us-east": 0, "eu-west": 1}))
+	})
+}
@@ -0,0 +1,179 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// WithExactThresholds builds the alias table with exact integer thresholds
+// instead of decimal probabilities. The coin tosses of the decimal engine
+// resolve only 1/100, which selects items with tiny weights far more often
+// than their weights warrant, and its probabilities are kept to 16 decimal
+// places, which rounds away the tiniest weights so that their items are
+// never selected. With this option, every weight is scaled by the same power
+// of ten into a whole number, and the table is built by the integer engine,
+// so that every item is selected with exactly its share of the total weight,
+// however tiny, such as 1 in 10^9. Rational weights of type *big.Rat are
+// scaled by their least common denominator instead, so that odds such as
+// 1/3 are exact as well. Use Starved to verify whether a chooser built
+// without it can select every item.
+//
+// The constructor panics if a weight cannot be scaled into a whole number
+// that fits in a uint64, because the weights span too many orders of
+// magnitude.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, []WeightedItem[string, decimal.Decimal]{
+//		{Item: "legitimate", Weight: decimal.NewFromInt(1)},
+//		{Item: "fraud", Weight: decimal.RequireFromString("0.000000000000000001")},
+//	}, WithExactThresholds())
+func WithExactThresholds() Option {
+	return func(o *options) {
+		o.exactThresholds = true
+	}
+}
+
+// exactItems scales the weights of the items by their least common
+// denominator, which for decimal weights is the smallest power of ten that
+// makes all of them whole numbers, and returns that scale.
+func exactItems[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight]) ([]WeightedItem[TItem, uint64], *big.Int, error) {
+	weights := make([]*big.Rat, len(items))
+	scale := big.NewInt(1)
+	for i, item := range items {
+		weight, err := exactWeight(i, item.Weight)
+		if err != nil {
+			return nil, nil, err
+		}
+		weights[i] = weight
+		// The least common multiple of the scale and the denominator.
+		gcd := new(big.Int).GCD(nil, nil, scale, weight.Denom())
+		scale.Mul(scale, new(big.Int).Quo(weight.Denom(), gcd))
+	}
+	scaled := make([]WeightedItem[TItem, uint64], len(items))
+	for i, weight := range weights {
+		whole := new(big.Int).Quo(scale, weight.Denom())
+		whole.Mul(whole, weight.Num())
+		if whole.BitLen() > 64 {
+			return nil, nil, fmt.Errorf("weight of item %d cannot be scaled by %s into 64 bits: %w", i, formatScale(scale), ErrOverflow)
+		}
+		scaled[i] = WeightedItem[TItem, uint64]{
+			Item:   items[i].Item,
+			Weight: whole.Uint64(),
+		}
+	}
+	return scaled, scale, nil
+}
+
+// exactWeight is like decimalWeight, but keeps rational weights exact.
+func exactWeight[TWeight Weight](index int, weight TWeight) (*big.Rat, error) {
+	if rat, ok := any(weight).(*big.Rat); ok && rat != nil && rat.Sign() > 0 {
+		return new(big.Rat).Set(rat), nil
+	}
+	value, err := decimalWeight(index, weight)
+	if err != nil {
+		return nil, err
+	}
+	return value.Rat(), nil
+}
+
+// formatScale formats the scale of exactItems, as a power of ten if it is
+// one.
+func formatScale(scale *big.Int) string {
+	digits := scale.String()
+	if strings.Trim(digits[1:], "0") == "" && digits[0] == '1' {
+		return fmt.Sprintf("10^%d", len(digits)-1)
+	}
+	return digits
+}
+
+// starvingSampler is implemented by the choosers whose coin tosses have a
+// limited resolution, which may leave items with positive weights that can
+// never be selected.
+type starvingSampler[TItem any] interface {
+	// resolvedShares reports the share of the coin tosses every bucket
+	// resolves to each of its items, including shares of zero.
+	resolvedShares() []weightedItem[TItem]
+}
+
+// Starved returns the items of the chooser that have a positive weight, yet
+// can never be selected, because their probability was rounded away or is
+// below the resolution of the coin tosses. Choosers built by the decimal
+// engine, such as NewAliasVoseMethod, may starve items whose weights are
+// many orders of magnitude below the total, while those built by the integer
+// engine or WithExactThresholds are exact and starve none.
+//
+// Panics:
+//   - If the chooser was not constructed by this package, and its
+//     distribution cannot be determined.
+//
+// Example usage:
+//
+//	if starved := Starved(wr); len(starved) > 0 {
+//		log.Fatalf("items %v can never be selected", starved)
+//	}
+func Starved[TItem comparable](wr WeightedRandom[TItem]) []TItem {
+	sampler, ok := wr.(starvingSampler[TItem])
+	if !ok {
+		// Other choosers are exact, which is only known of those whose
+		// distribution is known.
+		distributionOf(wr)
+		return nil
+	}
+	shares := sampler.resolvedShares()
+	selectable := make(map[TItem]bool, len(shares))
+	for _, share := range shares {
+		selectable[share.Item] = selectable[share.Item] || share.Weight.IsPositive()
+	}
+	var starved []TItem
+	for _, share := range shares {
+		if !selectable[share.Item] {
+			starved = append(starved, share.Item)
+			// Report every item once.
+			selectable[share.Item] = true
+		}
+	}
+	return starved
+}
+
+// resolvedShares resolves the coin toss of every bucket like NextUsing,
+// which selects the primary item for k out of 100 when k/100 is less than
+// its probability, and the alias otherwise.
+func (aliasMethod voseAliasMethodRandom[TItem]) resolvedShares() []weightedItem[TItem] {
+	shares := make([]weightedItem[TItem], 0, 2*len(aliasMethod.tuples))
+	for _, tuple := range aliasMethod.tuples {
+		threshold := min(tuple.probability.Shift(2).Ceil().IntPart(), 100)
+		shares = append(shares, weightedItem[TItem]{
+			Item:   tuple.primaryItem,
+			Weight: decimal.NewFromInt(threshold),
+		})
+		if tuple.aliasedItem != nil {
+			shares = append(shares, weightedItem[TItem]{
+				Item:   *tuple.aliasedItem,
+				Weight: decimal.NewFromInt(100 - threshold),
+			})
+		}
+	}
+	return shares
+}
+
+// resolvedShares resolves the coin tosses of the table of indices, if it
+// has a limited resolution.
+func (aliasMethod hookedAliasMethod[TItem]) resolvedShares() []weightedItem[TItem] {
+	sampler, ok := aliasMethod.indices.(starvingSampler[int])
+	if !ok {
+		return nil
+	}
+	indexShares := sampler.resolvedShares()
+	shares := make([]weightedItem[TItem], len(indexShares))
+	for i, share := range indexShares {
+		shares[i] = weightedItem[TItem]{
+			Item:   aliasMethod.items[share.Item],
+			Weight: share.Weight,
+		}
+	}
+	return shares
+}
@@ -0,0 +1,62 @@
+package weightedrand_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExactThresholds(t *testing.T) {
+	items := []WeightedItem[int, decimal.Decimal]{
+		{Item: 0, Weight: FixtureDecimal(t, "1")},
+		{Item: 1, Weight: FixtureDecimal(t, "0.000000000000000001")},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithError(t, "weight of item 1 cannot be scaled by 10^30 into 64 bits: the total weight overflows the integer engine", func() {
+			NewAliasVoseMethodWithOptions(nil, []WeightedItem[int, decimal.Decimal]{
+				{Item: 0, Weight: FixtureDecimal(t, "0.000000000000000000000000000001")},
+				{Item: 1, Weight: FixtureDecimal(t, "1")},
+			}, WithExactThresholds())
+		})
+		assert.Panics(t, func() {
+			Starved[int](opaqueWeightedRandom{})
+		})
+	})
+	t.Run("tiny weights remain selectable", func(t *testing.T) {
+		var info BuildInfo
+		wr := NewAliasVoseMethodWithOptions(nil, items, WithExactThresholds(), WithOnBuild(func(built BuildInfo) {
+			info = built
+		}))
+		assert.Empty(t, Starved(wr))
+		assert.Equal(t, "exact", info.Engine)
+		assert.Equal(t, "1.000000000000000001", info.TotalWeight.String())
+
+		wr = NewAliasVoseMethodWithOptions(nil, []WeightedItem[int, *big.Rat]{
+			{Item: 0, Weight: big.NewRat(1, 3)},
+			{Item: 1, Weight: big.NewRat(7, 13)},
+			{Item: 2, Weight: big.NewRat(1, 1_000_000_000_000)},
+		}, WithExactThresholds(), WithOnBuild(func(built BuildInfo) {
+			info = built
+		}))
+		assert.Empty(t, Starved(wr))
+		assert.Equal(t, "exact", info.Engine)
+		assert.Equal(t, "0.871794871795871794872", info.TotalWeight.StringFixed(21))
+
+		assert.Equal(t, []int{1}, Starved(NewAliasVoseMethod(nil, items...)))
+		assert.Equal(t, []int{1}, Starved(NewAliasVoseMethodWithOptions(nil, items, WithOnSelect(func(int, decimal.Decimal) {}))))
+		assert.Empty(t, Starved(NewAliasVoseMethodWithOptions(nil, []WeightedItem[int, int]{{Item: 0, Weight: 1_000_000_000}, {Item: 1}}, WithIntegerEngine())))
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, decimal.Decimal]{
+			{Item: Red, Weight: FixtureDecimal(t, "0.999")},
+			{Item: Blue, Weight: FixtureDecimal(t, "0.001")},
+		}, WithExactThresholds())
+		assert.NoError(t, SelfTest(wr, 100_000))
+	})
+}
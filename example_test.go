@@ -40,3 +40,25 @@ func ExampleNewAliasVoseMethod() {
 	// Stardew Valley
 	// Deep Rock Galactic
 }
+
+func ExampleNewSeededFromString() {
+	wr := weightedrand.NewSeededFromString("game night",
+		weightedrand.WeightedItem[string, int]{
+			Item:   "Hollow Knight: Silksong",
+			Weight: 1,
+		},
+		weightedrand.WeightedItem[string, int]{
+			Item:   "Deep Rock Galactic",
+			Weight: 7,
+		},
+	)
+
+	for range 3 {
+		fmt.Printf("%s\n", wr.Next())
+	}
+	// Output:
+	//
+	// Deep Rock Galactic
+	// Deep Rock Galactic
+	// Deep Rock Galactic
+}
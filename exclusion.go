@@ -0,0 +1,31 @@
+package weightedrand
+
+// maxExclusionAttempts is the number of selections attempted by
+// nextExcluding before it falls back to building a reduced table.
+const maxExclusionAttempts = 16
+
+// nextExcluding selects an item that is not excluded, with probabilities
+// proportional to the weights of the remaining items. Selections are
+// rejected and repeated while they are excluded, which keeps the common case
+// constant time; if the excluded items are likely enough that this keeps
+// failing, a table of only the remaining items is built instead. It returns
+// false if every item is excluded.
+func (aliasMethod voseAliasMethodRandom[TItem]) nextExcluding(random RandIntN, excluded func(TItem) bool) (TItem, bool) {
+	for range maxExclusionAttempts {
+		if item := aliasMethod.NextUsing(random); !excluded(item) {
+			return item, true
+		}
+	}
+	remaining := make([]weightedItem[TItem], 0, 2*len(aliasMethod.tuples))
+	for _, item := range aliasMethod.weights() {
+		if !excluded(item.Item) {
+			remaining = append(remaining, item)
+		}
+	}
+	if len(remaining) == 0 {
+		var zero TItem
+		return zero, false
+	}
+	reduced := newVoseAliasMethodFromDecimals(random, remaining)
+	return reduced.NextUsing(random), true
+}
@@ -0,0 +1,175 @@
+package weightedrand
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Explanation describes how the weights of an item became its probability
+// of selection by the alias table built from the items.
+type Explanation[TItem comparable] struct {
+	// Item is the explained item.
+	Item TItem
+	// RawWeights are the weights the item was provided with, once for every
+	// time it occurs among the items.
+	RawWeights []decimal.Decimal
+	// DefaultedToOne reports whether any of the raw weights was zero, and
+	// was thus assumed to be 1.
+	DefaultedToOne bool
+	// EffectiveWeight is the sum of the raw weights, after zero weights are
+	// assumed to be 1.
+	EffectiveWeight decimal.Decimal
+	// TotalWeight is the sum of the effective weights of all items.
+	TotalWeight decimal.Decimal
+	// NormalizationFactor is the number of items divided by the total
+	// weight, which scales the weights into shares of buckets.
+	NormalizationFactor decimal.Decimal
+	// Probability is the probability of the item being selected, which is
+	// its effective weight divided by the total weight.
+	Probability decimal.Decimal
+	// ResolvedProbability is the probability the item is actually selected
+	// with, which is the mean of its shares of the buckets. The coin tosses
+	// of the table have a resolution of 1/100, so it differs from
+	// Probability when a share of a bucket is not a multiple of 1/100.
+	ResolvedProbability decimal.Decimal
+	// Buckets are the buckets of the alias table the item is selected from.
+	Buckets []ExplainedBucket[TItem]
+}
+
+// ExplainedBucket describes a bucket of an alias table selecting the
+// explained item. A bucket is selected by a fair dice roll, and then its
+// item or alias by an unfair coin toss.
+type ExplainedBucket[TItem comparable] struct {
+	// Index is the index of the bucket.
+	Index int
+	// Aliased reports whether the item is the alias of the bucket, rather
+	// than its primary item.
+	Aliased bool
+	// Share is the probability of the item being selected once the bucket
+	// is selected, as resolved by the coin toss of the bucket in steps of
+	// 1/100.
+	Share decimal.Decimal
+	// Other is the item sharing the bucket, if any.
+	Other *TItem
+}
+
+// Explain explains how the weights of the item became its probability of
+// selection by the alias table that NewAliasVoseMethod builds from the
+// items, for debugging why it is selected as often as it is. An item that
+// does not occur among the items is explained with a probability of zero.
+// The shares of the buckets are those the coin tosses of the table resolve
+// to, so that the explanation matches how often the item is selected, even
+// when its probability is below the resolution of the coin tosses.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - items: The WeightedItem values, each containing an item and its associated weight.
+//   - item:  The item to explain.
+//
+// Returns:
+//   - Explanation[TItem]: The explanation, which is also readable as a string.
+//   - error:              If no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	explanation, err := Explain(items, "checkout-v2")
+//	if err != nil {
+//		return err
+//	}
+//	log.Println(explanation)
+func Explain[TItem comparable, TWeight Weight](items []WeightedItem[TItem, TWeight], item TItem) (Explanation[TItem], error) {
+	aliasMethod, err := buildVoseAliasMethod[TItem](nil, items)
+	if err != nil {
+		return Explanation[TItem]{}, err
+	}
+	explanation := Explanation[TItem]{
+		Item:            item,
+		EffectiveWeight: decimal.Zero,
+		TotalWeight:     decimal.Zero,
+	}
+	for _, current := range items {
+		// The weights were validated by building the table.
+		weight := effectiveWeight(current.Weight)
+		explanation.TotalWeight = explanation.TotalWeight.Add(weight)
+		if current.Item != item {
+			continue
+		}
+		raw := WeightAsDecimal(current.Weight)
+		explanation.RawWeights = append(explanation.RawWeights, raw)
+		explanation.DefaultedToOne = explanation.DefaultedToOne || raw.IsZero()
+		explanation.EffectiveWeight = explanation.EffectiveWeight.Add(weight)
+	}
+	explanation.NormalizationFactor = decimal.NewFromInt(int64(len(items))).Div(explanation.TotalWeight)
+	explanation.Probability = explanation.EffectiveWeight.Div(explanation.TotalWeight)
+	// The resolved shares hold the primary item of every bucket, followed
+	// by its alias if it has one, in steps of 1/100.
+	shares := aliasMethod.resolvedShares()
+	resolved := decimal.Zero
+	for index, tuple := range aliasMethod.tuples {
+		primaryShare := shares[0].Weight.Shift(-2)
+		shares = shares[1:]
+		aliasShare := decimal.Zero
+		if tuple.aliasedItem != nil {
+			aliasShare = shares[0].Weight.Shift(-2)
+			shares = shares[1:]
+		}
+		bucket := ExplainedBucket[TItem]{
+			Index: index,
+		}
+		if tuple.primaryItem == item {
+			bucket.Share = primaryShare
+			bucket.Other = tuple.aliasedItem
+		} else if tuple.aliasedItem != nil && *tuple.aliasedItem == item {
+			bucket.Aliased = true
+			bucket.Share = aliasShare
+			bucket.Other = &tuple.primaryItem
+		} else {
+			continue
+		}
+		if bucket.Share.IsPositive() {
+			resolved = resolved.Add(bucket.Share)
+			explanation.Buckets = append(explanation.Buckets, bucket)
+		}
+	}
+	explanation.ResolvedProbability = resolved.Div(decimal.NewFromInt(int64(len(aliasMethod.tuples))))
+	return explanation, nil
+}
+
+func (explanation Explanation[TItem]) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "item %v is selected with probability %s\n", explanation.Item, explanation.Probability.String())
+	if !explanation.ResolvedProbability.Equal(explanation.Probability) {
+		fmt.Fprintf(&builder, "  but the coin tosses resolve it to %s\n", explanation.ResolvedProbability.String())
+	}
+	if len(explanation.RawWeights) == 0 {
+		builder.WriteString("  it does not occur among the items\n")
+		return builder.String()
+	}
+	rawWeights := make([]string, len(explanation.RawWeights))
+	for i, weight := range explanation.RawWeights {
+		rawWeights[i] = weight.String()
+	}
+	fmt.Fprintf(&builder, "  raw weights: %s\n", strings.Join(rawWeights, ", "))
+	if explanation.DefaultedToOne {
+		builder.WriteString("  zero weights were assumed to be 1\n")
+	}
+	fmt.Fprintf(&builder, "  effective weight %s out of a total weight of %s\n", explanation.EffectiveWeight.String(), explanation.TotalWeight.String())
+	fmt.Fprintf(&builder, "  normalization factor: %s\n", explanation.NormalizationFactor.String())
+	for _, bucket := range explanation.Buckets {
+		role := "primary"
+		if bucket.Aliased {
+			role = "alias"
+		}
+		other := "no other item"
+		if bucket.Other != nil {
+			other = fmt.Sprintf("against %v", *bucket.Other)
+		}
+		fmt.Fprintf(&builder, "  bucket %d: %s with share %s, %s\n", bucket.Index, role, bucket.Share.String(), other)
+	}
+	return builder.String()
+}
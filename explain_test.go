@@ -0,0 +1,81 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 0},
+		{Item: Blue, Weight: 2},
+		{Item: Red, Weight: 4},
+	}
+	t.Run("errors", func(t *testing.T) {
+		_, err := Explain[MarbleColor, int](nil, Red)
+		assert.ErrorIs(t, err, ErrNoItems)
+		_, err = Explain([]WeightedItem[MarbleColor, int]{{Item: Red, Weight: -1}}, Red)
+		var negativeErr *ErrNegativeWeight
+		assert.ErrorAs(t, err, &negativeErr)
+	})
+	t.Run("explained items", func(t *testing.T) {
+		for _, color := range []MarbleColor{Red, Green, Blue} {
+			explanation, err := Explain(items, color)
+			require.NoError(t, err)
+			assert.Equal(t, color, explanation.Item)
+			assert.True(t, explanation.TotalWeight.Equal(FixtureDecimal(t, "8")))
+			assert.True(t, explanation.NormalizationFactor.Equal(FixtureDecimal(t, "0.5")))
+			// The shares of the buckets add up to the probability, as every
+			// bucket is selected with the same probability, and every share
+			// is a multiple of 1/100.
+			shares := decimal.Zero
+			for _, bucket := range explanation.Buckets {
+				shares = shares.Add(bucket.Share)
+			}
+			assert.Equal(t, explanation.ResolvedProbability.String(), shares.Div(decimal.NewFromInt(4)).String())
+			assert.True(t, explanation.ResolvedProbability.Equal(explanation.Probability), "%s", explanation)
+			assert.Contains(t, explanation.String(), "is selected with probability")
+		}
+		explanation, err := Explain(items, Green)
+		require.NoError(t, err)
+		assert.True(t, explanation.DefaultedToOne)
+		assert.Contains(t, explanation.String(), "zero weights were assumed to be 1")
+		assert.Equal(t, "0.125", explanation.Probability.String())
+
+		explanation, err = Explain(items, Red)
+		require.NoError(t, err)
+		assert.False(t, explanation.DefaultedToOne)
+		assert.Len(t, explanation.RawWeights, 2)
+		assert.Equal(t, "5", explanation.EffectiveWeight.String())
+	})
+	t.Run("missing item", func(t *testing.T) {
+		explanation, err := Explain(items, Yellow)
+		require.NoError(t, err)
+		assert.True(t, explanation.Probability.IsZero())
+		assert.Empty(t, explanation.Buckets)
+		assert.Contains(t, explanation.String(), "does not occur among the items")
+	})
+	t.Run("resolved shares", func(t *testing.T) {
+		rare := []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 999},
+		}
+		explanation, err := Explain(rare, Red)
+		require.NoError(t, err)
+		assert.Equal(t, "0.001", explanation.Probability.String())
+		// The share of 0.002 of its bucket is resolved to 1/100.
+		require.Len(t, explanation.Buckets, 1)
+		assert.Equal(t, "0.01", explanation.Buckets[0].Share.String())
+		assert.Equal(t, "0.005", explanation.ResolvedProbability.String())
+		assert.Contains(t, explanation.String(), "resolve it to 0.005")
+
+		explanation, err = Explain(rare, Blue)
+		require.NoError(t, err)
+		assert.Equal(t, "0.995", explanation.ResolvedProbability.String())
+	})
+}
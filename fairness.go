@@ -0,0 +1,137 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// GroupShare bounds the share of selections that go to the items of a
+// group, such as the exposure of a protected group in recommendations.
+type GroupShare struct {
+	// Min is the minimum share of selections.
+	Min decimal.Decimal
+	// Max is the maximum share of selections. A zero Max is treated as no
+	// maximum, so that only a minimum can be given.
+	Max decimal.Decimal
+}
+
+// FairWeights adjusts the weights of the items so that the share of each
+// group is within its bounds. The share of every group is its natural share
+// of the total weight scaled by a common factor and then clamped within its
+// bounds, with the factor chosen for the shares to sum to 1, so groups keep
+// their natural shares as far as the bounds allow. Within a group, items
+// keep their relative weights. Groups without bounds may take any share.
+//
+// The adjusted items can be used with any constructor, such as
+// NewAliasVoseMethod or BuildTable.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - items:  The WeightedItem values, each containing an item and its associated weight.
+//   - group:  A function returning the group label of an item.
+//   - shares: The bounds of the share of each group, by group label.
+//
+// Returns:
+//   - []WeightedItem[TItem, decimal.Decimal]: The items with their adjusted weights.
+//   - error: If no items are provided, weights are negative, the bounds are
+//     invalid, or the bounds cannot be satisfied together.
+//
+// Example usage:
+//
+//	adjusted, err := FairWeights(candidates, func(c Candidate) string { return c.Group }, map[string]GroupShare{
+//		"underrepresented": {Min: decimal.RequireFromString("0.3")},
+//		"majority":         {Max: decimal.RequireFromString("0.6")},
+//	})
+func FairWeights[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight], group func(TItem) string, shares map[string]GroupShare) ([]WeightedItem[TItem, decimal.Decimal], error) {
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	weights := make([]decimal.Decimal, len(items))
+	labels := make([]string, len(items))
+	groups := make(map[string]int)
+	var totals []decimal.Decimal
+	for i, item := range items {
+		weight, err := decimalWeight(i, item.Weight)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = weight
+		labels[i] = group(item.Item)
+		index, ok := groups[labels[i]]
+		if !ok {
+			index = len(totals)
+			groups[labels[i]] = index
+			totals = append(totals, decimal.Zero)
+		}
+		totals[index] = totals[index].Add(weight)
+	}
+	lows := make([]decimal.Decimal, len(totals))
+	highs := make([]decimal.Decimal, len(totals))
+	for i := range highs {
+		highs[i] = One
+	}
+	for label, share := range shares {
+		high := share.Max
+		if high.IsZero() {
+			high = One
+		}
+		if share.Min.IsNegative() || share.Min.GreaterThan(high) || high.GreaterThan(One) {
+			return nil, fmt.Errorf("share of group %q must be within 0 <= min <= max <= 1, but was [%s, %s]", label, share.Min.String(), high.String())
+		}
+		index, ok := groups[label]
+		if !ok {
+			if share.Min.IsPositive() {
+				return nil, fmt.Errorf("group %q has a minimum share of %s, but no items", label, share.Min.String())
+			}
+			continue
+		}
+		lows[index], highs[index] = share.Min, high
+	}
+	if sum := decimal.Sum(decimal.Zero, lows...); sum.GreaterThan(One) {
+		return nil, fmt.Errorf("the minimum shares sum to %s, which exceeds 1", sum.String())
+	} else if sum := decimal.Sum(decimal.Zero, highs...); sum.LessThan(One) {
+		return nil, fmt.Errorf("the maximum shares sum to %s, which is less than 1", sum.String())
+	}
+	groupShares := clampedShares(totals, lows, highs)
+	result := make([]WeightedItem[TItem, decimal.Decimal], len(items))
+	for i, item := range items {
+		index := groups[labels[i]]
+		weight := groupShares[index].Mul(weights[i]).DivRound(totals[index], boundsPrecision)
+		if weight.IsZero() {
+			// A weight of zero would default to 1, so keep the smallest
+			// weight the precision allows instead.
+			weight = decimal.New(1, -boundsPrecision)
+		}
+		result[i] = WeightedItem[TItem, decimal.Decimal]{
+			Item:   item.Item,
+			Weight: weight,
+		}
+	}
+	return result, nil
+}
+
+// NewFairSampler constructs a new WeightedRandom instance selecting from the
+// items with the weights adjusted by FairWeights. Unlike the constructors,
+// it returns an error rather than panicking, since whether the bounds can
+// be satisfied often depends on the items available at runtime.
+//
+// Example usage:
+//
+//	wr, err := NewFairSampler(randSource, candidates, func(c Candidate) string { return c.Group }, map[string]GroupShare{
+//		"underrepresented": {Min: decimal.RequireFromString("0.3")},
+//	})
+func NewFairSampler[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], group func(TItem) string, shares map[string]GroupShare) (WeightedRandom[TItem], error) {
+	adjusted, err := FairWeights(items, group, shares)
+	if err != nil {
+		return nil, err
+	}
+	aliasMethod, err := buildVoseAliasMethod(random, adjusted)
+	if err != nil {
+		return nil, err
+	}
+	return aliasMethod, nil
+}
@@ -0,0 +1,93 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairWeights(t *testing.T) {
+	group := func(item int) string {
+		return string(rune('a' + item/10))
+	}
+	items := []WeightedItem[int, int]{
+		{Item: 1, Weight: 80},
+		{Item: 2, Weight: 10},
+		{Item: 11, Weight: 10},
+	}
+	t.Run("errors", func(t *testing.T) {
+		for name, test := range map[string]struct {
+			shares   map[string]GroupShare
+			expected string
+		}{
+			"invalid bounds": {
+				shares:   map[string]GroupShare{"a": {Min: FixtureDecimal(t, "0.6"), Max: FixtureDecimal(t, "0.5")}},
+				expected: `share of group "a" must be within`,
+			},
+			"minimums exceed 1": {
+				shares:   map[string]GroupShare{"a": {Min: FixtureDecimal(t, "0.6")}, "b": {Min: FixtureDecimal(t, "0.5")}},
+				expected: "the minimum shares sum to 1.1",
+			},
+			"maximums below 1": {
+				shares:   map[string]GroupShare{"a": {Max: FixtureDecimal(t, "0.6")}, "b": {Max: FixtureDecimal(t, "0.3")}},
+				expected: "the maximum shares sum to 0.9",
+			},
+			"minimum without items": {
+				shares:   map[string]GroupShare{"c": {Min: FixtureDecimal(t, "0.1")}},
+				expected: `group "c" has a minimum share of 0.1, but no items`,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := FairWeights(items, group, test.shares)
+				assert.ErrorContains(t, err, test.expected)
+				_, err = NewFairSampler(nil, items, group, test.shares)
+				assert.ErrorContains(t, err, test.expected)
+			})
+		}
+		_, err := FairWeights[int, int](nil, group, nil)
+		assert.Error(t, err)
+	})
+	for name, test := range map[string]struct {
+		shares   map[string]GroupShare
+		expected map[int]float64
+	}{
+		"natural shares": {
+			shares:   map[string]GroupShare{"c": {Max: FixtureDecimal(t, "0.5")}},
+			expected: map[int]float64{1: 0.8, 2: 0.1, 11: 0.1},
+		},
+		"minimum": {
+			shares:   map[string]GroupShare{"b": {Min: FixtureDecimal(t, "0.3")}},
+			expected: map[int]float64{1: 0.7 * 8 / 9, 2: 0.7 / 9, 11: 0.3},
+		},
+		"maximum": {
+			shares:   map[string]GroupShare{"a": {Max: FixtureDecimal(t, "0.5")}},
+			expected: map[int]float64{1: 0.5 * 8 / 9, 2: 0.5 / 9, 11: 0.5},
+		},
+		"satisfied bounds": {
+			shares:   map[string]GroupShare{"a": {Min: FixtureDecimal(t, "0.5"), Max: FixtureDecimal(t, "0.95")}},
+			expected: map[int]float64{1: 0.8, 2: 0.1, 11: 0.1},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			adjusted, err := FairWeights(items, group, test.shares)
+			require.NoError(t, err)
+			total := decimal.Zero
+			for _, item := range adjusted {
+				total = total.Add(item.Weight)
+			}
+			assert.InDelta(t, 1.0, total.InexactFloat64(), 1e-9)
+			for _, item := range adjusted {
+				assert.InDeltaf(t, test.expected[item.Item], item.Weight.InexactFloat64(), 1e-9, "%d", item.Item)
+			}
+			wr, err := NewFairSampler(nil, items, group, test.shares)
+			require.NoError(t, err)
+			distribution := DistributionOf(wr)
+			for item, expected := range test.expected {
+				assert.InDeltaf(t, expected, distribution.Probability(item).InexactFloat64(), 1e-9, "%d", item)
+			}
+		})
+	}
+}
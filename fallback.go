@@ -0,0 +1,47 @@
+package weightedrand
+
+// fallbackRandom serves from the primary chooser while it is healthy, and
+// from the secondary otherwise.
+type fallbackRandom[TItem any] struct {
+	primary   WeightedRandom[TItem]
+	secondary WeightedRandom[TItem]
+	healthy   func() bool
+}
+
+// Fallback constructs a WeightedRandom that selects from the primary
+// chooser while the health predicate holds, and transparently switches to
+// the secondary chooser, such as a uniform or static distribution, while it
+// does not. The predicate is evaluated on every selection, so it should
+// return quickly, such as by reading a flag maintained by a health checker.
+//
+// The function panics if any of its arguments is nil.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - primary:   The WeightedRandom instance selected from normally.
+//   - secondary: The WeightedRandom instance selected from in degraded mode.
+//   - healthy:   A function reporting whether the primary should be selected from.
+//
+// Example usage:
+//
+//	var metricsFresh atomic.Bool
+//	wr := Fallback(metricWeighter, NewAliasVoseMethod(randSource, staticItems...), metricsFresh.Load)
+func Fallback[TItem any](primary, secondary WeightedRandom[TItem], healthy func() bool) WeightedRandom[TItem] {
+	if primary == nil || secondary == nil || healthy == nil {
+		panic("primary, secondary and healthy must not be nil")
+	}
+	return fallbackRandom[TItem]{
+		primary:   primary,
+		secondary: secondary,
+		healthy:   healthy,
+	}
+}
+
+func (fallback fallbackRandom[TItem]) Next() TItem {
+	if fallback.healthy() {
+		return fallback.primary.Next()
+	}
+	return fallback.secondary.Next()
+}
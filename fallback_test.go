@@ -0,0 +1,34 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallback(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	red := NewAliasVoseMethod(random, WeightedItem[MarbleColor, int]{Item: Red})
+	blue := NewAliasVoseMethod(random, WeightedItem[MarbleColor, int]{Item: Blue})
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Fallback(red, nil, func() bool { return true })
+		})
+		assert.Panics(t, func() {
+			Fallback(red, blue, nil)
+		})
+	})
+	t.Run("switches with health", func(t *testing.T) {
+		var healthy atomic.Bool
+		healthy.Store(true)
+		wr := Fallback(red, blue, healthy.Load)
+		assert.Equal(t, Red, wr.Next())
+		healthy.Store(false)
+		assert.Equal(t, Blue, wr.Next())
+		healthy.Store(true)
+		assert.Equal(t, Red, wr.Next())
+	})
+}
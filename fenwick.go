@@ -0,0 +1,208 @@
+package weightedrand
+
+import (
+	"math/bits"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Fenwick is a WeightedRandom whose items and weights can change after
+// construction at a cost of O(log n) each, unlike Dynamic, which rebuilds
+// its alias table in O(n) on the next selection after a change. The weights
+// are kept in a Fenwick tree (binary indexed tree) of their prefix sums, so
+// that selections also cost O(log n), rather than the O(1) of the alias
+// method. It suits weights that change as often as they are selected from,
+// such as weights derived from live metrics.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1.
+//
+// Fenwick is safe for concurrent use, given that the random number
+// generator is.
+type Fenwick[TItem comparable, TWeight Weight] struct {
+	mutex   sync.Mutex
+	random  RandIntN
+	items   []TItem
+	weights []decimal.Decimal
+	indices map[TItem]int
+	// tree holds the Fenwick tree of the weights, where tree[i] is the sum
+	// of the weights in (i - lowbit(i), i], indexed from 1.
+	tree []decimal.Decimal
+}
+
+// NewFenwick constructs a new Fenwick instance, which may be empty.
+// Duplicated items are combined, with their weights summed.
+//
+// The function panics if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - items:  The initial WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	f := NewFenwick(randSource, WeightedItem[string, int]{Item: "A", Weight: 2})
+//	f.Add("B", 3)
+//	f.UpdateWeight("A", 5)
+//	f.Remove("B")
+func NewFenwick[TItem comparable, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) *Fenwick[TItem, TWeight] {
+	fenwick := &Fenwick[TItem, TWeight]{
+		random:  random,
+		items:   make([]TItem, 0, len(items)),
+		weights: make([]decimal.Decimal, 0, len(items)),
+		indices: make(map[TItem]int, len(items)),
+	}
+	for _, item := range items {
+		weight := effectiveWeight(item.Weight)
+		if index, ok := fenwick.indices[item.Item]; ok {
+			fenwick.weights[index] = fenwick.weights[index].Add(weight)
+			continue
+		}
+		fenwick.indices[item.Item] = len(fenwick.items)
+		fenwick.items = append(fenwick.items, item.Item)
+		fenwick.weights = append(fenwick.weights, weight)
+	}
+	// Build the tree in O(n), by adding every node into its parent.
+	fenwick.tree = make([]decimal.Decimal, len(fenwick.weights)+1)
+	copy(fenwick.tree[1:], fenwick.weights)
+	for i := 1; i < len(fenwick.tree); i++ {
+		if parent := i + lowbit(i); parent < len(fenwick.tree) {
+			fenwick.tree[parent] = fenwick.tree[parent].Add(fenwick.tree[i])
+		}
+	}
+	return fenwick
+}
+
+// Add adds the item with the weight, or replaces the weight of the item if
+// it was already added.
+//
+// Panics:
+//   - If the weight is negative.
+func (fenwick *Fenwick[TItem, TWeight]) Add(item TItem, weight TWeight) {
+	currentWeight := effectiveWeight(weight)
+	fenwick.mutex.Lock()
+	defer fenwick.mutex.Unlock()
+	if index, ok := fenwick.indices[item]; ok {
+		fenwick.set(index, currentWeight)
+		return
+	}
+	// The node of the new item sums its weight with those of the items it
+	// covers, which precede it.
+	i := len(fenwick.tree)
+	node := currentWeight.Add(fenwick.prefix(i - 1)).Sub(fenwick.prefix(i - lowbit(i)))
+	fenwick.indices[item] = len(fenwick.items)
+	fenwick.items = append(fenwick.items, item)
+	fenwick.weights = append(fenwick.weights, currentWeight)
+	fenwick.tree = append(fenwick.tree, node)
+}
+
+// UpdateWeight replaces the weight of the item, and reports whether it was
+// present. Unlike Add, it does not add missing items.
+//
+// Panics:
+//   - If the weight is negative.
+func (fenwick *Fenwick[TItem, TWeight]) UpdateWeight(item TItem, weight TWeight) bool {
+	currentWeight := effectiveWeight(weight)
+	fenwick.mutex.Lock()
+	defer fenwick.mutex.Unlock()
+	index, ok := fenwick.indices[item]
+	if ok {
+		fenwick.set(index, currentWeight)
+	}
+	return ok
+}
+
+// Remove removes the item, and reports whether it was present.
+func (fenwick *Fenwick[TItem, TWeight]) Remove(item TItem) bool {
+	fenwick.mutex.Lock()
+	defer fenwick.mutex.Unlock()
+	index, ok := fenwick.indices[item]
+	if !ok {
+		return false
+	}
+	// Move the last item into the removed item's place, and clear the weight
+	// of the last node, which no other node covers, before dropping it.
+	last := len(fenwick.items) - 1
+	lastItem, lastWeight := fenwick.items[last], fenwick.weights[last]
+	fenwick.set(last, decimal.Zero)
+	if index != last {
+		fenwick.set(index, lastWeight)
+		fenwick.items[index] = lastItem
+		fenwick.indices[lastItem] = index
+	}
+	fenwick.items = fenwick.items[:last]
+	fenwick.weights = fenwick.weights[:last]
+	fenwick.tree = fenwick.tree[:last+1]
+	delete(fenwick.indices, item)
+	return true
+}
+
+// Len returns the number of items.
+func (fenwick *Fenwick[TItem, TWeight]) Len() int {
+	fenwick.mutex.Lock()
+	defer fenwick.mutex.Unlock()
+	return len(fenwick.items)
+}
+
+// Next selects an item by weight.
+//
+// Panics:
+//   - If there are no items.
+func (fenwick *Fenwick[TItem, TWeight]) Next() TItem {
+	item, ok := fenwick.TryNext()
+	if !ok {
+		panic("there are no items to select from")
+	}
+	return item
+}
+
+// TryNext selects an item by weight. When there are no items, it returns
+// the zero value and false.
+func (fenwick *Fenwick[TItem, TWeight]) TryNext() (TItem, bool) {
+	fenwick.mutex.Lock()
+	defer fenwick.mutex.Unlock()
+	if len(fenwick.items) == 0 {
+		var zero TItem
+		return zero, false
+	}
+	target := uniformDecimal(fenwick.random).Mul(fenwick.prefix(len(fenwick.items)))
+	// Descend the tree to the first item whose prefix sum exceeds the
+	// target, skipping every node whose whole range does not.
+	index := 0
+	for step := 1 << (bits.Len(uint(len(fenwick.items))) - 1); step > 0; step >>= 1 {
+		if next := index + step; next < len(fenwick.tree) && !fenwick.tree[next].GreaterThan(target) {
+			index = next
+			target = target.Sub(fenwick.tree[next])
+		}
+	}
+	// Rounding of the target may leave it at the total weight.
+	return fenwick.items[min(index, len(fenwick.items)-1)], true
+}
+
+// set replaces the weight of the item at the index.
+func (fenwick *Fenwick[TItem, TWeight]) set(index int, weight decimal.Decimal) {
+	delta := weight.Sub(fenwick.weights[index])
+	fenwick.weights[index] = weight
+	for i := index + 1; i < len(fenwick.tree); i += lowbit(i) {
+		fenwick.tree[i] = fenwick.tree[i].Add(delta)
+	}
+}
+
+// prefix returns the sum of the first n weights.
+func (fenwick *Fenwick[TItem, TWeight]) prefix(n int) decimal.Decimal {
+	sum := decimal.Zero
+	for i := n; i > 0; i -= lowbit(i) {
+		sum = sum.Add(fenwick.tree[i])
+	}
+	return sum
+}
+
+// lowbit returns the lowest set bit of i.
+func lowbit(i int) int {
+	return i & -i
+}
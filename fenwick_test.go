@@ -0,0 +1,89 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFenwick(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		f := NewFenwick[MarbleColor, int](nil)
+		assert.PanicsWithValue(t, "there are no items to select from", func() {
+			f.Next()
+		})
+		assert.Panics(t, func() {
+			f.Add(Red, -1)
+		})
+		assert.Panics(t, func() {
+			NewFenwick(nil, WeightedItem[MarbleColor, int]{Item: Red, Weight: -1})
+		})
+	})
+	t.Run("add, update and remove", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		f := NewFenwick(r,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 2},
+		)
+		assert.Equal(t, 2, f.Len())
+		counts := Counts[MarbleColor](f, 100_000)
+		assert.InDeltaf(t, 0.5, float64(counts[Red])/100_000, tolerance, "%v", counts)
+
+		f.Add(Green, 4)
+		f.Add(Yellow, 2)
+		assert.True(t, f.UpdateWeight(Red, 2))
+		assert.False(t, f.UpdateWeight(Orange, 1))
+		counts = Counts[MarbleColor](f, 100_000)
+		assert.Zero(t, counts[Orange])
+		assert.InDeltaf(t, 0.4, float64(counts[Green])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.2, float64(counts[Yellow])/100_000, tolerance, "%v", counts)
+
+		assert.True(t, f.Remove(Red))
+		assert.False(t, f.Remove(Red))
+		assert.Equal(t, 3, f.Len())
+		counts = Counts[MarbleColor](f, 100_000)
+		assert.Zero(t, counts[Red])
+		assert.InDeltaf(t, 0.5, float64(counts[Green])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+
+		f.Add(Green, 1)
+		counts = Counts[MarbleColor](f, 100_000)
+		assert.InDeltaf(t, 0.2, float64(counts[Green])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("remove every item", func(t *testing.T) {
+		f := NewFenwick(rand.New(rand.NewSource(1)),
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+		)
+		assert.True(t, f.Remove(Blue))
+		assert.Equal(t, Red, f.Next())
+		assert.True(t, f.Remove(Red))
+		color, ok := f.TryNext()
+		assert.False(t, ok)
+		assert.Zero(t, color)
+		f.Add(Green, 1)
+		assert.Equal(t, Green, f.Next())
+	})
+	t.Run("many items", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		f := NewFenwick[int, int](r)
+		for i := range 100 {
+			f.Add(i, 1)
+		}
+		for i := range 90 {
+			f.Remove(i)
+		}
+		f.UpdateWeight(99, 11)
+		counts := map[int]int{}
+		for range 100_000 {
+			counts[f.Next()]++
+		}
+		assert.Len(t, counts, 10)
+		assert.InDeltaf(t, 0.55, float64(counts[99])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.05, float64(counts[90])/100_000, tolerance, "%v", counts)
+	})
+}
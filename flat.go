@@ -0,0 +1,74 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// FlatTable is an alias table as flat arrays of primitive types, for sampling
+// outside of Go, such as by a GPU kernel or a cgo or wasm consumer, while
+// tables are built in Go. Bucket i selects Items[i] with probability
+// Probabilities[i], and Items[Aliases[i]] otherwise:
+//
+//	bucket := uniform integer in [0, len(Items))
+//	if uniform float in [0, 1) < Probabilities[bucket] {
+//		return Items[bucket]
+//	}
+//	return Items[Aliases[bucket]]
+type FlatTable[TItem any] struct {
+	Items         []TItem
+	Probabilities []float32
+	Aliases       []uint32
+}
+
+// Flatten exports the alias table as flat arrays. Buckets without an alias
+// have a probability of 1, and are their own alias. Probabilities are
+// rounded to float32 precision.
+//
+// Returns:
+//   - FlatTable[TItem]: The flat arrays of the table.
+//   - error: If the table has more buckets than uint32 can index, or an
+//     alias is not the item of any bucket, which only happens for tables
+//     restored from JSON that was not encoded by MarshalJSON.
+//
+// Example usage:
+//
+//	flat, err := Flatten(table)
+//	if err != nil {
+//		return err
+//	}
+//	upload(flat.Probabilities, flat.Aliases)
+func Flatten[TItem comparable](table *Table[TItem]) (FlatTable[TItem], error) {
+	tuples := table.aliasMethod.tuples
+	if uint64(len(tuples)) > math.MaxUint32 {
+		return FlatTable[TItem]{}, fmt.Errorf("the table has %d buckets, which exceeds what uint32 can index", len(tuples))
+	}
+	flat := FlatTable[TItem]{
+		Items:         make([]TItem, len(tuples)),
+		Probabilities: make([]float32, len(tuples)),
+		Aliases:       make([]uint32, len(tuples)),
+	}
+	// Every item of the table is the primary item of a bucket, and duplicated
+	// items are interchangeable, so any bucket of an item serves as its alias.
+	buckets := make(map[TItem]uint32, len(tuples))
+	for i, tuple := range tuples {
+		flat.Items[i] = tuple.primaryItem
+		if _, ok := buckets[tuple.primaryItem]; !ok {
+			buckets[tuple.primaryItem] = uint32(i)
+		}
+	}
+	for i, tuple := range tuples {
+		flat.Probabilities[i] = float32(tuple.probability.InexactFloat64())
+		flat.Aliases[i] = uint32(i)
+		if tuple.aliasedItem == nil || tuple.probability.Equal(One) {
+			flat.Probabilities[i] = 1
+			continue
+		}
+		alias, ok := buckets[*tuple.aliasedItem]
+		if !ok {
+			return FlatTable[TItem]{}, fmt.Errorf("the alias %v of bucket %d is not the item of any bucket", *tuple.aliasedItem, i)
+		}
+		flat.Aliases[i] = alias
+	}
+	return flat, nil
+}
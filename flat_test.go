@@ -0,0 +1,47 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatten(t *testing.T) {
+	table, err := BuildTable(
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Green, Weight: 2},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 7},
+	)
+	require.NoError(t, err)
+	flat, err := Flatten(table)
+	require.NoError(t, err)
+	assert.Len(t, flat.Items, 3)
+	assert.Len(t, flat.Probabilities, 3)
+	assert.Len(t, flat.Aliases, 3)
+	t.Run("samples the weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			bucket := r.Intn(len(flat.Items))
+			if r.Float32() < flat.Probabilities[bucket] {
+				counts[flat.Items[bucket]] += 1
+			} else {
+				counts[flat.Items[flat.Aliases[bucket]]] += 1
+			}
+		}
+		assert.InDeltaf(t, 0.1, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.2, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.7, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+	t.Run("invalid alias", func(t *testing.T) {
+		var restored Table[MarbleColor]
+		require.NoError(t, restored.UnmarshalJSON([]byte(`[{"probability":"0.5","item":"red","alias":"blue"}]`)))
+		_, err := Flatten(&restored)
+		assert.ErrorContains(t, err, "is not the item of any bucket")
+	})
+}
@@ -0,0 +1,119 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// float64Resolution is the number of distinct values the unfair coin toss of
+// the float64 alias method can take, matching the precision of a float64.
+const float64Resolution = 1 << 53
+
+type float64AliasMethodRandom[TItem any] struct {
+	random        RandIntN
+	items         []TItem
+	probabilities []float64
+	aliases       []int
+}
+
+// NewFloat64AliasMethod constructs a new WeightedRandom instance using the
+// Alias Method (Vose's algorithm), built entirely with float64 arithmetic.
+// It is intended for probabilities that were computed upstream, where the
+// exact decimal semantics of NewAliasVoseMethod are not needed and its cost
+// is not wanted.
+//
+// The probabilities are expected to be normalized already, but are scaled
+// by their sum to absorb rounding errors. Unlike the weights of the other
+// constructors, a probability of zero is never selected.
+//
+// The function panics if no items are provided, if the number of items and
+// probabilities differ, if any probability is negative, NaN or infinite, or
+// if every probability is zero. The items are copied, so later changes to
+// the slice do not affect selection.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - random:        A RandIntN implementation used for random number generation.
+//   - items:         The items to be sampled.
+//   - probabilities: The probability of each item, at the same index.
+//
+// Example usage:
+//
+//	wr := NewFloat64AliasMethod(randSource, []string{"A", "B"}, []float64{0.25, 0.75})
+func NewFloat64AliasMethod[TItem any](random RandIntN, items []TItem, probabilities []float64) WeightedRandom[TItem] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	} else if len(items) != len(probabilities) {
+		panic(fmt.Sprintf("%d items were provided with %d probabilities", len(items), len(probabilities)))
+	}
+	total := 0.0
+	for _, probability := range probabilities {
+		if probability < 0 || math.IsNaN(probability) || math.IsInf(probability, 0) {
+			panic(fmt.Sprintf("probability must be a finite non-negative value, but was %v", probability))
+		}
+		total += probability
+	}
+	if total == 0 {
+		panic("at least one probability must be positive")
+	}
+
+	count := len(items)
+	aliasMethod := float64AliasMethodRandom[TItem]{
+		random:        random,
+		items:         slices.Clone(items),
+		probabilities: make([]float64, count),
+		aliases:       make([]int, count),
+	}
+	// Scale the probabilities so they average to 1, and partition the
+	// indices into the two worklists, Small and Large.
+	scaled := make([]float64, count)
+	small := make([]int, 0, count)
+	large := make([]int, 0, count)
+	for i, probability := range probabilities {
+		scaled[i] = probability * float64(count) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		lesser, greater := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		aliasMethod.probabilities[lesser] = scaled[lesser]
+		aliasMethod.aliases[lesser] = greater
+		scaled[greater] = scaled[greater] + scaled[lesser] - 1
+		if scaled[greater] < 1 {
+			small = append(small, greater)
+		} else {
+			large = append(large, greater)
+		}
+	}
+	// Whatever remains is only due to rounding errors, and is certain to
+	// select itself.
+	for _, i := range append(small, large...) {
+		aliasMethod.probabilities[i] = 1
+		aliasMethod.aliases[i] = i
+	}
+	return aliasMethod
+}
+
+func (aliasMethod float64AliasMethodRandom[TItem]) Next() TItem {
+	return aliasMethod.NextUsing(aliasMethod.random)
+}
+
+// NextUsing selects an item by weight, using the random number generator
+// instead of the one provided at construction.
+func (aliasMethod float64AliasMethodRandom[TItem]) NextUsing(random RandIntN) TItem {
+	// First, perform a fair dice roll.
+	fairDiceRoll := random.Intn(len(aliasMethod.items))
+	// Second, perform an unfair coin toss.
+	unfairCoinToss := float64(random.Int63n(float64Resolution)) / float64Resolution
+	if unfairCoinToss < aliasMethod.probabilities[fairDiceRoll] {
+		return aliasMethod.items[fairDiceRoll]
+	}
+	return aliasMethod.items[aliasMethod.aliases[fairDiceRoll]]
+}
@@ -0,0 +1,78 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkFloat64AliasMethod(b *testing.B) {
+	items := make([]int, 10_000)
+	probabilities := make([]float64, len(items))
+	for i := range items {
+		items[i] = i
+		probabilities[i] = float64(i+1) / 50_005_000
+	}
+	b.Run("build", func(b *testing.B) {
+		for b.Loop() {
+			_ = NewFloat64AliasMethod(nil, items, probabilities)
+		}
+	})
+	b.Run("next", func(b *testing.B) {
+		wr := NewFloat64AliasMethod(rand.New(rand.NewSource(1)), items, probabilities)
+		for b.Loop() {
+			_ = wr.Next()
+		}
+	})
+}
+
+func TestFloat64AliasMethod(t *testing.T) {
+	colors := []MarbleColor{Red, Green, Blue}
+	t.Run("panic", func(t *testing.T) {
+		testcases := map[string][]float64{
+			"mismatched lengths": {0.5, 0.5},
+			"negative":           {0.5, 0.6, -0.1},
+			"nan":                {0.5, 0.5, math.NaN()},
+			"infinite":           {0.5, 0.5, math.Inf(1)},
+			"all zero":           {0, 0, 0},
+		}
+		for name, probabilities := range testcases {
+			t.Run(name, func(t *testing.T) {
+				assert.Panics(t, func() {
+					NewFloat64AliasMethod(nil, colors, probabilities)
+				})
+			})
+		}
+		t.Run("no items", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewFloat64AliasMethod[MarbleColor](nil, nil, nil)
+			})
+		})
+	})
+	t.Run("probabilities", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		expected := []float64{0.1, 0, 0.9}
+		wr := NewFloat64AliasMethod(r, colors, expected)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		assert.Zerof(t, counts[Green], "%s", counts)
+		for i, color := range colors {
+			assert.InDeltaf(t, expected[i], float64(counts[color])/iterations, tolerance, "%s", counts)
+		}
+	})
+	t.Run("items are copied", func(t *testing.T) {
+		items := []MarbleColor{Red, Blue}
+		wr := NewFloat64AliasMethod(rand.New(rand.NewSource(1)), items, []float64{1, 0})
+		items[0] = Green
+		for range 100 {
+			assert.Equal(t, Red, wr.Next())
+		}
+	})
+}
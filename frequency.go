@@ -0,0 +1,153 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FrequencyCap is a WeightedRandom that limits how many times each item may
+// be selected within a sliding time window, such as at most 100 times per
+// hour. Once an item reaches its cap, it is excluded and the distribution
+// is renormalized over the remaining items until enough of its selections
+// fall out of the window.
+//
+// FrequencyCap is safe for concurrent use, given that the random number
+// generator is.
+type FrequencyCap[TItem comparable] struct {
+	mutex      sync.Mutex
+	clock      Clock
+	table      *adjustableTable[TItem]
+	indices    map[TItem][]int
+	caps       map[TItem]frequencyLimit
+	selections map[TItem][]time.Time
+}
+
+type frequencyLimit struct {
+	max    int
+	window time.Duration
+}
+
+// NewFrequencyCap constructs a new FrequencyCap instance. No item is capped
+// until one is configured with WithCap.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - clock:  A Clock implementation used to track the time window.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	fc := NewFrequencyCap(randSource, SystemClock, WeightedItem[string, int]{Item: "promo", Weight: 5}, WeightedItem[string, int]{Item: "house", Weight: 1}).
+//		WithCap("promo", 100, time.Hour)
+func NewFrequencyCap[TItem comparable, TWeight Weight](random RandIntN, clock Clock, items ...WeightedItem[TItem, TWeight]) *FrequencyCap[TItem] {
+	frequencyCap := &FrequencyCap[TItem]{
+		clock:      clock,
+		table:      newAdjustableTable(random, items),
+		indices:    make(map[TItem][]int, len(items)),
+		caps:       make(map[TItem]frequencyLimit),
+		selections: make(map[TItem][]time.Time),
+	}
+	for index, item := range items {
+		frequencyCap.indices[item.Item] = append(frequencyCap.indices[item.Item], index)
+	}
+	return frequencyCap
+}
+
+// WithCap limits the item to at most max selections within any window of
+// the given duration. It returns the FrequencyCap to allow chaining.
+//
+// Panics:
+//   - If max is negative or the window is not positive.
+func (frequencyCap *FrequencyCap[TItem]) WithCap(item TItem, max int, window time.Duration) *FrequencyCap[TItem] {
+	if max < 0 {
+		panic(fmt.Sprintf("cap must be non-negative value, but was %d", max))
+	} else if window <= 0 {
+		panic(fmt.Sprintf("window must be positive value, but was %s", window))
+	}
+	frequencyCap.mutex.Lock()
+	defer frequencyCap.mutex.Unlock()
+	frequencyCap.caps[item] = frequencyLimit{
+		max:    max,
+		window: window,
+	}
+	frequencyCap.refresh(item, frequencyCap.clock.Now())
+	return frequencyCap
+}
+
+// Next selects an item from the items that have not reached their cap.
+//
+// Panics:
+//   - If every item has reached its cap. Use TryNext to avoid this.
+func (frequencyCap *FrequencyCap[TItem]) Next() TItem {
+	item, ok := frequencyCap.TryNext()
+	if !ok {
+		panic("every item has reached its frequency cap")
+	}
+	return item
+}
+
+// TryNext selects an item from the items that have not reached their cap.
+// If every item has reached its cap, it returns false.
+func (frequencyCap *FrequencyCap[TItem]) TryNext() (TItem, bool) {
+	frequencyCap.mutex.Lock()
+	defer frequencyCap.mutex.Unlock()
+	now := frequencyCap.clock.Now()
+	for item := range frequencyCap.caps {
+		frequencyCap.refresh(item, now)
+	}
+	index, ok := frequencyCap.table.next()
+	if !ok {
+		var zero TItem
+		return zero, false
+	}
+	item := frequencyCap.table.item(index)
+	if _, ok := frequencyCap.caps[item]; ok {
+		frequencyCap.selections[item] = append(frequencyCap.selections[item], now)
+		frequencyCap.refresh(item, now)
+	}
+	return item, true
+}
+
+// Selections reports how many times the item was selected within its
+// current window. Items without a cap always report zero.
+func (frequencyCap *FrequencyCap[TItem]) Selections(item TItem) int {
+	frequencyCap.mutex.Lock()
+	defer frequencyCap.mutex.Unlock()
+	frequencyCap.refresh(item, frequencyCap.clock.Now())
+	return len(frequencyCap.selections[item])
+}
+
+// refresh discards the selections of the item that fell out of its window,
+// and excludes or restores the item depending on whether it is at its cap.
+func (frequencyCap *FrequencyCap[TItem]) refresh(item TItem, now time.Time) {
+	limit, ok := frequencyCap.caps[item]
+	if !ok {
+		return
+	}
+	selections := frequencyCap.selections[item]
+	expired := 0
+	for expired < len(selections) && !now.Before(selections[expired].Add(limit.window)) {
+		expired++
+	}
+	selections = selections[expired:]
+	if len(selections) == 0 {
+		delete(frequencyCap.selections, item)
+	} else {
+		frequencyCap.selections[item] = selections
+	}
+	capped := len(selections) >= limit.max
+	for _, index := range frequencyCap.indices[item] {
+		if capped {
+			frequencyCap.table.exclude(index)
+		} else {
+			frequencyCap.table.restore(index)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequencyCap(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1_000},
+	}
+	t.Run("panic", func(t *testing.T) {
+		fc := NewFrequencyCap(nil, SystemClock, items...)
+		assert.Panics(t, func() {
+			fc.WithCap(Blue, -1, time.Hour)
+		})
+		assert.Panics(t, func() {
+			fc.WithCap(Blue, 1, 0)
+		})
+	})
+	t.Run("renormalizes once capped", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		fc := NewFrequencyCap(r, clock, items...).
+			WithCap(Blue, 10, time.Hour)
+		counts := make(MarbleColorCounts)
+		for range 1_000 {
+			counts[fc.Next()] += 1
+			clock.Advance(time.Second)
+		}
+		assert.Equalf(t, int64(10), counts[Blue], "%s", counts)
+		assert.Equal(t, 10, fc.Selections(Blue))
+	})
+	t.Run("sliding window", func(t *testing.T) {
+		clock := NewFixtureClock()
+		fc := NewFrequencyCap(rand.New(rand.NewSource(1)), clock, items[1:]...).
+			WithCap(Blue, 2, time.Minute)
+		assert.Equal(t, Blue, fc.Next())
+		clock.Advance(30 * time.Second)
+		assert.Equal(t, Blue, fc.Next())
+		_, ok := fc.TryNext()
+		assert.False(t, ok)
+		clock.Advance(30 * time.Second)
+		assert.Equal(t, 1, fc.Selections(Blue))
+		assert.Equal(t, Blue, fc.Next())
+		_, ok = fc.TryNext()
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,52 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NewFromMap constructs a new WeightedRandom instance from a map of items to
+// their weights, using the Alias Method (Vose's algorithm) configured by the
+// options, like NewAliasVoseMethodWithOptions. The items are ordered by
+// their formatted representation (%v) before the alias table is built, so
+// that the table, and the selections under a fixed seed, do not depend on
+// the iteration order of the map. Items printed alike, such as distinct
+// pointers, are not ordered by it; their order must not matter.
+//
+// The function panics under the same conditions as
+// NewAliasVoseMethodWithOptions, including when the map is empty.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - weights: The items mapped to their associated weights.
+//   - opts:    A variadic list of Option and ItemOption values.
+//
+// Example usage:
+//
+//	wr := NewFromMap(randSource, map[string]int{"A": 2, "B": 3})
+func NewFromMap[TItem comparable, TWeight Weight](random RandIntN, weights map[TItem]TWeight, opts ...ItemOption[TItem]) WeightedRandom[TItem] {
+	return NewAliasVoseMethodWithOptions(random, mapItems(weights), opts...)
+}
+
+// mapItems returns the items of the map ordered by their formatted
+// representation.
+func mapItems[TItem comparable, TWeight Weight](weights map[TItem]TWeight) []WeightedItem[TItem, TWeight] {
+	keys := make(map[TItem]string, len(weights))
+	items := make([]WeightedItem[TItem, TWeight], 0, len(weights))
+	for item, weight := range weights {
+		keys[item] = fmt.Sprintf("%v", item)
+		items = append(items, WeightedItem[TItem, TWeight]{
+			Item:   item,
+			Weight: weight,
+		})
+	}
+	slices.SortFunc(items, func(a, b WeightedItem[TItem, TWeight]) int {
+		return strings.Compare(keys[a.Item], keys[b.Item])
+	})
+	return items
+}
@@ -0,0 +1,45 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromMap(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewFromMap[MarbleColor, int](nil, nil)
+		})
+		assert.Panics(t, func() {
+			NewFromMap(nil, map[MarbleColor]int{Red: -1})
+		})
+	})
+	t.Run("reproducible", func(t *testing.T) {
+		weights := map[int]int{}
+		for i := range 100 {
+			weights[i] = i%7 + 1
+		}
+		var expected []int
+		for range 5 {
+			wr := NewFromMap(rand.New(rand.NewSource(1)), weights)
+			selections := make([]int, 1_000)
+			for i := range selections {
+				selections[i] = wr.Next()
+			}
+			if expected == nil {
+				expected = selections
+			}
+			assert.Equal(t, expected, selections)
+		}
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromMap(r, map[MarbleColor]int{Red: 1, Blue: 3}, WithIntegerEngine())
+		counts := Counts[MarbleColor](wr, 100_000)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+}
@@ -0,0 +1,142 @@
+// Package geo generates synthetic locations: points sampled within regions,
+// such as bounding boxes or polygons, that are selected by weight.
+package geo
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nikole-dunixi/weightedrand"
+)
+
+// maxAttempts is the number of points a Polygon samples within its bounds
+// before it concludes that it contains none of them.
+const maxAttempts = 10_000
+
+// Point is a location in degrees of latitude and longitude.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+func (point Point) String() string {
+	return fmt.Sprintf("(%.6f, %.6f)", point.Lat, point.Lon)
+}
+
+// Region is an area points can be sampled within.
+type Region interface {
+	// Sample returns a point within the region, uniformly distributed by
+	// area.
+	Sample(random weightedrand.RandIntN) Point
+}
+
+// Box is a region bounded by two parallels and two meridians. A box whose
+// western bound is east of its eastern bound crosses the antimeridian.
+type Box struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// validate panics if the bounds of the box are out of range.
+func (box Box) validate() {
+	if !(-90 <= box.South && box.South <= box.North && box.North <= 90) {
+		panic(fmt.Sprintf("latitudes must be ordered within [-90, 90], but were %g and %g", box.South, box.North))
+	} else if !(-180 <= box.West && box.West <= 180 && -180 <= box.East && box.East <= 180) {
+		panic(fmt.Sprintf("longitudes must be within [-180, 180], but were %g and %g", box.West, box.East))
+	}
+}
+
+// Sample returns a point within the box, uniformly distributed by area on
+// the sphere, so that points do not crowd towards the poles.
+func (box Box) Sample(random weightedrand.RandIntN) Point {
+	// The area of a band of latitude is proportional to the difference of
+	// the sines of its bounds.
+	south, north := math.Sin(box.South*math.Pi/180), math.Sin(box.North*math.Pi/180)
+	lat := math.Asin(south+uniform(random)*(north-south)) * 180 / math.Pi
+	width := box.East - box.West
+	if width < 0 {
+		width += 360
+	}
+	lon := box.West + uniform(random)*width
+	if lon > 180 {
+		lon -= 360
+	}
+	return Point{
+		Lat: lat,
+		Lon: lon,
+	}
+}
+
+// Polygon is a region of any shape, such as a polygon or a multipolygon,
+// described by its bounding box and a function reporting whether it
+// contains a point, such as one provided by a geometry library. Points are
+// sampled within the bounds until one is contained, so the polygon should
+// fill a reasonable share of its bounds.
+type Polygon struct {
+	Bounds   Box
+	Contains func(Point) bool
+}
+
+// Sample returns a point within the polygon, uniformly distributed by area
+// on the sphere.
+//
+// Panics:
+//   - If none of many points sampled within the bounds is contained.
+func (polygon Polygon) Sample(random weightedrand.RandIntN) Point {
+	for range maxAttempts {
+		if point := polygon.Bounds.Sample(random); polygon.Contains(point) {
+			return point
+		}
+	}
+	panic(fmt.Sprintf("the polygon contains none of %d points sampled within its bounds", maxAttempts))
+}
+
+// Sampler samples points within regions selected by weight, such as the
+// locations of synthetic users distributed like a population.
+type Sampler struct {
+	random  weightedrand.RandIntN
+	regions weightedrand.WeightedRandom[Region]
+}
+
+// New constructs a Sampler over the regions.
+//
+// The function panics if no regions are provided, the bounds of a region
+// are out of range, a polygon has no containment function, or weights are
+// negative.
+//
+// Example usage:
+//
+//	s := geo.New(randSource,
+//		weightedrand.WeightedItem[geo.Region, int]{Item: geo.Box{South: 40.5, West: -74.3, North: 40.9, East: -73.7}, Weight: 8},
+//		weightedrand.WeightedItem[geo.Region, int]{Item: geo.Polygon{Bounds: alpsBounds, Contains: alps.Contains}, Weight: 2},
+//	)
+//	location := s.Next()
+func New[TWeight weightedrand.Weight](random weightedrand.RandIntN, regions ...weightedrand.WeightedItem[Region, TWeight]) *Sampler {
+	for _, region := range regions {
+		switch region := region.Item.(type) {
+		case Box:
+			region.validate()
+		case Polygon:
+			region.Bounds.validate()
+			if region.Contains == nil {
+				panic("polygon must have a containment function")
+			}
+		}
+	}
+	return &Sampler{
+		random:  random,
+		regions: weightedrand.NewAliasVoseMethod(random, regions...),
+	}
+}
+
+// Next selects a region by weight, and returns a point sampled within it.
+func (sampler *Sampler) Next() Point {
+	return sampler.regions.Next().Sample(sampler.random)
+}
+
+// uniform returns a uniformly distributed float64 within [0, 1).
+func uniform(random weightedrand.RandIntN) float64 {
+	return float64(random.Int63n(1<<53)) / (1 << 53)
+}
@@ -0,0 +1,79 @@
+package geo_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"github.com/nikole-dunixi/weightedrand/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	west := geo.Box{South: 0, West: -10, North: 10, East: 0}
+	east := geo.Box{South: 0, West: 170, North: 10, East: -170}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			geo.New[int](r)
+		})
+		assert.Panics(t, func() {
+			geo.New(r, weightedrand.WeightedItem[geo.Region, int]{Item: geo.Box{South: 10, North: 0}})
+		})
+		assert.Panics(t, func() {
+			geo.New(r, weightedrand.WeightedItem[geo.Region, int]{Item: geo.Box{West: -190}})
+		})
+		assert.Panics(t, func() {
+			geo.New(r, weightedrand.WeightedItem[geo.Region, int]{Item: geo.Polygon{Bounds: west}})
+		})
+		assert.Panics(t, func() {
+			geo.Polygon{Bounds: west, Contains: func(geo.Point) bool { return false }}.Sample(r)
+		})
+	})
+	t.Run("weighted regions", func(t *testing.T) {
+		s := geo.New(r,
+			weightedrand.WeightedItem[geo.Region, int]{Item: west, Weight: 1},
+			weightedrand.WeightedItem[geo.Region, int]{Item: east, Weight: 3},
+		)
+		const iterations = 10_000
+		inEast := 0
+		for range iterations {
+			point := s.Next()
+			assert.GreaterOrEqual(t, point.Lat, 0.0)
+			assert.Less(t, point.Lat, 10.0)
+			if point.Lon >= 170 || point.Lon < -170 {
+				inEast++
+			} else {
+				assert.GreaterOrEqual(t, point.Lon, -10.0)
+				assert.Less(t, point.Lon, 0.0)
+			}
+		}
+		assert.InDelta(t, 0.75, float64(inEast)/iterations, 0.02)
+	})
+	t.Run("uniform by area", func(t *testing.T) {
+		box := geo.Box{South: 0, West: 0, North: 90, East: 10}
+		const iterations = 100_000
+		below30 := 0
+		for range iterations {
+			if box.Sample(r).Lat < 30 {
+				below30++
+			}
+		}
+		// The band below 30 degrees holds half the area of the hemisphere.
+		assert.InDelta(t, 0.5, float64(below30)/iterations, 0.01)
+	})
+	t.Run("polygon", func(t *testing.T) {
+		circle := geo.Polygon{
+			Bounds: geo.Box{South: -1, West: -1, North: 1, East: 1},
+			Contains: func(point geo.Point) bool {
+				return math.Hypot(point.Lat, point.Lon) <= 1
+			},
+		}
+		for range 1_000 {
+			point := circle.Sample(r)
+			assert.LessOrEqual(t, math.Hypot(point.Lat, point.Lon), 1.0)
+		}
+	})
+}
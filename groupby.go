@@ -0,0 +1,79 @@
+package weightedrand
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// GroupTally aggregates the selections of the items of a group, such as a
+// category, team or region, against what their weights lead to expect.
+type GroupTally struct {
+	// Observed is the number of times items of the group were selected.
+	Observed int
+	// Proportion is the share of all selections that were of the group.
+	Proportion float64
+	// Expected is the number of selections of the group the probabilities
+	// of its items lead to expect, out of as many selections as counted.
+	Expected float64
+	// ExpectedProportion is the probability of selecting an item of the
+	// group.
+	ExpectedProportion float64
+}
+
+// GroupBy aggregates the counts of the selections made from the chooser,
+// such as those returned by Counts, by a key of their items, and compares
+// them with the probabilities of the chooser at the level of the groups.
+// Groups of items that are never selected are reported with their
+// expectation, and items that were counted without belonging to the
+// distribution count towards their group with no expectation.
+//
+// Panics:
+//   - If the chooser was not constructed by this package, and its
+//     distribution cannot be determined.
+//
+// Type Parameters:
+//   - TItem: The type of the items that were selected.
+//   - TKey:  The type of the keys the items are grouped by.
+//
+// Parameters:
+//   - wr:     The WeightedRandom instance the selections were made from.
+//   - counts: The number of times each item was selected.
+//   - key:    A function returning the group of an item.
+//
+// Example usage:
+//
+//	byRegion := GroupBy(wr, Counts(wr, 100_000), func(backend Backend) string { return backend.Region })
+//	for region, tally := range byRegion {
+//		fmt.Printf("%s: %.1f%% (expected %.1f%%)\n", region, 100*tally.Proportion, 100*tally.ExpectedProportion)
+//	}
+func GroupBy[TItem comparable, TKey comparable](wr WeightedRandom[TItem], counts map[TItem]int, key func(TItem) TKey) map[TKey]GroupTally {
+	weights := mergedWeights(distributionOf(wr))
+	totalWeight := decimal.Zero
+	for _, weight := range weights {
+		totalWeight = totalWeight.Add(weight)
+	}
+	totalCount := 0
+	for _, count := range counts {
+		totalCount += count
+	}
+	tallies := make(map[TKey]GroupTally)
+	for item, weight := range weights {
+		group := key(item)
+		tally := tallies[group]
+		tally.ExpectedProportion += weight.Div(totalWeight).InexactFloat64()
+		tallies[group] = tally
+	}
+	for item, count := range counts {
+		group := key(item)
+		tally := tallies[group]
+		tally.Observed += count
+		tallies[group] = tally
+	}
+	for group, tally := range tallies {
+		tally.Expected = tally.ExpectedProportion * float64(totalCount)
+		if totalCount > 0 {
+			tally.Proportion = float64(tally.Observed) / float64(totalCount)
+		}
+		tallies[group] = tally
+	}
+	return tallies
+}
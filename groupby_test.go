@@ -0,0 +1,48 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupBy(t *testing.T) {
+	warm := func(color MarbleColor) bool {
+		return color == Red || color == Orange || color == Yellow
+	}
+	wr := NewAliasVoseMethod(rand.New(rand.NewSource(time.Now().Unix())),
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Orange, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 2},
+		WeightedItem[MarbleColor, int]{Item: Yellow, Weight: 0},
+	)
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			GroupBy(opaqueWeightedRandom{}, nil, func(int) int { return 0 })
+		})
+	})
+	t.Run("without selections", func(t *testing.T) {
+		tallies := GroupBy(wr, nil, warm)
+		assert.InDelta(t, 0.6, tallies[true].ExpectedProportion, 1e-9)
+		assert.InDelta(t, 0.4, tallies[false].ExpectedProportion, 1e-9)
+		assert.Zero(t, tallies[true].Observed)
+		assert.Zero(t, tallies[true].Expected)
+	})
+	t.Run("selections", func(t *testing.T) {
+		const iterations = 100_000
+		tallies := GroupBy(wr, Counts(wr, iterations), warm)
+		assert.Len(t, tallies, 2)
+		assert.Equal(t, iterations, tallies[true].Observed+tallies[false].Observed)
+		assert.InDelta(t, 0.6*iterations, tallies[true].Expected, 1e-6)
+		assert.InDelta(t, 0.6, tallies[true].Proportion, tolerance)
+		assert.InDelta(t, 0.4, tallies[false].Proportion, tolerance)
+	})
+	t.Run("items outside the distribution", func(t *testing.T) {
+		tallies := GroupBy(wr, map[MarbleColor]int{Green: 4}, func(color MarbleColor) MarbleColor { return color })
+		assert.Equal(t, GroupTally{Observed: 4, Proportion: 1}, tallies[Green])
+		assert.Equal(t, 4.0*0.4, tallies[Blue].Expected)
+	})
+}
@@ -0,0 +1,118 @@
+package weightedrand
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Hedger selects items for hedged requests: a primary item by weight, and if
+// needed, a secondary item by weight among every item except the primary.
+//
+// Hedger is safe for concurrent use, given that the random number generator
+// is.
+type Hedger[TItem comparable] struct {
+	mutex       sync.Mutex
+	random      RandIntN
+	aliasMethod voseAliasMethodRandom[TItem]
+}
+
+// NewHedger constructs a new Hedger.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	h := NewHedger(randSource, WeightedItem[string, int]{Item: "a", Weight: 2}, WeightedItem[string, int]{Item: "b", Weight: 3})
+//	response, err := Hedge(ctx, h, 50*time.Millisecond, fetch)
+func NewHedger[TItem comparable, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) *Hedger[TItem] {
+	return &Hedger[TItem]{
+		random:      random,
+		aliasMethod: newVoseAliasMethod(random, items),
+	}
+}
+
+// Next selects an item by weight.
+func (hedger *Hedger[TItem]) Next() TItem {
+	hedger.mutex.Lock()
+	defer hedger.mutex.Unlock()
+	return hedger.aliasMethod.Next()
+}
+
+// NextExcluding selects an item by weight among every item except the
+// excluded one. It returns false if there is no other item.
+func (hedger *Hedger[TItem]) NextExcluding(excluded TItem) (TItem, bool) {
+	hedger.mutex.Lock()
+	defer hedger.mutex.Unlock()
+	return hedger.aliasMethod.nextExcluding(hedger.random, func(item TItem) bool {
+		return item == excluded
+	})
+}
+
+type hedgeResult[TResult any] struct {
+	value TResult
+	err   error
+}
+
+// Hedge calls do with a primary item selected by the Hedger. If the primary
+// has not succeeded after the delay, or fails before it, do is additionally
+// called with a distinct secondary item. The first successful result is
+// returned, and the context passed to the other call is canceled. If every
+// call fails, the last error is returned.
+//
+// Example usage:
+//
+//	body, err := Hedge(ctx, h, 50*time.Millisecond, func(ctx context.Context, backend string) ([]byte, error) {
+//		return fetch(ctx, backend)
+//	})
+func Hedge[TItem comparable, TResult any](
+	ctx context.Context, hedger *Hedger[TItem], delay time.Duration, do func(ctx context.Context, item TItem) (TResult, error),
+) (TResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Buffered, so that the call that does not win can always complete.
+	results := make(chan hedgeResult[TResult], 2)
+	launch := func(item TItem) {
+		go func() {
+			value, err := do(ctx, item)
+			results <- hedgeResult[TResult]{value: value, err: err}
+		}()
+	}
+
+	primary := hedger.Next()
+	launch(primary)
+	pending := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	hedge := timer.C
+	launchSecondary := func() {
+		hedge = nil
+		if secondary, ok := hedger.NextExcluding(primary); ok {
+			launch(secondary)
+			pending++
+		}
+	}
+
+	var zero TResult
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-hedge:
+			launchSecondary()
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.value, nil
+			}
+			lastErr = result.err
+			if hedge != nil {
+				launchSecondary()
+			}
+			if pending == 0 {
+				return zero, lastErr
+			}
+		}
+	}
+}
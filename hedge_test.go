@@ -0,0 +1,86 @@
+package weightedrand_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedger(t *testing.T) {
+	t.Run("next excluding", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		h := NewHedger(r,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1_000},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		counts := make(MarbleColorCounts)
+		for range 10_000 {
+			color, ok := h.NextExcluding(Red)
+			assert.True(t, ok)
+			counts[color] += 1
+		}
+		assert.Zero(t, counts[Red])
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/10_000, tolerance, "%s", counts)
+
+		single := NewHedger(r, WeightedItem[MarbleColor, int]{Item: Red})
+		_, ok := single.NextExcluding(Red)
+		assert.False(t, ok)
+	})
+}
+
+func TestHedge(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	h := NewHedger(r,
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+	)
+	failure := errors.New("unavailable")
+	t.Run("primary succeeds before delay", func(t *testing.T) {
+		calls := make(chan MarbleColor, 2)
+		result, err := Hedge(context.Background(), h, time.Hour, func(ctx context.Context, color MarbleColor) (MarbleColor, error) {
+			calls <- color
+			return color, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, result, <-calls)
+		assert.Empty(t, calls)
+	})
+	t.Run("secondary wins after delay", func(t *testing.T) {
+		var attempts atomic.Int32
+		primary := make(chan MarbleColor, 1)
+		result, err := Hedge(context.Background(), h, time.Millisecond, func(ctx context.Context, color MarbleColor) (MarbleColor, error) {
+			if attempts.Add(1) == 1 {
+				primary <- color
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return color, nil
+		})
+		assert.NoError(t, err)
+		assert.NotEqual(t, <-primary, result)
+	})
+	t.Run("primary fails before delay", func(t *testing.T) {
+		var attempts atomic.Int32
+		result, err := Hedge(context.Background(), h, time.Hour, func(ctx context.Context, color MarbleColor) (MarbleColor, error) {
+			if attempts.Add(1) == 1 {
+				return "", failure
+			}
+			return color, nil
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result)
+	})
+	t.Run("every call fails", func(t *testing.T) {
+		_, err := Hedge(context.Background(), h, time.Millisecond, func(context.Context, MarbleColor) (int, error) {
+			return 0, failure
+		})
+		assert.ErrorIs(t, err, failure)
+	})
+}
@@ -0,0 +1,101 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// HistogramBucket is a bucket of a histogram: the number of observations
+// within [Lower, Upper).
+type HistogramBucket[TBound Number] struct {
+	Lower TBound
+	Upper TBound
+	Count uint64
+}
+
+func (bucket HistogramBucket[TBound]) String() string {
+	return fmt.Sprintf(
+		"{count: %d, range: [%v, %v)}",
+		bucket.Count,
+		bucket.Lower,
+		bucket.Upper,
+	)
+}
+
+// Histogram draws values from the empirical distribution of a histogram,
+// such as observed latencies: it selects a bucket in proportion to its
+// count, and then a value uniformly within its bounds. It implements
+// WeightedRandom[float64], where Next is NextFloat64.
+type Histogram[TBound Number] struct {
+	random  RandIntN
+	buckets RandomInjectable[histogramRange]
+}
+
+type histogramRange struct {
+	lower float64
+	width float64
+}
+
+// NewFromHistogram constructs a new Histogram from its buckets. Buckets
+// with a count of zero are never selected; unlike the weights of the
+// constructors, their count is not assumed to be 1. A bucket whose bounds
+// are equal always yields its lower bound. Buckets are selected with the
+// integer engine, so that rare buckets in the tail of the histogram are
+// selected exactly in proportion to their counts.
+//
+// The function panics if no bucket has a positive count, or if the bounds
+// of a bucket are not finite or its upper bound is below its lower bound.
+//
+// Type Parameters:
+//   - TBound: The type of the bounds of the buckets.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - buckets: A variadic list of HistogramBucket values.
+//
+// Example usage:
+//
+//	latencies := NewFromHistogram(randSource,
+//		HistogramBucket[float64]{Lower: 0, Upper: 0.05, Count: 9_000},
+//		HistogramBucket[float64]{Lower: 0.05, Upper: 0.25, Count: 950},
+//		HistogramBucket[float64]{Lower: 0.25, Upper: 2.5, Count: 50},
+//	)
+//	time.Sleep(time.Duration(latencies.NextFloat64() * float64(time.Second)))
+func NewFromHistogram[TBound Number](random RandIntN, buckets ...HistogramBucket[TBound]) *Histogram[TBound] {
+	items := make([]WeightedItem[histogramRange, uint64], 0, len(buckets))
+	for _, bucket := range buckets {
+		lower, upper := float64(bucket.Lower), float64(bucket.Upper)
+		if math.IsNaN(lower) || math.IsInf(lower, 0) || math.IsNaN(upper) || math.IsInf(upper, 0) || upper < lower {
+			panic(fmt.Sprintf("bucket must have finite bounds in order, but was %s", bucket.String()))
+		} else if bucket.Count == 0 {
+			continue
+		}
+		items = append(items, WeightedItem[histogramRange, uint64]{
+			Item: histogramRange{
+				lower: lower,
+				width: upper - lower,
+			},
+			Weight: bucket.Count,
+		})
+	}
+	if len(items) == 0 {
+		panic("at least one bucket must have a positive count")
+	}
+	return &Histogram[TBound]{
+		random:  random,
+		buckets: newIntegerAliasMethod(buildContext{}, random, items),
+	}
+}
+
+// NextFloat64 selects a bucket in proportion to its count, and returns a
+// value drawn uniformly within its bounds.
+func (histogram *Histogram[TBound]) NextFloat64() float64 {
+	bucket := histogram.buckets.Next()
+	offset := float64(histogram.random.Int63n(float64Resolution)) / float64Resolution
+	return bucket.lower + offset*bucket.width
+}
+
+// Next is NextFloat64, so that a Histogram is a WeightedRandom[float64].
+func (histogram *Histogram[TBound]) Next() float64 {
+	return histogram.NextFloat64()
+}
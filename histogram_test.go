@@ -0,0 +1,76 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewFromHistogram[float64](nil)
+		})
+		assert.Panics(t, func() {
+			NewFromHistogram(nil, HistogramBucket[int]{Lower: 0, Upper: 10})
+		})
+		assert.Panics(t, func() {
+			NewFromHistogram(nil, HistogramBucket[int]{Lower: 10, Upper: 0, Count: 1})
+		})
+		assert.Panics(t, func() {
+			NewFromHistogram(nil, HistogramBucket[float64]{Lower: 0, Upper: math.Inf(1), Count: 1})
+		})
+	})
+	t.Run("empirical distribution", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		histogram := NewFromHistogram(r,
+			HistogramBucket[int]{Lower: 0, Upper: 10, Count: 3},
+			HistogramBucket[int]{Lower: 10, Upper: 20, Count: 0},
+			HistogramBucket[int]{Lower: 20, Upper: 100, Count: 1},
+			HistogramBucket[int]{Lower: 150, Upper: 150, Count: 0},
+		)
+		const iterations = 100_000
+		low, high := 0, 0
+		sum := 0.0
+		for range iterations {
+			value := histogram.NextFloat64()
+			assert.False(t, value >= 10 && value < 20, value)
+			assert.Less(t, value, 100.0)
+			if value < 5 {
+				low++
+			} else if value >= 20 {
+				high++
+			}
+			sum += value
+		}
+		assert.InDelta(t, 0.375, float64(low)/iterations, tolerance)
+		assert.InDelta(t, 0.25, float64(high)/iterations, tolerance)
+		// The mean is 0.75*5 + 0.25*60.
+		assert.InDelta(t, 18.75, sum/iterations, 0.5)
+	})
+	t.Run("point mass", func(t *testing.T) {
+		histogram := NewFromHistogram(rand.New(rand.NewSource(1)), HistogramBucket[float64]{Lower: 2.5, Upper: 2.5, Count: 1})
+		assert.Equal(t, 2.5, histogram.Next())
+	})
+	t.Run("tail bucket", func(t *testing.T) {
+		histogram := NewFromHistogram(rand.New(rand.NewSource(1)),
+			HistogramBucket[float64]{Lower: 0, Upper: 0.05, Count: 9_000},
+			HistogramBucket[float64]{Lower: 0.05, Upper: 0.25, Count: 950},
+			HistogramBucket[float64]{Lower: 0.25, Upper: 2.5, Count: 50},
+		)
+		const iterations = 1_000_000
+		tail := 0
+		for range iterations {
+			if histogram.NextFloat64() >= 0.25 {
+				tail++
+			}
+		}
+		// A coin toss of 1/100 resolution would select the tail bucket
+		// about 0.0067 of the time.
+		assert.InDelta(t, 0.005, float64(tail)/iterations, 0.0003)
+	})
+}
@@ -0,0 +1,143 @@
+package weightedrand
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BuildInfo describes the construction of an alias table, as reported to
+// the hook of WithOnBuild.
+type BuildInfo struct {
+	// Items is the number of items the table was built from.
+	Items int
+	// TotalWeight is the sum of the weights of the items, after weights that
+	// were not provided are assumed to be 1.
+	TotalWeight decimal.Decimal
+	// Engine is the arithmetic the table was built with, either "decimal",
+	// "integer", or "exact" for WithExactThresholds.
+	Engine string
+	// Duration is the time it took to build the table.
+	Duration time.Duration
+}
+
+// WithOnSelect registers a hook called with every selected item and the
+// probability it had to be selected, such as to log or count selections
+// without wrapping the WeightedRandom instance. The hook is for items of the
+// same type as the instance it is used with, and is called synchronously by
+// Next, so it should return quickly.
+func WithOnSelect[TItem any](hook func(item TItem, probability decimal.Decimal)) ItemOption[TItem] {
+	return func(o *options) {
+		o.onSelect = hook
+	}
+}
+
+// WithOnBuild registers a hook called every time an alias table is built,
+// which for NewDynamic includes every rebuild following a change.
+func WithOnBuild(hook func(BuildInfo)) Option {
+	return func(o *options) {
+		o.onBuild = hook
+	}
+}
+
+// onSelectHook returns the hook of WithOnSelect, which ItemOption ensures
+// is for items of type TItem, and returns nil if there is none.
+func onSelectHook[TItem any](o options) func(TItem, decimal.Decimal) {
+	if o.onSelect == nil {
+		return nil
+	}
+	return o.onSelect.(func(TItem, decimal.Decimal))
+}
+
+// hookedAliasMethod selects the index of an item from an alias table, so
+// that the probability of the selected item can be reported to the hook.
+type hookedAliasMethod[TItem any] struct {
+	random        RandIntN
+	items         []TItem
+	probabilities []decimal.Decimal
+	indices       RandomInjectable[int]
+	onSelect      func(TItem, decimal.Decimal)
+}
+
+func newHookedAliasMethod[TItem any, TWeight Weight](build buildContext, random RandIntN, items []WeightedItem[TItem, TWeight], integerEngine bool, onSelect func(TItem, decimal.Decimal)) hookedAliasMethod[TItem] {
+	aliasMethod, err := buildHookedAliasMethod(build, random, items, integerEngine, onSelect)
+	if err != nil {
+		panic(err)
+	}
+	return aliasMethod
+}
+
+// buildHookedAliasMethod is like newHookedAliasMethod, but returns an error
+// instead of panicking.
+func buildHookedAliasMethod[TItem any, TWeight Weight](build buildContext, random RandIntN, items []WeightedItem[TItem, TWeight], integerEngine bool, onSelect func(TItem, decimal.Decimal)) (hookedAliasMethod[TItem], error) {
+	if len(items) == 0 {
+		return hookedAliasMethod[TItem]{}, ErrNoItems
+	}
+	for index, item := range items {
+		if _, err := decimalWeight(index, item.Weight); err != nil {
+			return hookedAliasMethod[TItem]{}, err
+		}
+	}
+	aliasMethod := hookedAliasMethod[TItem]{
+		random:        random,
+		items:         make([]TItem, 0, len(items)),
+		probabilities: make([]decimal.Decimal, 0, len(items)),
+		onSelect:      onSelect,
+	}
+	indices := make([]WeightedItem[int, TWeight], 0, len(items))
+	for index, item := range normalizedItems(items) {
+		aliasMethod.items = append(aliasMethod.items, item.Item)
+		aliasMethod.probabilities = append(aliasMethod.probabilities, item.Weight)
+		indices = append(indices, WeightedItem[int, TWeight]{
+			Item:   index,
+			Weight: items[index].Weight,
+		})
+	}
+	var err error
+	if integerEngine {
+		aliasMethod.indices, err = buildIntegerAliasMethod(build, random, indices)
+	} else {
+		aliasMethod.indices, err = buildVoseAliasMethodContext(build, random, indices)
+	}
+	if err != nil {
+		return hookedAliasMethod[TItem]{}, err
+	}
+	return aliasMethod, nil
+}
+
+func (aliasMethod hookedAliasMethod[TItem]) Next() TItem {
+	return aliasMethod.NextUsing(aliasMethod.random)
+}
+
+// NextUsing selects an item by weight, using the random number generator
+// instead of the one provided at construction.
+func (aliasMethod hookedAliasMethod[TItem]) NextUsing(random RandIntN) TItem {
+	index := aliasMethod.indices.NextUsing(random)
+	item := aliasMethod.items[index]
+	aliasMethod.onSelect(item, aliasMethod.probabilities[index])
+	return item
+}
+
+func (aliasMethod hookedAliasMethod[TItem]) weights() []weightedItem[TItem] {
+	weights := make([]weightedItem[TItem], 0, len(aliasMethod.items))
+	for index, item := range aliasMethod.items {
+		weights = append(weights, weightedItem[TItem]{
+			Item:   item,
+			Weight: aliasMethod.probabilities[index],
+		})
+	}
+	return weights
+}
+
+func (aliasMethod hookedAliasMethod[TItem]) randomSource() RandIntN {
+	return aliasMethod.random
+}
+
+// totalWeight sums the effective weights of the items.
+func totalWeight[TItem any](items []weightedItem[TItem]) decimal.Decimal {
+	total := decimal.Zero
+	for _, item := range items {
+		total = total.Add(item.Weight)
+	}
+	return total
+}
@@ -0,0 +1,76 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 0},
+		{Item: Blue, Weight: 2},
+	}
+	for _, engine := range []string{"decimal", "integer"} {
+		t.Run(engine, func(t *testing.T) {
+			opts := []ItemOption[MarbleColor]{}
+			if engine == "integer" {
+				opts = append(opts, WithIntegerEngine())
+			}
+			var builds []BuildInfo
+			counts := make(MarbleColorCounts)
+			probabilities := make(map[MarbleColor]string)
+			wr := NewAliasVoseMethodWithOptions(r, items, append(opts,
+				WithOnBuild(func(info BuildInfo) {
+					builds = append(builds, info)
+				}),
+				WithOnSelect(func(item MarbleColor, probability decimal.Decimal) {
+					counts[item] += 1
+					probabilities[item] = probability.String()
+				}),
+			)...)
+			if assert.Len(t, builds, 1) {
+				assert.Equal(t, 3, builds[0].Items)
+				assert.Equal(t, "4", builds[0].TotalWeight.String())
+				assert.Equal(t, engine, builds[0].Engine)
+			}
+			selected := make(MarbleColorCounts)
+			for range 100_000 {
+				selected[wr.Next()] += 1
+			}
+			assert.Equal(t, selected, counts)
+			assert.Equal(t, map[MarbleColor]string{Red: "0.25", Green: "0.25", Blue: "0.5"}, probabilities)
+			assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+		})
+	}
+	t.Run("dynamic", func(t *testing.T) {
+		var builds []BuildInfo
+		var lastItem MarbleColor
+		var lastProbability decimal.Decimal
+		d := NewDynamic(r, items,
+			WithOnBuild(func(info BuildInfo) {
+				builds = append(builds, info)
+			}),
+			WithOnSelect(func(item MarbleColor, probability decimal.Decimal) {
+				lastItem, lastProbability = item, probability
+			}),
+		)
+		assert.Empty(t, builds)
+		d.Next()
+		d.Next()
+		assert.Len(t, builds, 1)
+		d.Remove(Red)
+		d.Remove(Green)
+		item := d.Next()
+		assert.Len(t, builds, 2)
+		assert.Equal(t, Blue, item)
+		assert.Equal(t, Blue, lastItem)
+		assert.True(t, lastProbability.Equal(One), lastProbability)
+	})
+}
@@ -0,0 +1,265 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// integerAliasMethodRandom is the alias table built by WithIntegerEngine.
+// Every bucket has a capacity equal to the total weight, and the threshold
+// of a bucket is the share of that capacity belonging to its primary item.
+type integerAliasMethodRandom[TItem any] struct {
+	random     RandIntN
+	items      []TItem
+	total      int64
+	thresholds []int64
+	aliases    []int
+}
+
+// newIntegerAliasMethod builds the alias table of the integer engine. The
+// table is held in int64 arithmetic when the total weight allows it, and in
+// uint128 arithmetic otherwise, so that large weights are never truncated.
+// Its progress is reported to the build context, whose context is ignored.
+// It panics with the error of buildIntegerAliasMethod.
+func newIntegerAliasMethod[TItem any, TWeight Weight](build buildContext, random RandIntN, items []WeightedItem[TItem, TWeight]) RandomInjectable[TItem] {
+	aliasMethod, err := buildIntegerAliasMethod(build, random, items)
+	if err != nil {
+		panic(err)
+	}
+	return aliasMethod
+}
+
+// buildIntegerAliasMethod is like newIntegerAliasMethod, but returns
+// ErrNoItems, ErrNegativeWeight, ErrNonFiniteWeight or a wrapped
+// ErrOverflow instead of panicking.
+func buildIntegerAliasMethod[TItem any, TWeight Weight](build buildContext, random RandIntN, items []WeightedItem[TItem, TWeight]) (RandomInjectable[TItem], error) {
+	if len(items) == 0 {
+		return nil, ErrNoItems
+	}
+	count := uint64(len(items))
+	values := make([]TItem, len(items))
+	weights := make([]uint64, len(items))
+	total := uint128{}
+	narrow := true
+	for i, currentItem := range items {
+		values[i] = currentItem.Item
+		weight, err := integerWeight(i, currentItem.Weight)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = weight
+		var overflow bool
+		total, overflow = total.add(uint128{lo: weights[i]})
+		if overflow {
+			return nil, fmt.Errorf("total weight of the first %d items exceeds 128 bits: %w", i+1, ErrOverflow)
+		}
+		if scaled := mul64(weights[i], count); scaled.hi != 0 || scaled.lo > math.MaxInt64 {
+			narrow = false
+		}
+	}
+	if narrow && total.hi == 0 && total.lo <= math.MaxInt64 {
+		return newNarrowIntegerAliasMethod(build, random, values, weights, int64(total.lo)), nil
+	}
+	return newWideIntegerAliasMethod(build, random, values, weights, total)
+}
+
+func newNarrowIntegerAliasMethod[TItem any](build buildContext, random RandIntN, items []TItem, weights []uint64, total int64) integerAliasMethodRandom[TItem] {
+	count := uint64(len(items))
+	aliasMethod := integerAliasMethodRandom[TItem]{
+		random:     random,
+		items:      items,
+		total:      total,
+		thresholds: make([]int64, len(items)),
+		aliases:    make([]int, len(items)),
+	}
+	// The total weight becomes the capacity of every bucket, and every
+	// weight is scaled by the number of items so that the weights average
+	// to the capacity. The caller ensures neither exceeds math.MaxInt64.
+	scaled := make([]uint64, len(items))
+	for i, weight := range weights {
+		scaled[i] = weight * count
+	}
+
+	// Create two worklists, Small and Large.
+	small := make([]int, 0, len(items))
+	large := make([]int, 0, len(items))
+	capacity := uint64(total)
+	for i, weight := range scaled {
+		if weight < capacity {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for done := 0; len(small) > 0 && len(large) > 0; done++ {
+		if done%cancelInterval == 0 {
+			build.report(done, len(items))
+		}
+		lesser, greater := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		aliasMethod.thresholds[lesser] = int64(scaled[lesser])
+		aliasMethod.aliases[lesser] = greater
+		// Neither value exceeds math.MaxInt64, so the sum cannot overflow.
+		scaled[greater] = scaled[greater] + scaled[lesser] - capacity
+		if scaled[greater] < capacity {
+			small = append(small, greater)
+		} else {
+			large = append(large, greater)
+		}
+	}
+	// Integer arithmetic is exact, so whatever remains fills its bucket.
+	for _, i := range append(small, large...) {
+		aliasMethod.thresholds[i] = aliasMethod.total
+		aliasMethod.aliases[i] = i
+	}
+	build.report(len(items), len(items))
+	return aliasMethod
+}
+
+func (aliasMethod integerAliasMethodRandom[TItem]) Next() TItem {
+	return aliasMethod.NextUsing(aliasMethod.random)
+}
+
+// NextUsing selects an item by weight, using the random number generator
+// instead of the one provided at construction.
+func (aliasMethod integerAliasMethodRandom[TItem]) NextUsing(random RandIntN) TItem {
+	// First, perform a fair dice roll.
+	fairDiceRoll := random.Intn(len(aliasMethod.items))
+	// Second, perform an unfair coin toss against the bucket's capacity.
+	if random.Int63n(aliasMethod.total) < aliasMethod.thresholds[fairDiceRoll] {
+		return aliasMethod.items[fairDiceRoll]
+	}
+	return aliasMethod.items[aliasMethod.aliases[fairDiceRoll]]
+}
+
+// wideIntegerAliasMethodRandom is the alias table of the integer engine for
+// total weights beyond the range of int64, held in uint128 arithmetic. Coin
+// tosses draw as many random bits as the total weight has, so selections
+// remain exact at the cost of more random numbers.
+type wideIntegerAliasMethodRandom[TItem any] struct {
+	random     RandIntN
+	items      []TItem
+	total      uint128
+	thresholds []uint128
+	aliases    []int
+}
+
+func newWideIntegerAliasMethod[TItem any](build buildContext, random RandIntN, items []TItem, weights []uint64, total uint128) (wideIntegerAliasMethodRandom[TItem], error) {
+	count := uint64(len(items))
+	aliasMethod := wideIntegerAliasMethodRandom[TItem]{
+		random:     random,
+		items:      items,
+		total:      total,
+		thresholds: make([]uint128, len(items)),
+		aliases:    make([]int, len(items)),
+	}
+	scaled := make([]uint128, len(items))
+	small := make([]int, 0, len(items))
+	large := make([]int, 0, len(items))
+	for i, weight := range weights {
+		scaled[i] = mul64(weight, count)
+		if scaled[i].less(total) {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for done := 0; len(small) > 0 && len(large) > 0; done++ {
+		if done%cancelInterval == 0 {
+			build.report(done, len(items))
+		}
+		lesser, greater := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		aliasMethod.thresholds[lesser] = scaled[lesser]
+		aliasMethod.aliases[lesser] = greater
+		sum, overflow := scaled[greater].add(scaled[lesser])
+		if overflow {
+			return wideIntegerAliasMethodRandom[TItem]{}, fmt.Errorf("scaled weight of item %d exceeds 128 bits: %w", greater, ErrOverflow)
+		}
+		scaled[greater] = sum.sub(total)
+		if scaled[greater].less(total) {
+			small = append(small, greater)
+		} else {
+			large = append(large, greater)
+		}
+	}
+	for _, i := range append(small, large...) {
+		aliasMethod.thresholds[i] = total
+		aliasMethod.aliases[i] = i
+	}
+	build.report(len(items), len(items))
+	return aliasMethod, nil
+}
+
+func (aliasMethod wideIntegerAliasMethodRandom[TItem]) Next() TItem {
+	return aliasMethod.NextUsing(aliasMethod.random)
+}
+
+// NextUsing selects an item by weight, using the random number generator
+// instead of the one provided at construction.
+func (aliasMethod wideIntegerAliasMethodRandom[TItem]) NextUsing(random RandIntN) TItem {
+	fairDiceRoll := random.Intn(len(aliasMethod.items))
+	if randomUint128(random, aliasMethod.total).less(aliasMethod.thresholds[fairDiceRoll]) {
+		return aliasMethod.items[fairDiceRoll]
+	}
+	return aliasMethod.items[aliasMethod.aliases[fairDiceRoll]]
+}
+
+// integerWeight converts the weight of the item at the index for the
+// integer engine, applying the same defaulting rules as effectiveWeight.
+// Integer weights are converted directly, and others through decimalWeight,
+// which must result in a whole number that fits in a uint64.
+func integerWeight[TWeight Weight](index int, weight TWeight) (uint64, error) {
+	var result uint64
+	switch value := any(weight).(type) {
+	case int:
+		return signedWeight(index, int64(value))
+	case int8:
+		return signedWeight(index, int64(value))
+	case int16:
+		return signedWeight(index, int64(value))
+	case int32:
+		return signedWeight(index, int64(value))
+	case int64:
+		return signedWeight(index, value)
+	case uint:
+		result = uint64(value)
+	case uint8:
+		result = uint64(value)
+	case uint16:
+		result = uint64(value)
+	case uint32:
+		result = uint64(value)
+	case uint64:
+		result = value
+	default:
+		converted, err := decimalWeight(index, weight)
+		if err != nil {
+			return 0, err
+		} else if !converted.IsInteger() {
+			return 0, fmt.Errorf("weight of item %d is %s, but the integer engine requires whole weights", index, converted.String())
+		} else if converted.BigInt().BitLen() > 64 {
+			return 0, fmt.Errorf("weight of item %d is %s, which exceeds 64 bits: %w", index, converted.String(), ErrOverflow)
+		}
+		return converted.BigInt().Uint64(), nil
+	}
+	// If no weight is provided, it is assumed to be 1
+	if result == 0 {
+		return 1, nil
+	}
+	return result, nil
+}
+
+func signedWeight(index int, value int64) (uint64, error) {
+	if value < 0 {
+		return 0, &ErrNegativeWeight{
+			Index:  index,
+			Weight: decimal.NewFromInt(value),
+		}
+	} else if value == 0 {
+		return 1, nil
+	}
+	return uint64(value), nil
+}
@@ -0,0 +1,84 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkIntegerEngine(b *testing.B) {
+	items := make([]WeightedItem[int, int], 1_000)
+	for i := range items {
+		items[i] = WeightedItem[int, int]{Item: i, Weight: i + 1}
+	}
+	b.Run("decimal", func(b *testing.B) {
+		for b.Loop() {
+			_ = NewAliasVoseMethodWithOptions(nil, items)
+		}
+	})
+	b.Run("integer", func(b *testing.B) {
+		for b.Loop() {
+			_ = NewAliasVoseMethodWithOptions(nil, items, WithIntegerEngine())
+		}
+	})
+}
+
+func TestIntegerEngine(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		t.Run("no items", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewAliasVoseMethodWithOptions[int, int](nil, nil, WithIntegerEngine())
+			})
+		})
+		t.Run("negative weight", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewAliasVoseMethodWithOptions(nil, []WeightedItem[int, int]{{Weight: -1}}, WithIntegerEngine())
+			})
+		})
+		t.Run("fractional weight", func(t *testing.T) {
+			assert.Panics(t, func() {
+				NewAliasVoseMethodWithOptions(nil, []WeightedItem[int, decimal.Decimal]{
+					{Weight: FixtureDecimal(t, "0.5")},
+				}, WithIntegerEngine())
+			})
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, decimal.Decimal]{
+			{Item: Red},
+			{Item: Green, Weight: FixtureDecimal(t, "2")},
+			{Item: Blue, Weight: FixtureDecimal(t, "7")},
+		}, WithIntegerEngine())
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.1, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.2, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.7, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+	t.Run("total weight beyond int64", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, uint64]{
+			{Item: Red, Weight: math.MaxUint64 / 4},
+			{Item: Green, Weight: math.MaxUint64},
+			{Item: Blue, Weight: math.MaxUint64 / 4 * 3},
+		}, WithIntegerEngine())
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.125, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.5, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.375, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+		assert.NoError(t, SelfTest(wr, iterations))
+	})
+}
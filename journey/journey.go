@@ -0,0 +1,145 @@
+// Package journey simulates synthetic user journeys: a walk through states
+// connected by weighted transitions, where the time spent in each state is
+// drawn from a weighted dwell-time distribution.
+package journey
+
+import (
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand"
+)
+
+// State describes a single step of a journey. A state without transitions is
+// terminal, and ends the journey when reached.
+type State[TWeight weightedrand.Weight] struct {
+	// Name uniquely identifies the state, and is referenced by transitions.
+	Name string
+	// Dwell is the distribution of how long is spent in the state before
+	// transitioning. If empty, no time is spent in the state.
+	Dwell []weightedrand.WeightedItem[time.Duration, TWeight]
+	// Transitions are the states that may follow this one, by weight.
+	Transitions []weightedrand.WeightedItem[string, TWeight]
+}
+
+// Event is emitted for every state visited during a journey.
+type Event struct {
+	// Step is the index of the event within the journey, starting at zero.
+	Step int
+	// State is the name of the state that was entered.
+	State string
+	// Offset is the time since the start of the journey the state was entered.
+	Offset time.Duration
+	// Dwell is the time spent in the state.
+	Dwell time.Duration
+}
+
+func (event Event) String() string {
+	return fmt.Sprintf(
+		"{step: %d, state: %s, offset: %s, dwell: %s}",
+		event.Step, event.State, event.Offset, event.Dwell,
+	)
+}
+
+type compiledState struct {
+	dwell       weightedrand.WeightedRandom[time.Duration]
+	transitions weightedrand.WeightedRandom[string]
+}
+
+// Journey is a state machine with weighted transitions. It is immutable once
+// constructed, and may be walked any number of times.
+type Journey struct {
+	start  string
+	states map[string]compiledState
+}
+
+// New constructs a Journey that begins in the start state.
+//
+// The function panics if no states are provided, if state names are
+// duplicated, if the start state or a transition target does not exist, or
+// if weights are negative.
+//
+// Example usage:
+//
+//	j := journey.New(randSource, "landing",
+//		journey.State[int]{
+//			Name:        "landing",
+//			Dwell:       []weightedrand.WeightedItem[time.Duration, int]{{Item: time.Second, Weight: 1}},
+//			Transitions: []weightedrand.WeightedItem[string, int]{{Item: "search", Weight: 3}, {Item: "exit", Weight: 1}},
+//		},
+//		journey.State[int]{Name: "search", Transitions: []weightedrand.WeightedItem[string, int]{{Item: "exit", Weight: 1}}},
+//		journey.State[int]{Name: "exit"},
+//	)
+//	events := j.Walk(100)
+func New[TWeight weightedrand.Weight](random weightedrand.RandIntN, start string, states ...State[TWeight]) *Journey {
+	if len(states) == 0 {
+		panic("at least one state must be provided")
+	}
+	journey := &Journey{
+		start:  start,
+		states: make(map[string]compiledState, len(states)),
+	}
+	for _, state := range states {
+		if _, ok := journey.states[state.Name]; ok {
+			panic(fmt.Sprintf("state %q was provided more than once", state.Name))
+		}
+		var compiled compiledState
+		if len(state.Dwell) > 0 {
+			compiled.dwell = weightedrand.NewAliasVoseMethod(random, state.Dwell...)
+		}
+		if len(state.Transitions) > 0 {
+			compiled.transitions = weightedrand.NewAliasVoseMethod(random, state.Transitions...)
+		}
+		journey.states[state.Name] = compiled
+	}
+	if _, ok := journey.states[start]; !ok {
+		panic(fmt.Sprintf("start state %q does not exist", start))
+	}
+	for _, state := range states {
+		for _, transition := range state.Transitions {
+			if _, ok := journey.states[transition.Item]; !ok {
+				panic(fmt.Sprintf("state %q transitions to %q, which does not exist", state.Name, transition.Item))
+			}
+		}
+	}
+	return journey
+}
+
+// Walk performs a single journey from the start state, and returns the
+// events of every state visited. The journey ends when a terminal state is
+// reached, or after maxSteps events have been emitted. If maxSteps is not
+// positive, no events are emitted.
+func (journey *Journey) Walk(maxSteps int) []Event {
+	events := make([]Event, 0, max(0, min(maxSteps, 64)))
+	for event := range journey.Events(maxSteps) {
+		events = append(events, event)
+	}
+	return events
+}
+
+// Events returns an iterator over the events of a single journey, which
+// allows events to be emitted as they are generated. The journey ends when a
+// terminal state is reached, or after maxSteps events have been emitted.
+func (journey *Journey) Events(maxSteps int) iter.Seq[Event] {
+	return func(yield func(Event) bool) {
+		current := journey.start
+		offset := time.Duration(0)
+		for step := range maxSteps {
+			state := journey.states[current]
+			event := Event{
+				Step:   step,
+				State:  current,
+				Offset: offset,
+			}
+			if state.dwell != nil {
+				event.Dwell = state.dwell.Next()
+			}
+			if !yield(event) || state.transitions == nil {
+				return
+			}
+			offset += event.Dwell
+			current = state.transitions.Next()
+		}
+	}
+}
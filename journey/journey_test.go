@@ -0,0 +1,84 @@
+package journey_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"github.com/nikole-dunixi/weightedrand/journey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStates() []journey.State[int] {
+	return []journey.State[int]{
+		{
+			Name:  "landing",
+			Dwell: []weightedrand.WeightedItem[time.Duration, int]{{Item: time.Second, Weight: 1}},
+			Transitions: []weightedrand.WeightedItem[string, int]{
+				{Item: "search", Weight: 1},
+				{Item: "exit", Weight: 1},
+			},
+		},
+		{
+			Name: "search",
+			Dwell: []weightedrand.WeightedItem[time.Duration, int]{
+				{Item: 2 * time.Second, Weight: 1},
+				{Item: 4 * time.Second, Weight: 1},
+			},
+			Transitions: []weightedrand.WeightedItem[string, int]{
+				{Item: "search", Weight: 1},
+				{Item: "exit", Weight: 1},
+			},
+		},
+		{
+			Name: "exit",
+		},
+	}
+}
+
+func TestJourney(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		t.Run("no states", func(t *testing.T) {
+			assert.Panics(t, func() {
+				journey.New[int](nil, "landing")
+			})
+		})
+		t.Run("unknown start", func(t *testing.T) {
+			assert.Panics(t, func() {
+				journey.New(nil, "checkout", testStates()...)
+			})
+		})
+		t.Run("unknown transition", func(t *testing.T) {
+			states := testStates()
+			states[2].Transitions = []weightedrand.WeightedItem[string, int]{{Item: "checkout"}}
+			assert.Panics(t, func() {
+				journey.New(nil, "landing", states...)
+			})
+		})
+	})
+	t.Run("walk", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		j := journey.New(r, "landing", testStates()...)
+		for range 100 {
+			events := j.Walk(1_000)
+			require.NotEmpty(t, events)
+			assert.Equal(t, "landing", events[0].State)
+			assert.Equal(t, time.Second, events[0].Dwell)
+			assert.Equal(t, "exit", events[len(events)-1].State)
+			for i := 1; i < len(events); i++ {
+				assert.Equal(t, i, events[i].Step)
+				assert.Equal(t, events[i-1].Offset+events[i-1].Dwell, events[i].Offset)
+			}
+		}
+	})
+	t.Run("max steps", func(t *testing.T) {
+		states := testStates()
+		states[0].Transitions = []weightedrand.WeightedItem[string, int]{{Item: "landing"}}
+		j := journey.New(rand.New(rand.NewSource(1)), "landing", states...)
+		assert.Len(t, j.Walk(10), 10)
+		assert.Empty(t, j.Walk(0))
+		assert.Empty(t, j.Walk(-1))
+	})
+}
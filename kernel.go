@@ -0,0 +1,87 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kernel draws the noise added to a point by a Smoothed chooser, which is
+// centered on zero.
+type Kernel func(random RandIntN) float64
+
+// GaussianKernel draws normally distributed noise with the standard
+// deviation as its bandwidth.
+//
+// Panics:
+//   - If the bandwidth is negative or not finite.
+func GaussianKernel(bandwidth float64) Kernel {
+	validateBandwidth(bandwidth)
+	return func(random RandIntN) float64 {
+		// The Box-Muller transform, with u1 kept away from zero.
+		u1 := (float64(random.Int63n(float64Resolution)) + 0.5) / float64Resolution
+		u2 := float64(random.Int63n(float64Resolution)) / float64Resolution
+		return bandwidth * math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+	}
+}
+
+// UniformKernel draws noise uniformly within [-bandwidth, bandwidth).
+//
+// Panics:
+//   - If the bandwidth is negative or not finite.
+func UniformKernel(bandwidth float64) Kernel {
+	validateBandwidth(bandwidth)
+	return func(random RandIntN) float64 {
+		u := float64(random.Int63n(float64Resolution)) / float64Resolution
+		return bandwidth * (2*u - 1)
+	}
+}
+
+func validateBandwidth(bandwidth float64) {
+	if !(bandwidth >= 0) || math.IsInf(bandwidth, 1) {
+		panic(fmt.Sprintf("bandwidth must be a non-negative finite number, but was %g", bandwidth))
+	}
+}
+
+// smoothedRandom is the WeightedRandom of NewSmoothed.
+type smoothedRandom[TItem Number] struct {
+	random RandIntN
+	points WeightedRandom[TItem]
+	kernel Kernel
+}
+
+// NewSmoothed constructs a new WeightedRandom that samples values near
+// weighted points, in the style of kernel density estimation: it selects a
+// point from the chooser by weight, and then adds noise drawn from the
+// kernel, so that its output is smoothed rather than purely discrete.
+//
+// The function panics if the kernel is nil.
+//
+// Type Parameters:
+//   - TItem: The type of the points.
+//
+// Parameters:
+//   - random: A RandIntN implementation used to draw the noise.
+//   - points: The WeightedRandom selecting the points by weight.
+//   - kernel: The Kernel drawing the noise, such as GaussianKernel.
+//
+// Example usage:
+//
+//	readings := NewSmoothed(randSource, NewAliasVoseMethod(randSource,
+//		WeightedItem[float64, int]{Item: 21.5, Weight: 8},
+//		WeightedItem[float64, int]{Item: 35, Weight: 1},
+//	), GaussianKernel(0.4))
+//	temperature := readings.Next()
+func NewSmoothed[TItem Number](random RandIntN, points WeightedRandom[TItem], kernel Kernel) WeightedRandom[float64] {
+	if kernel == nil {
+		panic("a kernel must be provided")
+	}
+	return smoothedRandom[TItem]{
+		random: random,
+		points: points,
+		kernel: kernel,
+	}
+}
+
+func (smoothed smoothedRandom[TItem]) Next() float64 {
+	return float64(smoothed.points.Next()) + smoothed.kernel(smoothed.random)
+}
@@ -0,0 +1,54 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmoothed(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			GaussianKernel(-1)
+		})
+		assert.Panics(t, func() {
+			UniformKernel(math.NaN())
+		})
+		assert.Panics(t, func() {
+			NewSmoothed[int](nil, nil, nil)
+		})
+	})
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	points := NewAliasVoseMethod(r,
+		WeightedItem[int, int]{Item: 0, Weight: 3},
+		WeightedItem[int, int]{Item: 100, Weight: 1},
+	)
+	const iterations = 100_000
+	t.Run("gaussian", func(t *testing.T) {
+		smoothed := NewSmoothed(r, points, GaussianKernel(2))
+		near, sum, squares := 0, 0.0, 0.0
+		for range iterations {
+			value := smoothed.Next()
+			if value < 50 {
+				near++
+				sum += value
+				squares += value * value
+			}
+		}
+		assert.InDelta(t, 0.75, float64(near)/iterations, tolerance)
+		mean := sum / float64(near)
+		assert.InDelta(t, 0, mean, 0.1)
+		assert.InDelta(t, 2, math.Sqrt(squares/float64(near)-mean*mean), 0.1)
+	})
+	t.Run("uniform", func(t *testing.T) {
+		smoothed := NewSmoothed(r, points, UniformKernel(5))
+		for range iterations {
+			value := smoothed.Next()
+			assert.True(t, (value >= -5 && value < 5) || (value >= 95 && value < 105), value)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package weightedrand
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+)
+
+// Labeled shares a single alias table between independent streams of
+// selections, one for each label, such as one for each endpoint of a
+// service. The random number generator of every stream is derived from a
+// master seed and its label, so each stream is reproducible on its own,
+// regardless of how selections of the other streams interleave with it.
+//
+// Labeled is safe for concurrent use.
+type Labeled[TItem any] struct {
+	seed    string
+	table   voseAliasMethodRandom[TItem]
+	mutex   sync.Mutex
+	streams map[string]*labeledStream[TItem]
+}
+
+type labeledStream[TItem any] struct {
+	mutex  sync.Mutex
+	random *rand.Rand
+	table  voseAliasMethodRandom[TItem]
+}
+
+// NewLabeled constructs a new Labeled instance, whose streams are derived
+// from the master seed.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - seed:  The master seed from which every stream is derived.
+//   - items: A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	l := NewLabeled("replay-7", WeightedItem[string, int]{Item: "A", Weight: 2}, WeightedItem[string, int]{Item: "B", Weight: 3})
+//	item := l.ForLabel("/checkout").Next()
+func NewLabeled[TItem any, TWeight Weight](seed string, items ...WeightedItem[TItem, TWeight]) *Labeled[TItem] {
+	return &Labeled[TItem]{
+		seed:    seed,
+		table:   newVoseAliasMethod[TItem](nil, items),
+		streams: make(map[string]*labeledStream[TItem]),
+	}
+}
+
+// ForLabel returns the stream of the label. Every call with the same label
+// returns the same stream, which continues where it left off, and the
+// stream is safe for concurrent use.
+func (labeled *Labeled[TItem]) ForLabel(label string) WeightedRandom[TItem] {
+	labeled.mutex.Lock()
+	defer labeled.mutex.Unlock()
+	stream, ok := labeled.streams[label]
+	if !ok {
+		stream = &labeledStream[TItem]{
+			random: deriveRand(labeled.seed, label),
+			table:  labeled.table,
+		}
+		labeled.streams[label] = stream
+	}
+	return stream
+}
+
+func (stream *labeledStream[TItem]) Next() TItem {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+	return stream.table.NextUsing(stream.random)
+}
+
+// deriveRand derives a random number generator from the seed and the label.
+// Both are prefixed by their length before hashing, so that no other pair
+// of seed and label produces the same input.
+func deriveRand(seed, label string) *rand.Rand {
+	hash := sha256.New()
+	for _, part := range []string{seed, label} {
+		hash.Write(binary.BigEndian.AppendUint64(nil, uint64(len(part))))
+		hash.Write([]byte(part))
+	}
+	digest := hash.Sum(nil)
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(digest[:8]))))
+}
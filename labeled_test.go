@@ -0,0 +1,41 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabeled(t *testing.T) {
+	items := make([]WeightedItem[int, int], 100)
+	for i := range items {
+		items[i] = WeightedItem[int, int]{Item: i, Weight: i + 1}
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewLabeled[int, int]("seed")
+		})
+	})
+	sequence := func(wr WeightedRandom[int]) []int {
+		return NextN(wr, 50)
+	}
+	t.Run("reproducible per label", func(t *testing.T) {
+		a := NewLabeled("seed", items...)
+		b := NewLabeled("seed", items...)
+		// Interleaving selections of other labels does not affect a stream.
+		sequence(a.ForLabel("other"))
+		assert.Equal(t, sequence(a.ForLabel("checkout")), sequence(b.ForLabel("checkout")))
+		assert.Equal(t, sequence(a.ForLabel("checkout")), sequence(b.ForLabel("checkout")))
+	})
+	t.Run("independent between labels and seeds", func(t *testing.T) {
+		a := NewLabeled("seed", items...)
+		b := NewLabeled("other seed", items...)
+		assert.NotEqual(t, sequence(a.ForLabel("checkout")), sequence(a.ForLabel("search")))
+		assert.NotEqual(t, sequence(a.ForLabel("search")), sequence(b.ForLabel("search")))
+		// The label and seed are not simply concatenated.
+		c := NewLabeled("se", items...)
+		d := NewLabeled("s", items...)
+		assert.NotEqual(t, sequence(c.ForLabel("ed")), sequence(d.ForLabel("eed")))
+	})
+}
@@ -0,0 +1,190 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// latencyBuckets is the number of buckets of a latencyHistogram. Bucket i
+// counts latencies below 2^i microseconds, so the last bucket reaches well
+// beyond a day.
+const latencyBuckets = 38
+
+// latencyHistogram counts latencies in exponentially sized buckets.
+type latencyHistogram struct {
+	counts [latencyBuckets]uint64
+	total  uint64
+}
+
+func (histogram *latencyHistogram) observe(latency time.Duration) {
+	micros := uint64(max(latency.Microseconds(), 0))
+	bucket := min(bits.Len64(micros), latencyBuckets-1)
+	histogram.counts[bucket]++
+	histogram.total++
+}
+
+// quantile returns the upper bound of the bucket containing the quantile.
+func (histogram *latencyHistogram) quantile(q float64) time.Duration {
+	target := uint64(q * float64(histogram.total))
+	cumulative := uint64(0)
+	for bucket, count := range histogram.counts {
+		cumulative += count
+		if cumulative > target || cumulative == histogram.total {
+			return time.Duration(uint64(1)<<bucket) * time.Microsecond
+		}
+	}
+	return time.Duration(uint64(1)<<(latencyBuckets-1)) * time.Microsecond
+}
+
+// decay halves every count, so that older observations gradually lose their
+// influence.
+func (histogram *latencyHistogram) decay() {
+	histogram.total = 0
+	for bucket := range histogram.counts {
+		histogram.counts[bucket] /= 2
+		histogram.total += histogram.counts[bucket]
+	}
+}
+
+// LatencyWeighter is a WeightedRandom that weights its items inversely
+// proportional to a quantile of their observed latencies, so that faster
+// items are selected more often. Latencies are recorded with Observe, and
+// the weights are recomputed by Recompute, or periodically by Run, which
+// swaps in a new alias table without interrupting selections.
+//
+// Items without observations are weighted with the average weight of the
+// items that have them, and every item has the same weight until any
+// latency is observed.
+//
+// LatencyWeighter is safe for concurrent use, given that the random number
+// generator is.
+type LatencyWeighter[TItem comparable] struct {
+	swappable  *Swappable[TItem]
+	random     RandIntN
+	quantile   float64
+	items      []TItem
+	mutex      sync.Mutex
+	histograms map[TItem]*latencyHistogram
+}
+
+// NewLatencyWeighter constructs a new LatencyWeighter, weighting its items
+// by the quantile (such as 0.99) of their latencies.
+//
+// The function panics if no items are provided, or if the quantile is not
+// within [0, 1]. The items are copied, so a slice passed with items...
+// can be changed afterwards without affecting the weighter.
+//
+// Example usage:
+//
+//	lw := NewLatencyWeighter(randSource, 0.9, "a", "b", "c")
+//	go lw.Run(ctx, 5*time.Second)
+//	backend := lw.Next()
+//	start := time.Now()
+//	call(backend)
+//	lw.Observe(backend, time.Since(start))
+func NewLatencyWeighter[TItem comparable](random RandIntN, quantile float64, items ...TItem) *LatencyWeighter[TItem] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	} else if quantile < 0 || quantile > 1 {
+		panic(fmt.Sprintf("quantile must be between 0 and 1, but was %v", quantile))
+	}
+	weighter := &LatencyWeighter[TItem]{
+		random:     random,
+		quantile:   quantile,
+		items:      slices.Clone(items),
+		histograms: make(map[TItem]*latencyHistogram, len(items)),
+	}
+	for _, item := range items {
+		weighter.histograms[item] = &latencyHistogram{}
+	}
+	weighter.swappable = NewSwappable(weighter.build())
+	return weighter
+}
+
+// Next selects an item by the weights of the last recomputation.
+func (weighter *LatencyWeighter[TItem]) Next() TItem {
+	return weighter.swappable.Next()
+}
+
+// Observe records the latency of using the item. Latencies of unknown items
+// are ignored.
+func (weighter *LatencyWeighter[TItem]) Observe(item TItem, latency time.Duration) {
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	if histogram, ok := weighter.histograms[item]; ok {
+		histogram.observe(latency)
+	}
+}
+
+// Recompute rebuilds the weights from the observed latencies and swaps them
+// into service. Afterwards, the observations are decayed by half, so that
+// the weights follow changes in latency over time.
+func (weighter *LatencyWeighter[TItem]) Recompute() {
+	weighter.swappable.Swap(weighter.build())
+}
+
+// Weights returns the weight every item would currently be assigned by
+// Recompute.
+func (weighter *LatencyWeighter[TItem]) Weights() []WeightedItem[TItem, decimal.Decimal] {
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	return weighter.weights()
+}
+
+// Run recomputes the weights every interval until the context is done.
+func (weighter *LatencyWeighter[TItem]) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			weighter.Recompute()
+		}
+	}
+}
+
+func (weighter *LatencyWeighter[TItem]) build() WeightedRandom[TItem] {
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	items := weighter.weights()
+	for _, histogram := range weighter.histograms {
+		histogram.decay()
+	}
+	return NewAliasVoseMethod(weighter.random, items...)
+}
+
+func (weighter *LatencyWeighter[TItem]) weights() []WeightedItem[TItem, decimal.Decimal] {
+	items := make([]WeightedItem[TItem, decimal.Decimal], len(weighter.items))
+	observed := 0
+	sum := decimal.Zero
+	for i, item := range weighter.items {
+		items[i].Item = item
+		histogram := weighter.histograms[item]
+		if histogram.total == 0 {
+			continue
+		}
+		// The weight is the number of requests per second at the latency.
+		latency := histogram.quantile(weighter.quantile)
+		items[i].Weight = decimal.NewFromInt(int64(time.Second)).Div(decimal.NewFromInt(int64(latency)))
+		sum = sum.Add(items[i].Weight)
+		observed++
+	}
+	average := One
+	if observed > 0 {
+		average = sum.Div(decimal.NewFromInt(int64(observed)))
+	}
+	for i := range items {
+		if items[i].Weight.IsZero() {
+			items[i].Weight = average
+		}
+	}
+	return items
+}
@@ -0,0 +1,52 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyWeighter(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewLatencyWeighter[MarbleColor](nil, 0.5)
+		})
+		assert.Panics(t, func() {
+			NewLatencyWeighter(nil, 1.5, Red)
+		})
+	})
+	t.Run("weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		lw := NewLatencyWeighter(r, 0.9, Red, Green, Blue)
+		counts := Counts[MarbleColor](lw, 30_000)
+		assert.InDeltaf(t, 1.0/3, float64(counts[Red])/30_000, tolerance, "%v", counts)
+
+		for range 100 {
+			lw.Observe(Red, 10*time.Millisecond)
+			lw.Observe(Blue, 40*time.Millisecond)
+		}
+		lw.Observe(Yellow, time.Hour)
+		weights := lw.Weights()
+		assert.Equal(t, Red, weights[0].Item)
+		assert.True(t, weights[0].Weight.Equal(weights[2].Weight.Mul(FixtureDecimal(t, "4"))), "%v", weights)
+		assert.True(t, weights[1].Weight.Equal(weights[0].Weight.Add(weights[2].Weight).Div(FixtureDecimal(t, "2"))), "%v", weights)
+
+		lw.Recompute()
+		counts = Counts[MarbleColor](lw, 100_000)
+		// Red is weighted 4, Blue is weighted 1, and Green the average of 2.5.
+		assert.InDeltaf(t, 4.0/7.5, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 1.0/7.5, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 2.5/7.5, float64(counts[Green])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("items are copied", func(t *testing.T) {
+		items := []MarbleColor{Red, Blue}
+		lw := NewLatencyWeighter(rand.New(rand.NewSource(1)), 0.9, items...)
+		items[0] = Green
+		weights := lw.Weights()
+		assert.Equal(t, Red, weights[0].Item)
+		assert.NotContains(t, Counts[MarbleColor](lw, 1_000), Green)
+	})
+}
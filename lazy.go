@@ -0,0 +1,59 @@
+package weightedrand
+
+// lazyAliasMethod selects an index from an alias table, and materializes
+// only the item at the selected index.
+type lazyAliasMethod[TItem any] struct {
+	indices     voseAliasMethodRandom[int]
+	materialize func(i int) TItem
+}
+
+// NewLazy constructs a new WeightedRandom instance using the Alias Method
+// (Vose's algorithm) over items represented by their index, such that item
+// i has the weight weights[i] and is constructed by materialize(i) only
+// when it is selected. This keeps large items, such as multi-kilobyte
+// payloads loaded from storage, out of memory until they are needed. The
+// items are materialized on every selection, so materialize should cache
+// them if constructing them is costly and selections repeat.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1.
+//
+// The function panics if no weights are provided, materialize is nil, or
+// weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random:      A RandIntN implementation used for random number generation.
+//   - weights:     The weights of the items, by index.
+//   - materialize: A function constructing the item at an index.
+//
+// Example usage:
+//
+//	wr := NewLazy(randSource, manifest.Weights, func(i int) Document {
+//		return loadDocument(manifest.Keys[i])
+//	})
+func NewLazy[TItem any, TWeight Weight](random RandIntN, weights []TWeight, materialize func(i int) TItem) WeightedRandom[TItem] {
+	if len(weights) == 0 {
+		panic("at least one item must be provided")
+	} else if materialize == nil {
+		panic("materialize must not be nil")
+	}
+	indices := make([]weightedItem[int], len(weights))
+	for i, weight := range weights {
+		indices[i] = weightedItem[int]{
+			Item:   i,
+			Weight: effectiveWeight(weight),
+		}
+	}
+	return lazyAliasMethod[TItem]{
+		indices:     newVoseAliasMethodFromDecimals(random, indices),
+		materialize: materialize,
+	}
+}
+
+func (aliasMethod lazyAliasMethod[TItem]) Next() TItem {
+	return aliasMethod.materialize(aliasMethod.indices.Next())
+}
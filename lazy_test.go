@@ -0,0 +1,47 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy(t *testing.T) {
+	colors := []MarbleColor{Red, Green, Blue}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewLazy(nil, []int{}, func(i int) MarbleColor { return colors[i] })
+		})
+		assert.Panics(t, func() {
+			NewLazy[MarbleColor](nil, []int{1}, nil)
+		})
+		assert.Panics(t, func() {
+			NewLazy(nil, []int{-1}, func(i int) MarbleColor { return colors[i] })
+		})
+	})
+	t.Run("materializes selected items", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		materialized := make(map[int]int)
+		wr := NewLazy(r, []int{1, 0, 2, 0}, func(i int) MarbleColor {
+			materialized[i]++
+			return colors[i%len(colors)]
+		})
+		assert.Empty(t, materialized)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		total := 0
+		for _, count := range materialized {
+			total += count
+		}
+		assert.Equal(t, iterations, total)
+		assert.InDeltaf(t, 0.40, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.20, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.40, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+}
@@ -0,0 +1,138 @@
+// Package lite is a lightweight weighted random selection for constrained
+// targets, such as TinyGo or WebAssembly in the browser. It depends only on
+// the standard library, without decimal arithmetic or reflection, so it
+// compiles small and runs fast, at the cost of only accepting integer
+// weights whose total fits in an int64.
+//
+// Tables are built like the integer engine of weightedrand, so given the
+// same random number generator, both select the same items.
+package lite
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+)
+
+// Weight is a type constraint that allows any signed or unsigned integer type.
+type Weight interface {
+	int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64
+}
+
+// RandIntN is the random number generator used for selection. It has the
+// same methods as weightedrand.RandIntN, so that *rand.Rand satisfies both.
+type RandIntN interface {
+	Intn(n int) int
+	Int63n(n int64) int64
+}
+
+// WeightedItem represents an item with an associated weight.
+type WeightedItem[TItem any, TWeight Weight] struct {
+	Item   TItem
+	Weight TWeight
+}
+
+// WeightedRandom selects items by their weights.
+type WeightedRandom[TItem any] struct {
+	random     RandIntN
+	items      []TItem
+	total      int64
+	thresholds []int64
+	aliases    []int
+}
+
+// New constructs a new WeightedRandom instance using the Alias Method
+// (Vose's algorithm). If no weight is provided, it is assumed to be 1.
+//
+// The function panics if no items are provided, weights are negative, or
+// the total weight overflows an int64.
+//
+// Example usage:
+//
+//	wr := lite.New(randSource, lite.WeightedItem[string, int]{Item: "A", Weight: 2}, lite.WeightedItem[string, int]{Item: "B", Weight: 3})
+//	item := wr.Next()
+func New[TItem any, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) *WeightedRandom[TItem] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	}
+	count := uint64(len(items))
+	wr := &WeightedRandom[TItem]{
+		random:     random,
+		items:      make([]TItem, len(items)),
+		thresholds: make([]int64, len(items)),
+		aliases:    make([]int, len(items)),
+	}
+	// Every bucket has a capacity equal to the total weight, and every
+	// weight is scaled by the number of items so that the weights average to
+	// the capacity.
+	scaled := make([]uint64, len(items))
+	total := uint64(0)
+	for i, currentItem := range items {
+		wr.items[i] = currentItem.Item
+		weight := weightAsUint64(currentItem.Weight)
+		if weight > math.MaxInt64 || total > math.MaxInt64-weight {
+			panic("the total weight overflows an int64")
+		}
+		total += weight
+		hi, lo := bits.Mul64(weight, count)
+		if hi != 0 || lo > math.MaxInt64 {
+			panic("the scaled weight overflows an int64")
+		}
+		scaled[i] = lo
+	}
+	wr.total = int64(total)
+
+	// Create two worklists, Small and Large.
+	small := make([]int, 0, len(items))
+	large := make([]int, 0, len(items))
+	for i, weight := range scaled {
+		if weight < total {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		lesser, greater := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		wr.thresholds[lesser] = int64(scaled[lesser])
+		wr.aliases[lesser] = greater
+		// Neither value exceeds math.MaxInt64, so the sum cannot overflow.
+		scaled[greater] = scaled[greater] + scaled[lesser] - total
+		if scaled[greater] < total {
+			small = append(small, greater)
+		} else {
+			large = append(large, greater)
+		}
+	}
+	// Integer arithmetic is exact, so whatever remains fills its bucket.
+	for _, i := range append(small, large...) {
+		wr.thresholds[i] = wr.total
+		wr.aliases[i] = i
+	}
+	return wr
+}
+
+// Next selects an item by weight.
+func (wr *WeightedRandom[TItem]) Next() TItem {
+	// First, perform a fair dice roll.
+	fairDiceRoll := wr.random.Intn(len(wr.items))
+	// Second, perform an unfair coin toss against the bucket's capacity.
+	if wr.random.Int63n(wr.total) < wr.thresholds[fairDiceRoll] {
+		return wr.items[fairDiceRoll]
+	}
+	return wr.items[wr.aliases[fairDiceRoll]]
+}
+
+// weightAsUint64 converts the weight, applying the defaulting rule that a
+// weight of zero is assumed to be 1.
+func weightAsUint64[TWeight Weight](weight TWeight) uint64 {
+	if weight < 0 {
+		// The conversion is exact, since the weight is a negative integer.
+		panic("weight must be non-negative value, but was " + strconv.FormatInt(int64(weight), 10))
+	} else if weight == 0 {
+		return 1
+	}
+	return uint64(weight)
+}
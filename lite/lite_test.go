@@ -0,0 +1,43 @@
+package lite_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"github.com/nikole-dunixi/weightedrand/lite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			lite.New[string, int](nil)
+		})
+		assert.PanicsWithValue(t, "weight must be non-negative value, but was -1", func() {
+			lite.New(nil, lite.WeightedItem[string, int]{Item: "A", Weight: -1})
+		})
+		assert.Panics(t, func() {
+			lite.New(nil, lite.WeightedItem[string, uint64]{Item: "A", Weight: 1 << 63})
+		})
+	})
+	t.Run("matches the integer engine", func(t *testing.T) {
+		seed := time.Now().Unix()
+		items := []weightedrand.WeightedItem[string, int]{
+			{Item: "A", Weight: 1},
+			{Item: "B", Weight: 0},
+			{Item: "C", Weight: 7},
+			{Item: "D", Weight: 12},
+		}
+		liteItems := make([]lite.WeightedItem[string, int], len(items))
+		for i, item := range items {
+			liteItems[i] = lite.WeightedItem[string, int]{Item: item.Item, Weight: item.Weight}
+		}
+		expected := weightedrand.NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(seed)), items, weightedrand.WithIntegerEngine())
+		actual := lite.New(rand.New(rand.NewSource(seed)), liteItems...)
+		for range 10_000 {
+			assert.Equal(t, expected.Next(), actual.Next())
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package weightedrand
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// MetricTransform converts the value of a metric into a weight, such as the
+// requests per second of a backend into its share of new requests.
+type MetricTransform func(value float64) float64
+
+// Inverse weights items inversely to their metric, so that less loaded
+// backends receive more traffic. Values below the floor are treated as the
+// floor, so that idle backends do not receive an unbounded weight.
+//
+// Panics:
+//   - If the floor is not positive.
+func Inverse(floor float64) MetricTransform {
+	if !(floor > 0) {
+		panic(fmt.Sprintf("floor must be positive value, but was %g", floor))
+	}
+	return func(value float64) float64 {
+		return 1 / max(value, floor)
+	}
+}
+
+// Capped limits the weights to the maximum.
+func Capped(maximum float64) MetricTransform {
+	return func(value float64) float64 {
+		return min(value, maximum)
+	}
+}
+
+// ChainTransforms applies the transforms in order.
+func ChainTransforms(transforms ...MetricTransform) MetricTransform {
+	return func(value float64) float64 {
+		for _, transform := range transforms {
+			value = transform(value)
+		}
+		return value
+	}
+}
+
+// MetricWeighter is a WeightedRandom over the labels of a metric snapshot,
+// such as the QPS of every backend scraped from Prometheus, whose weights
+// are derived from the metric by a transform. Every snapshot passed to
+// Update rebuilds the table, which is swapped in without blocking
+// selections.
+//
+// MetricWeighter is safe for concurrent use, given that the random number
+// generator is.
+type MetricWeighter struct {
+	random    RandIntN
+	transform MetricTransform
+	swappable Swappable[string]
+	mutex     sync.Mutex
+	alpha     float64
+	smoothed  map[string]float64
+}
+
+// NewMetricWeighter constructs a new MetricWeighter, which is ready for
+// selection once the first snapshot is passed to Update. A nil transform
+// uses the metric as the weight.
+//
+// Example usage:
+//
+//	mw := NewMetricWeighter(randSource, ChainTransforms(Inverse(1), Capped(0.5))).WithSmoothing(0.3)
+//	if err := mw.Update(map[string]float64{"backend-a": 120, "backend-b": 40}); err != nil {
+//		return err
+//	}
+//	backend := mw.Next()
+func NewMetricWeighter(random RandIntN, transform MetricTransform) *MetricWeighter {
+	if transform == nil {
+		transform = func(value float64) float64 {
+			return value
+		}
+	}
+	return &MetricWeighter{
+		random:    random,
+		transform: transform,
+		alpha:     1,
+	}
+}
+
+// WithSmoothing smooths the weights across snapshots with an exponentially
+// weighted moving average, so that a single noisy scrape does not swing the
+// traffic. An alpha of 1 disables smoothing, and lower values smooth more.
+// It returns the MetricWeighter to allow chaining.
+//
+// Panics:
+//   - If alpha is not within (0, 1].
+func (weighter *MetricWeighter) WithSmoothing(alpha float64) *MetricWeighter {
+	if !(alpha > 0 && alpha <= 1) {
+		panic(fmt.Sprintf("alpha must be within (0, 1], but was %g", alpha))
+	}
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	weighter.alpha = alpha
+	return weighter
+}
+
+// Update transforms the metric of every label into its weight, and rebuilds
+// the table. Labels that are absent from the snapshot are removed, and
+// labels whose weight is zero are never selected.
+//
+// Returns:
+//   - error: If a weight is negative or not finite, or no label has a
+//     positive weight. The previous table then remains in service.
+func (weighter *MetricWeighter) Update(snapshot map[string]float64) error {
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	smoothed := make(map[string]float64, len(snapshot))
+	items := make([]weightedItem[string], 0, len(snapshot))
+	// Labels are sorted, so that the same snapshot builds the same table.
+	for _, label := range slices.Sorted(maps.Keys(snapshot)) {
+		weight := weighter.transform(snapshot[label])
+		if math.IsNaN(weight) || math.IsInf(weight, 0) || weight < 0 {
+			return fmt.Errorf("weight of %q must be a finite non-negative value, but was %g", label, weight)
+		}
+		if previous, ok := weighter.smoothed[label]; ok {
+			weight = weighter.alpha*weight + (1-weighter.alpha)*previous
+		}
+		smoothed[label] = weight
+		// Zero weights are dropped, rather than being assumed to be 1.
+		if decimalWeight := decimal.NewFromFloat(weight); decimalWeight.IsPositive() {
+			items = append(items, weightedItem[string]{
+				Item:   label,
+				Weight: decimalWeight,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return ErrAllZeroWeights
+	}
+	weighter.smoothed = smoothed
+	weighter.swappable.Swap(newVoseAliasMethodFromDecimals(weighter.random, items))
+	return nil
+}
+
+// Next selects a label by its weight.
+//
+// Panics:
+//   - If no snapshot was successfully passed to Update.
+func (weighter *MetricWeighter) Next() string {
+	current := weighter.swappable.current.Load()
+	if current == nil {
+		panic("no snapshot has been applied")
+	}
+	return (*current).Next()
+}
+
+// Weights returns the current weight of every label, after smoothing.
+func (weighter *MetricWeighter) Weights() map[string]float64 {
+	weighter.mutex.Lock()
+	defer weighter.mutex.Unlock()
+	return maps.Clone(weighter.smoothed)
+}
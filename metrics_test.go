@@ -0,0 +1,65 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricWeighter(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Inverse(0)
+		})
+		assert.Panics(t, func() {
+			NewMetricWeighter(r, nil).WithSmoothing(0)
+		})
+		assert.Panics(t, func() {
+			NewMetricWeighter(r, nil).Next()
+		})
+	})
+	t.Run("transforms", func(t *testing.T) {
+		transform := ChainTransforms(Inverse(10), Capped(0.05))
+		assert.InDelta(t, 0.01, transform(100), 1e-12)
+		assert.InDelta(t, 0.05, transform(15), 1e-12)
+		assert.InDelta(t, 0.05, transform(0), 1e-12)
+	})
+	t.Run("errors", func(t *testing.T) {
+		mw := NewMetricWeighter(r, nil)
+		assert.ErrorIs(t, mw.Update(map[string]float64{}), ErrAllZeroWeights)
+		assert.ErrorIs(t, mw.Update(map[string]float64{"a": 0}), ErrAllZeroWeights)
+		assert.ErrorContains(t, mw.Update(map[string]float64{"a": 1, "b": -1}), `weight of "b"`)
+		assert.ErrorContains(t, mw.Update(map[string]float64{"a": math.NaN()}), `weight of "a"`)
+	})
+	t.Run("inverse load", func(t *testing.T) {
+		mw := NewMetricWeighter(r, Inverse(1))
+		require.NoError(t, mw.Update(map[string]float64{"a": 100, "b": 300}))
+		const iterations = 100_000
+		counts := make(map[string]int)
+		for range iterations {
+			counts[mw.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.75, float64(counts["a"])/iterations, tolerance, "%v", counts)
+		require.NoError(t, mw.Update(map[string]float64{"b": 300, "c": 0}))
+		counts = make(map[string]int)
+		for range iterations {
+			counts[mw.Next()] += 1
+		}
+		assert.Zero(t, counts["a"])
+		assert.InDeltaf(t, 300.0/301, float64(counts["c"])/iterations, tolerance, "%v", counts)
+	})
+	t.Run("smoothing", func(t *testing.T) {
+		mw := NewMetricWeighter(r, nil).WithSmoothing(0.5)
+		require.NoError(t, mw.Update(map[string]float64{"a": 10, "b": 10}))
+		require.NoError(t, mw.Update(map[string]float64{"a": 30, "b": 10, "c": 4}))
+		assert.Equal(t, map[string]float64{"a": 20, "b": 10, "c": 4}, mw.Weights())
+		require.NoError(t, mw.Update(map[string]float64{"a": 30}))
+		assert.Equal(t, map[string]float64{"a": 25}, mw.Weights())
+	})
+}
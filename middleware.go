@@ -0,0 +1,136 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Middleware wraps a WeightedRandom in another, which changes or observes
+// its selections, such as to record metrics or avoid repeats. Middlewares
+// are stacked with Chain.
+type Middleware[TItem any] func(WeightedRandom[TItem]) WeightedRandom[TItem]
+
+// middlewareRandom adapts a function to WeightedRandom, reporting the
+// distribution of the instance it wraps, so that choosers built on the
+// distribution, such as NewMonitor, can be stacked on top of middlewares.
+type middlewareRandom[TItem any] struct {
+	next    func() TItem
+	wrapped WeightedRandom[TItem]
+}
+
+func (middleware middlewareRandom[TItem]) Next() TItem {
+	return middleware.next()
+}
+
+func (middleware middlewareRandom[TItem]) weights() []weightedItem[TItem] {
+	return distributionOf(middleware.wrapped).weights()
+}
+
+func (middleware middlewareRandom[TItem]) randomSource() RandIntN {
+	return distributionOf(middleware.wrapped).randomSource()
+}
+
+// resolvedShares resolves the coin tosses of the wrapped instance, if it
+// has a limited resolution.
+func (middleware middlewareRandom[TItem]) resolvedShares() []weightedItem[TItem] {
+	sampler, ok := middleware.wrapped.(starvingSampler[TItem])
+	if !ok {
+		return nil
+	}
+	return sampler.resolvedShares()
+}
+
+// noRepeatAttempts is the number of times NoRepeat draws again before it
+// accepts a repeated selection.
+const noRepeatAttempts = 32
+
+// Chain wraps the WeightedRandom instance in the middlewares, in the order
+// they are provided: the first wraps the instance, and every other wraps
+// the one before it, so that a selection passes through the middlewares in
+// order on its way to the caller.
+//
+// The wrapped instances report the distribution of the instance they wrap,
+// so that they can be wrapped by choosers that depend on it, such as
+// NewMonitor, or inspected, such as by Fingerprint. The reported
+// distribution is that of the weights, which middlewares that change the
+// selections, such as NoRepeat, deviate from.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - wr:          The WeightedRandom instance to wrap.
+//   - middlewares: A variadic list of Middleware values.
+//
+// Returns:
+//   - WeightedRandom[TItem]: The instance wrapped by the last middleware, or wr itself if none are provided.
+//
+// Example usage:
+//
+//	wr := Chain(NewAliasVoseMethod(randSource, items...),
+//		NoRepeat[string](3),
+//		Observe(func(item string) { selections.WithLabelValues(item).Inc() }),
+//		func(wr WeightedRandom[string]) WeightedRandom[string] {
+//			return NewMonitor(wr, 10_000).WithOnDeviation(alert)
+//		},
+//	)
+func Chain[TItem any](wr WeightedRandom[TItem], middlewares ...Middleware[TItem]) WeightedRandom[TItem] {
+	for _, middleware := range middlewares {
+		wr = middleware(wr)
+	}
+	return wr
+}
+
+// Observe returns a Middleware that calls observe with every selection
+// before it is returned, such as to record metrics.
+func Observe[TItem any](observe func(TItem)) Middleware[TItem] {
+	return func(wr WeightedRandom[TItem]) WeightedRandom[TItem] {
+		return middlewareRandom[TItem]{
+			next: func() TItem {
+				item := wr.Next()
+				observe(item)
+				return item
+			},
+			wrapped: wr,
+		}
+	}
+}
+
+// NoRepeat returns a Middleware that avoids selecting any of the most
+// recent selections within the window, such as to not play the same track
+// twice in a row, by drawing again. Items are thus selected in proportion
+// to their weights among those outside the window. If only items within the
+// window are drawn after several attempts, such as when there are too few
+// items, the last of them is selected regardless.
+//
+// The wrapped instance is safe for concurrent use, given that the instance
+// it wraps is.
+//
+// Panics:
+//   - If the window is not positive.
+func NoRepeat[TItem comparable](window int) Middleware[TItem] {
+	if window <= 0 {
+		panic(fmt.Sprintf("window must be positive, but was %d", window))
+	}
+	return func(wr WeightedRandom[TItem]) WeightedRandom[TItem] {
+		var mutex sync.Mutex
+		recent := make([]TItem, 0, window)
+		return middlewareRandom[TItem]{
+			next: func() TItem {
+				mutex.Lock()
+				defer mutex.Unlock()
+				item := wr.Next()
+				for attempt := 1; attempt < noRepeatAttempts && slices.Contains(recent, item); attempt++ {
+					item = wr.Next()
+				}
+				if len(recent) == window {
+					recent = append(recent[:0], recent[1:]...)
+				}
+				recent = append(recent, item)
+				return item
+			},
+			wrapped: wr,
+		}
+	}
+}
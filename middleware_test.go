@@ -0,0 +1,79 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 2},
+		{Item: Green, Weight: 1},
+		{Item: Blue, Weight: 1},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NoRepeat[MarbleColor](0)
+		})
+	})
+	t.Run("without middlewares", func(t *testing.T) {
+		wr := NewAliasVoseMethod(random, items...)
+		assert.Equal(t, wr, Chain(wr))
+	})
+	t.Run("order", func(t *testing.T) {
+		var order []string
+		wr := Chain(NewAliasVoseMethod(random, items...),
+			Observe(func(MarbleColor) { order = append(order, "first") }),
+			Observe(func(MarbleColor) { order = append(order, "second") }),
+		)
+		wr.Next()
+		assert.Equal(t, []string{"first", "second"}, order)
+	})
+	t.Run("no repeats", func(t *testing.T) {
+		counts := MarbleColorCounts{}
+		wr := Chain(NewAliasVoseMethod(random, items...),
+			NoRepeat[MarbleColor](1),
+			Observe(func(color MarbleColor) { counts[color]++ }),
+		)
+		previous := wr.Next()
+		for range 1000 {
+			color := wr.Next()
+			assert.NotEqual(t, previous, color)
+			previous = color
+		}
+		assert.Equal(t, int64(1001), counts[Red]+counts[Green]+counts[Blue])
+	})
+	t.Run("documented chain", func(t *testing.T) {
+		// Equal weights are not skewed by avoiding repeats, so the monitor
+		// has nothing to report.
+		tracks := make([]WeightedItem[int, int], 20)
+		for index := range tracks {
+			tracks[index] = WeightedItem[int, int]{Item: index, Weight: 1}
+		}
+		base := NewAliasVoseMethod(rand.New(rand.NewSource(42)), tracks...)
+		selections := 0
+		deviations := 0
+		wr := Chain(base,
+			NoRepeat[int](3),
+			Observe(func(int) { selections++ }),
+			func(wr WeightedRandom[int]) WeightedRandom[int] {
+				return NewMonitor(wr, 10_000).WithOnDeviation(func(Deviation[int]) { deviations++ })
+			},
+		)
+		for range 20_000 {
+			wr.Next()
+		}
+		assert.Equal(t, 20_000, selections)
+		assert.Zero(t, deviations)
+		observed := Chain(base, NoRepeat[int](3), Observe(func(int) {}))
+		assert.Equal(t, Fingerprint(base), Fingerprint(observed))
+		assert.NotPanics(t, func() {
+			NewTraced(observed, func(context.Context, DrawAnnotation[int]) {}).Next()
+		})
+	})
+}
@@ -0,0 +1,144 @@
+package weightedrand
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// probabilityScale is the number of decimal places a probability keeps when
+// deciding an outcome with it. Probabilities are exact up to this precision.
+const probabilityScale = 18
+
+// probabilityResolution is the number of distinct outcomes of a decision,
+// which is 10^probabilityScale.
+const probabilityResolution int64 = 1_000_000_000_000_000_000
+
+// probabilityThreshold converts the probability into the number of outcomes
+// out of probabilityResolution that decide in favor.
+func probabilityThreshold(probability decimal.Decimal) int64 {
+	if probability.IsNegative() || probability.GreaterThan(One) {
+		panic(fmt.Sprintf("probability must be between 0 and 1, but was %s", probability.String()))
+	}
+	return probability.Shift(probabilityScale).IntPart()
+}
+
+// bernoulli decides in favor with the probability represented by the
+// threshold.
+func bernoulli(random RandIntN, threshold int64) bool {
+	return random.Int63n(probabilityResolution) < threshold
+}
+
+// Mirror returns a function that reports true with the given probability,
+// for deciding whether a request should be mirrored. The probability is
+// exact up to 18 decimal places.
+//
+// The returned function is safe for concurrent use, given that the random
+// number generator is.
+//
+// Panics:
+//   - If the probability is not between 0 and 1.
+//
+// Example usage:
+//
+//	shouldMirror := Mirror(randSource, decimal.RequireFromString("0.05"))
+func Mirror(random RandIntN, probability decimal.Decimal) func() bool {
+	threshold := probabilityThreshold(probability)
+	return func() bool {
+		return bernoulli(random, threshold)
+	}
+}
+
+// MirrorTransport is an http.RoundTripper that sends every request to its
+// base transport, and additionally sends a copy of some requests to a shadow
+// backend. Shadow requests are sent asynchronously, their responses are
+// discarded, and they never affect the response of the original request.
+type MirrorTransport struct {
+	base         http.RoundTripper
+	shadow       *url.URL
+	shouldMirror func() bool
+	onError      func(error)
+	inflight     sync.WaitGroup
+}
+
+// NewMirrorTransport constructs a new MirrorTransport. A nil base uses
+// http.DefaultTransport. Mirrored requests keep their path and query, but
+// are sent to the scheme and host of the shadow URL.
+//
+// Example usage:
+//
+//	shadow, _ := url.Parse("http://shadow.internal:8080")
+//	client := &http.Client{
+//		Transport: NewMirrorTransport(nil, shadow, Mirror(randSource, decimal.RequireFromString("0.1"))),
+//	}
+func NewMirrorTransport(base http.RoundTripper, shadow *url.URL, shouldMirror func() bool) *MirrorTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &MirrorTransport{
+		base:         base,
+		shadow:       shadow,
+		shouldMirror: shouldMirror,
+		onError:      func(error) {},
+	}
+}
+
+// WithErrorHandler sets the function called when a shadow request fails. It
+// returns the MirrorTransport to allow chaining.
+func (transport *MirrorTransport) WithErrorHandler(onError func(error)) *MirrorTransport {
+	transport.onError = onError
+	return transport
+}
+
+// RoundTrip sends the request to the base transport, and mirrors it to the
+// shadow backend if selected to.
+func (transport *MirrorTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !transport.shouldMirror() {
+		return transport.base.RoundTrip(request)
+	}
+	var body []byte
+	if request.Body != nil && request.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// Replace the consumed body, without modifying the caller's request.
+		request = request.Clone(request.Context())
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	// The shadow request must outlive the original one.
+	shadowRequest := request.Clone(context.WithoutCancel(request.Context()))
+	shadowRequest.URL.Scheme = transport.shadow.Scheme
+	shadowRequest.URL.Host = transport.shadow.Host
+	shadowRequest.Host = transport.shadow.Host
+	if body != nil {
+		shadowRequest.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	transport.inflight.Add(1)
+	go func() {
+		defer transport.inflight.Done()
+		response, err := transport.base.RoundTrip(shadowRequest)
+		if err != nil {
+			transport.onError(err)
+			return
+		}
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+	}()
+	return transport.base.RoundTrip(request)
+}
+
+// Wait blocks until every shadow request in flight has completed, such as
+// during a graceful shutdown.
+func (transport *MirrorTransport) Wait() {
+	transport.inflight.Wait()
+}
@@ -0,0 +1,82 @@
+package weightedrand_test
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirror(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Mirror(nil, decimal.NewFromInt(-1))
+		})
+		assert.Panics(t, func() {
+			Mirror(nil, FixtureDecimal(t, "1.01"))
+		})
+	})
+	t.Run("probability", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		testcases := []string{"0", "0.05", "0.5", "1"}
+		for _, testcase := range testcases {
+			shouldMirror := Mirror(r, FixtureDecimal(t, testcase))
+			const iterations = 100_000
+			mirrored := 0
+			for range iterations {
+				if shouldMirror() {
+					mirrored++
+				}
+			}
+			expected := FixtureDecimal(t, testcase).InexactFloat64()
+			assert.InDeltaf(t, expected, float64(mirrored)/iterations, tolerance, "probability %s", testcase)
+		}
+	})
+}
+
+func TestMirrorTransport(t *testing.T) {
+	var mutex sync.Mutex
+	var shadowBodies []string
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mutex.Lock()
+		defer mutex.Unlock()
+		shadowBodies = append(shadowBodies, r.URL.Path+":"+string(body))
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer shadow.Close()
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer primary.Close()
+	shadowURL, err := url.Parse(shadow.URL)
+	require.NoError(t, err)
+
+	mirrored := true
+	transport := NewMirrorTransport(nil, shadowURL, func() bool {
+		return mirrored
+	})
+	client := &http.Client{Transport: transport}
+	for _, body := range []string{"first", "second"} {
+		response, err := client.Post(primary.URL+"/orders", "text/plain", strings.NewReader(body))
+		require.NoError(t, err)
+		received, _ := io.ReadAll(response.Body)
+		response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, body, string(received))
+		mirrored = false
+	}
+	transport.Wait()
+	assert.Equal(t, []string{"/orders:first"}, shadowBodies)
+}
@@ -0,0 +1,189 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Deviation describes a window of selections whose frequencies deviate from
+// the expected distribution.
+type Deviation[TItem comparable] struct {
+	// Statistic is Pearson's chi-square statistic of the window.
+	Statistic float64
+	// PValue is the probability of a deviation at least as large, if the
+	// selections did follow the expected distribution.
+	PValue float64
+	// Observed is the number of times each item was selected in the window.
+	Observed map[TItem]int
+	// Expected is the number of times each item was expected to be selected
+	// in the window.
+	Expected map[TItem]float64
+}
+
+// Monitor is a WeightedRandom that continuously tests its selections
+// against the distribution of the instance it wraps, such as to detect a
+// broken random number generator or a biased engine in production. Every
+// few selections, it runs a chi-square goodness-of-fit test over a sliding
+// window of the most recent selections, and reports through a callback when
+// the window starts deviating beyond the significance level. The test is
+// not run on every selection, as it evaluates the chi-square distribution
+// while the Monitor is locked.
+//
+// The test assumes that every item is expected to be selected several times
+// within the window, so the window should be large relative to the inverse
+// of the smallest probability.
+//
+// Monitor is safe for concurrent use, given that the wrapped instance is.
+type Monitor[TItem comparable] struct {
+	wr           WeightedRandom[TItem]
+	frequencies  expectedFrequencies[TItem]
+	mutex        sync.Mutex
+	window       []int
+	position     int
+	filled       bool
+	counts       []int
+	significance float64
+	onDeviation  func(Deviation[TItem])
+	deviating    bool
+	// interval is the number of selections between tests, and untested the
+	// number of selections since the last test.
+	interval int
+	untested int
+}
+
+// NewMonitor constructs a new Monitor over the WeightedRandom instance,
+// testing the most recent selections within the window size. The
+// significance level defaults to 0.001, the window is tested every
+// hundredth of its size in selections, and deviations are ignored until a
+// callback is configured with WithOnDeviation.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - wr:     The WeightedRandom instance whose selections are tested.
+//   - window: The number of most recent selections that are tested.
+//
+// Panics:
+//   - If the window is not positive.
+//   - If the distribution of the instance cannot be determined, because it
+//     was not constructed by this package.
+//
+// Example usage:
+//
+//	m := NewMonitor(wr, 10_000).WithOnDeviation(func(d Deviation[string]) {
+//		log.Printf("selections deviate from the weights (p=%g): %v", d.PValue, d.Observed)
+//	})
+func NewMonitor[TItem comparable](wr WeightedRandom[TItem], window int) *Monitor[TItem] {
+	if window <= 0 {
+		panic(fmt.Sprintf("window must be positive value, but was %d", window))
+	}
+	frequencies := newExpectedFrequencies(wr)
+	return &Monitor[TItem]{
+		wr:           wr,
+		frequencies:  frequencies,
+		window:       make([]int, window),
+		counts:       make([]int, len(frequencies.items)),
+		significance: 0.001,
+		interval:     max(1, window/100),
+	}
+}
+
+// WithSignificance sets the significance level, which is the p-value below
+// which a window is considered to deviate. Lower levels report fewer false
+// positives, but need larger deviations. It returns the Monitor to allow
+// chaining.
+//
+// Panics:
+//   - If the significance is not within (0, 1).
+func (monitor *Monitor[TItem]) WithSignificance(significance float64) *Monitor[TItem] {
+	if !(significance > 0 && significance < 1) {
+		panic(fmt.Sprintf("significance must be within (0, 1), but was %g", significance))
+	}
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	monitor.significance = significance
+	return monitor
+}
+
+// WithCheckInterval sets the number of selections between tests of the
+// window, which trades the cost of the test against how soon a deviation is
+// reported. The window is first tested once it is full and the interval has
+// passed. It returns the Monitor to allow chaining.
+//
+// Panics:
+//   - If the interval is not positive.
+func (monitor *Monitor[TItem]) WithCheckInterval(interval int) *Monitor[TItem] {
+	if interval <= 0 {
+		panic(fmt.Sprintf("interval must be positive value, but was %d", interval))
+	}
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	monitor.interval = interval
+	return monitor
+}
+
+// WithOnDeviation sets the callback that is called when the window starts
+// deviating. It is called once per deviation rather than on every
+// selection, and again only after the window has recovered. It returns the
+// Monitor to allow chaining.
+func (monitor *Monitor[TItem]) WithOnDeviation(onDeviation func(Deviation[TItem])) *Monitor[TItem] {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	monitor.onDeviation = onDeviation
+	return monitor
+}
+
+// Next selects an item from the wrapped instance, and tests the window once
+// it is full and the check interval has passed. The callback is called
+// before Next returns, but without the Monitor being locked.
+func (monitor *Monitor[TItem]) Next() TItem {
+	item := monitor.wr.Next()
+	deviation, ok := monitor.record(item)
+	if ok {
+		monitor.onDeviation(deviation)
+	}
+	return item
+}
+
+// Reset discards the selections of the window.
+func (monitor *Monitor[TItem]) Reset() {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	clear(monitor.counts)
+	monitor.position = 0
+	monitor.filled = false
+	monitor.deviating = false
+	monitor.untested = 0
+}
+
+// record adds the selection to the window, and reports a deviation if the
+// window is tested and started deviating, and there is a callback for it.
+func (monitor *Monitor[TItem]) record(item TItem) (Deviation[TItem], bool) {
+	monitor.mutex.Lock()
+	defer monitor.mutex.Unlock()
+	index := monitor.frequencies.indices[item]
+	if monitor.filled {
+		monitor.counts[monitor.window[monitor.position]]--
+	}
+	monitor.window[monitor.position] = index
+	monitor.counts[index]++
+	monitor.position++
+	if monitor.position == len(monitor.window) {
+		monitor.position = 0
+		monitor.filled = true
+	}
+	monitor.untested++
+	if !monitor.filled || monitor.untested < monitor.interval {
+		return Deviation[TItem]{}, false
+	}
+	monitor.untested = 0
+	size := len(monitor.window)
+	deviation := monitor.frequencies.test(monitor.counts, size)
+	wasDeviating := monitor.deviating
+	monitor.deviating = deviation.PValue < monitor.significance
+	if !monitor.deviating || wasDeviating || monitor.onDeviation == nil {
+		return Deviation[TItem]{}, false
+	}
+	return monitor.frequencies.describe(deviation, monitor.counts, size), true
+}
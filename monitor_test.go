@@ -0,0 +1,92 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+// stuckRand is a broken random number generator, which always returns 0.
+type stuckRand struct{}
+
+func (stuckRand) Intn(int) int       { return 0 }
+func (stuckRand) Int63n(int64) int64 { return 0 }
+
+func TestMonitor(t *testing.T) {
+	items := []WeightedItem[string, int]{
+		{Item: "A", Weight: 1},
+		{Item: "B", Weight: 2},
+		{Item: "C", Weight: 1},
+	}
+	t.Run("panic", func(t *testing.T) {
+		wr := NewAliasVoseMethod(nil, items...)
+		assert.Panics(t, func() {
+			NewMonitor(wr, 0)
+		})
+		assert.Panics(t, func() {
+			NewMonitor(wr, 10).WithSignificance(1)
+		})
+		assert.Panics(t, func() {
+			NewMonitor(wr, 10).WithCheckInterval(0)
+		})
+		assert.Panics(t, func() {
+			NewMonitor[int](opaqueWeightedRandom{}, 10)
+		})
+	})
+	t.Run("unbiased", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		deviations := 0
+		m := NewMonitor(NewAliasVoseMethod(r, items...), 1_000).
+			WithSignificance(1e-9).
+			WithOnDeviation(func(Deviation[string]) {
+				deviations++
+			})
+		for range 20_000 {
+			m.Next()
+		}
+		assert.Zero(t, deviations)
+	})
+	t.Run("biased", func(t *testing.T) {
+		var deviations []Deviation[string]
+		m := NewMonitor(NewAliasVoseMethod(stuckRand{}, items...), 100).
+			WithOnDeviation(func(d Deviation[string]) {
+				deviations = append(deviations, d)
+			})
+		for range 99 {
+			m.Next()
+		}
+		assert.Empty(t, deviations, "the window is not full")
+		for range 1_000 {
+			m.Next()
+		}
+		if assert.Len(t, deviations, 1, "deviations are reported once") {
+			deviation := deviations[0]
+			assert.Less(t, deviation.PValue, 0.001)
+			assert.Greater(t, deviation.Statistic, 100.0)
+			assert.Equal(t, 100, deviation.Observed[m.Next()])
+			assert.InDelta(t, 50.0, deviation.Expected["B"], 1e-9)
+		}
+		m.Reset()
+		for range 100 {
+			m.Next()
+		}
+		assert.Len(t, deviations, 2, "a reset window reports again")
+	})
+	t.Run("check interval", func(t *testing.T) {
+		deviations := 0
+		m := NewMonitor(NewAliasVoseMethod(stuckRand{}, items...), 10).
+			WithCheckInterval(25).
+			WithOnDeviation(func(Deviation[string]) {
+				deviations++
+			})
+		for range 24 {
+			m.Next()
+		}
+		assert.Zero(t, deviations, "the window is full, but not yet tested")
+		m.Next()
+		assert.Equal(t, 1, deviations)
+	})
+}
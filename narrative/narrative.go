@@ -0,0 +1,166 @@
+// Package narrative walks weighted outcome trees, such as the branches of an
+// interactive story or the steps of a tutorial flow. Every node of a tree has
+// weighted children, which may be guarded by conditions on the state of the
+// reader; a walk descends from the root by selecting one of the eligible
+// children at each node, until it reaches a node without any.
+//
+// Trees can be declared in Go, or loaded from JSON or YAML. Guards are Go
+// functions, and are referenced by name from the declarations.
+package narrative
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"gopkg.in/yaml.v3"
+)
+
+// Guard reports whether a node is eligible to be selected, given the state
+// of the walk.
+type Guard[TState any] func(state TState) bool
+
+// Node is a single outcome of a tree.
+type Node struct {
+	// ID uniquely identifies the node within the tree.
+	ID string `json:"id" yaml:"id"`
+	// Text is the content of the node, such as a passage of the story.
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
+	// Weight is the weight of the node among its siblings. If no weight is
+	// provided, it is assumed to be 1.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+	// Guard is the name of the guard the node must satisfy to be selected.
+	// If empty, the node is always eligible.
+	Guard string `json:"guard,omitempty" yaml:"guard,omitempty"`
+	// Children are the outcomes that may follow the node.
+	Children []Node `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+type compiledNode[TState any] struct {
+	node     Node
+	guard    Guard[TState]
+	children []*compiledNode[TState]
+}
+
+// Tree is a weighted outcome tree. It is immutable once constructed, and may
+// be walked any number of times.
+type Tree[TState any] struct {
+	random weightedrand.RandIntN
+	root   *compiledNode[TState]
+}
+
+// New constructs a Tree from its root node, with the guards referenced by
+// the nodes.
+//
+// The function panics if node IDs are empty or duplicated, or if a node
+// references a guard that was not provided.
+//
+// Example usage:
+//
+//	tree := narrative.New(randSource, narrative.Node{
+//		ID:   "cave",
+//		Text: "You enter a dark cave.",
+//		Children: []narrative.Node{
+//			{ID: "torch", Text: "You light your torch.", Guard: "has-torch"},
+//			{ID: "stumble", Text: "You stumble in the dark.", Weight: 3},
+//		},
+//	}, map[string]narrative.Guard[Player]{
+//		"has-torch": func(p Player) bool { return p.Inventory["torch"] },
+//	})
+//	path := tree.Walk(player)
+func New[TState any](random weightedrand.RandIntN, root Node, guards map[string]Guard[TState]) *Tree[TState] {
+	tree, err := compile(random, root, guards)
+	if err != nil {
+		panic(err.Error())
+	}
+	return tree
+}
+
+// LoadJSON reads the root node of a tree from JSON, and constructs the Tree
+// with the guards referenced by the nodes.
+func LoadJSON[TState any](random weightedrand.RandIntN, reader io.Reader, guards map[string]Guard[TState]) (*Tree[TState], error) {
+	var root Node
+	if err := json.NewDecoder(reader).Decode(&root); err != nil {
+		return nil, fmt.Errorf("could not decode tree: %w", err)
+	}
+	return compile(random, root, guards)
+}
+
+// LoadYAML reads the root node of a tree from YAML, and constructs the Tree
+// with the guards referenced by the nodes.
+func LoadYAML[TState any](random weightedrand.RandIntN, reader io.Reader, guards map[string]Guard[TState]) (*Tree[TState], error) {
+	var root Node
+	if err := yaml.NewDecoder(reader).Decode(&root); err != nil {
+		return nil, fmt.Errorf("could not decode tree: %w", err)
+	}
+	return compile(random, root, guards)
+}
+
+func compile[TState any](random weightedrand.RandIntN, root Node, guards map[string]Guard[TState]) (*Tree[TState], error) {
+	ids := make(map[string]struct{})
+	var compileNode func(node Node) (*compiledNode[TState], error)
+	compileNode = func(node Node) (*compiledNode[TState], error) {
+		if node.ID == "" {
+			return nil, errors.New("every node must have an ID")
+		} else if _, ok := ids[node.ID]; ok {
+			return nil, fmt.Errorf("node %q was provided more than once", node.ID)
+		}
+		ids[node.ID] = struct{}{}
+		compiled := &compiledNode[TState]{
+			node:     node,
+			children: make([]*compiledNode[TState], 0, len(node.Children)),
+		}
+		if node.Guard != "" {
+			guard, ok := guards[node.Guard]
+			if !ok || guard == nil {
+				return nil, fmt.Errorf("node %q references guard %q, which does not exist", node.ID, node.Guard)
+			}
+			compiled.guard = guard
+		}
+		for _, child := range node.Children {
+			compiledChild, err := compileNode(child)
+			if err != nil {
+				return nil, err
+			}
+			compiled.children = append(compiled.children, compiledChild)
+		}
+		return compiled, nil
+	}
+	compiledRoot, err := compileNode(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Tree[TState]{
+		random: random,
+		root:   compiledRoot,
+	}, nil
+}
+
+// Walk descends the tree from the root, and returns the path of nodes
+// visited, starting with the root. At every node, one of the children whose
+// guard is satisfied by the state is selected by weight, and the walk ends
+// at the first node without any eligible child. The guard of the root is not
+// evaluated.
+func (tree *Tree[TState]) Walk(state TState) []Node {
+	path := []Node{tree.root.node}
+	eligible := make([]*compiledNode[TState], 0)
+	for current := tree.root; ; {
+		eligible = eligible[:0]
+		for _, child := range current.children {
+			if child.guard == nil || child.guard(state) {
+				eligible = append(eligible, child)
+			}
+		}
+		if len(eligible) == 0 {
+			return path
+		}
+		// The eligible children depend on the state, so they are selected
+		// from without building a table.
+		current = eligible[weightedrand.Sample(eligible, func(i int) uint64 {
+			return eligible[i].node.Weight
+		}, tree.random)]
+		path = append(path, current.node)
+	}
+}
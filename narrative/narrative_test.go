@@ -0,0 +1,100 @@
+package narrative_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand/narrative"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type player struct {
+	torch bool
+}
+
+func testGuards() map[string]narrative.Guard[player] {
+	return map[string]narrative.Guard[player]{
+		"has-torch": func(p player) bool { return p.torch },
+	}
+}
+
+const testJSON = `{
+	"id": "cave",
+	"text": "You enter a dark cave.",
+	"children": [
+		{"id": "torch", "guard": "has-torch", "weight": 3, "children": [{"id": "treasure"}]},
+		{"id": "stumble"}
+	]
+}`
+
+const testYAML = `
+id: cave
+text: You enter a dark cave.
+children:
+  - id: torch
+    guard: has-torch
+    weight: 3
+    children:
+      - id: treasure
+  - id: stumble
+`
+
+func pathIDs(path []narrative.Node) string {
+	ids := make([]string, 0, len(path))
+	for _, node := range path {
+		ids = append(ids, node.ID)
+	}
+	return strings.Join(ids, ">")
+}
+
+func TestNew(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			narrative.New(r, narrative.Node{}, testGuards())
+		})
+		assert.Panics(t, func() {
+			narrative.New(r, narrative.Node{ID: "a", Children: []narrative.Node{{ID: "a"}}}, testGuards())
+		})
+		assert.Panics(t, func() {
+			narrative.New(r, narrative.Node{ID: "a", Children: []narrative.Node{{ID: "b", Guard: "missing"}}}, testGuards())
+		})
+	})
+	t.Run("load errors", func(t *testing.T) {
+		_, err := narrative.LoadJSON(r, strings.NewReader("{"), testGuards())
+		assert.Error(t, err)
+		_, err = narrative.LoadYAML(r, strings.NewReader(testYAML), map[string]narrative.Guard[player]{})
+		assert.ErrorContains(t, err, `guard "has-torch"`)
+	})
+}
+
+func TestWalk(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	jsonTree, err := narrative.LoadJSON(r, strings.NewReader(testJSON), testGuards())
+	require.NoError(t, err)
+	yamlTree, err := narrative.LoadYAML(r, strings.NewReader(testYAML), testGuards())
+	require.NoError(t, err)
+	for name, tree := range map[string]*narrative.Tree[player]{"json": jsonTree, "yaml": yamlTree} {
+		t.Run(name, func(t *testing.T) {
+			t.Run("guard not satisfied", func(t *testing.T) {
+				for range 100 {
+					path := tree.Walk(player{})
+					assert.Equal(t, "cave>stumble", pathIDs(path))
+					assert.Equal(t, "You enter a dark cave.", path[0].Text)
+				}
+			})
+			t.Run("guard satisfied", func(t *testing.T) {
+				const iterations = 100_000
+				counts := make(map[string]int)
+				for range iterations {
+					counts[pathIDs(tree.Walk(player{torch: true}))] += 1
+				}
+				assert.Len(t, counts, 2)
+				assert.InDeltaf(t, 0.75, float64(counts["cave>torch>treasure"])/iterations, 0.05, "%v", counts)
+			})
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package ngram generates text from an n-gram model, where the next token
+// after every prefix is selected from a weighted table built with Vose's
+// alias method.
+package ngram
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nikole-dunixi/weightedrand"
+)
+
+// token identifiers reserved for the boundaries of a sequence.
+const (
+	beginToken int32 = iota
+	endToken
+)
+
+// Trainer accumulates n-gram counts from a corpus. Tokens are interned into
+// compact identifiers, so that prefixes can be keyed without retaining a
+// copy of every token.
+type Trainer struct {
+	n          int
+	vocabulary map[string]int32
+	tokens     []string
+	counts     map[string]map[int32]int64
+}
+
+// NewTrainer constructs a Trainer for n-grams of size n, meaning the next
+// token is predicted from the n-1 tokens before it.
+//
+// The function panics if n is less than 1.
+func NewTrainer(n int) *Trainer {
+	if n < 1 {
+		panic(fmt.Sprintf("n-gram size must be at least 1, but was %d", n))
+	}
+	return &Trainer{
+		n:          n,
+		vocabulary: make(map[string]int32),
+		tokens:     []string{"", ""},
+		counts:     make(map[string]map[int32]int64),
+	}
+}
+
+// Train ingests a single sequence of tokens, such as a sentence.
+func (trainer *Trainer) Train(tokens []string) {
+	prefix := trainer.beginPrefix()
+	for _, token := range tokens {
+		id := trainer.intern(token)
+		trainer.observe(prefix, id)
+		prefix = shift(prefix, id)
+	}
+	trainer.observe(prefix, endToken)
+}
+
+// TrainText ingests a text, splitting it into lines and every line into
+// whitespace separated tokens. Every non-empty line is a sequence.
+func (trainer *Trainer) TrainText(text string) {
+	for line := range strings.Lines(text) {
+		if tokens := strings.Fields(line); len(tokens) > 0 {
+			trainer.Train(tokens)
+		}
+	}
+}
+
+// Build constructs a Model from the counts accumulated so far. The trainer
+// may continue to be used afterwards, without affecting the model.
+//
+// The function panics if no sequences have been trained.
+func (trainer *Trainer) Build(random weightedrand.RandIntN) *Model {
+	if len(trainer.counts) == 0 {
+		panic("at least one sequence must be trained")
+	}
+	model := &Model{
+		n:      trainer.n,
+		tokens: slices.Clone(trainer.tokens),
+		tables: make(map[string]weightedrand.WeightedRandom[int32], len(trainer.counts)),
+	}
+	for key, counts := range trainer.counts {
+		// Sort by identifier so that the tables, and therefore the generated
+		// sequences, do not depend on map iteration order.
+		items := make([]weightedrand.WeightedItem[int32, int64], 0, len(counts))
+		for _, id := range slices.Sorted(maps.Keys(counts)) {
+			items = append(items, weightedrand.WeightedItem[int32, int64]{
+				Item:   id,
+				Weight: counts[id],
+			})
+		}
+		model.tables[key] = weightedrand.NewAliasVoseMethod(random, items...)
+	}
+	return model
+}
+
+func (trainer *Trainer) intern(token string) int32 {
+	if id, ok := trainer.vocabulary[token]; ok {
+		return id
+	}
+	id := int32(len(trainer.tokens))
+	trainer.vocabulary[token] = id
+	trainer.tokens = append(trainer.tokens, token)
+	return id
+}
+
+func (trainer *Trainer) observe(prefix []int32, id int32) {
+	key := prefixKey(prefix)
+	counts, ok := trainer.counts[key]
+	if !ok {
+		counts = make(map[int32]int64)
+		trainer.counts[key] = counts
+	}
+	counts[id] += 1
+}
+
+func (trainer *Trainer) beginPrefix() []int32 {
+	prefix := make([]int32, trainer.n-1)
+	for i := range prefix {
+		prefix[i] = beginToken
+	}
+	return prefix
+}
+
+// Model generates sequences of tokens from trained n-gram counts. It is
+// immutable, and only uses the random number generator it was built with.
+type Model struct {
+	n      int
+	tokens []string
+	tables map[string]weightedrand.WeightedRandom[int32]
+}
+
+// Generate produces a single sequence of at most maxTokens tokens. The
+// sequence ends early if the model selects the end of a sequence. If
+// maxTokens is not positive, the sequence is empty.
+func (model *Model) Generate(maxTokens int) []string {
+	result := make([]string, 0, max(0, min(maxTokens, 64)))
+	prefix := make([]int32, model.n-1)
+	for range maxTokens {
+		table, ok := model.tables[prefixKey(prefix)]
+		if !ok {
+			break
+		}
+		id := table.Next()
+		if id == endToken {
+			break
+		}
+		result = append(result, model.tokens[id])
+		prefix = shift(prefix, id)
+	}
+	return result
+}
+
+// GenerateText produces a single sequence like Generate, joined by spaces.
+func (model *Model) GenerateText(maxTokens int) string {
+	return strings.Join(model.Generate(maxTokens), " ")
+}
+
+// Prefixes returns the number of distinct prefixes, and therefore weighted
+// tables, in the model.
+func (model *Model) Prefixes() int {
+	return len(model.tables)
+}
+
+// prefixKey encodes the prefix as a compact string, suitable for a map key.
+func prefixKey(prefix []int32) string {
+	buffer := make([]byte, 4*len(prefix))
+	for i, id := range prefix {
+		binary.LittleEndian.PutUint32(buffer[4*i:], uint32(id))
+	}
+	return string(buffer)
+}
+
+// shift drops the oldest token from the prefix and appends the newest one,
+// reusing the underlying array.
+func shift(prefix []int32, id int32) []int32 {
+	if len(prefix) == 0 {
+		return prefix
+	}
+	copy(prefix, prefix[1:])
+	prefix[len(prefix)-1] = id
+	return prefix
+}
@@ -0,0 +1,73 @@
+package ngram_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand/ngram"
+	"github.com/stretchr/testify/assert"
+)
+
+const corpus = `the cat sat on the mat
+the dog sat on the log
+the cat chased the dog
+`
+
+func TestModel(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			ngram.NewTrainer(0)
+		})
+		assert.Panics(t, func() {
+			ngram.NewTrainer(2).Build(nil)
+		})
+	})
+	t.Run("generates trained bigrams", func(t *testing.T) {
+		trainer := ngram.NewTrainer(2)
+		trainer.TrainText(corpus)
+		bigrams := make(map[string]bool)
+		for line := range strings.Lines(corpus) {
+			tokens := strings.Fields(line)
+			for i := 1; i < len(tokens); i++ {
+				bigrams[tokens[i-1]+" "+tokens[i]] = true
+			}
+		}
+		model := trainer.Build(rand.New(rand.NewSource(time.Now().Unix())))
+		for range 100 {
+			tokens := model.Generate(50)
+			assert.NotEmpty(t, tokens)
+			assert.Equal(t, "the", tokens[0])
+			for i := 1; i < len(tokens); i++ {
+				assert.Truef(t, bigrams[tokens[i-1]+" "+tokens[i]], "%q was never trained", tokens[i-1]+" "+tokens[i])
+			}
+		}
+	})
+	t.Run("reproduces a single sequence", func(t *testing.T) {
+		trainer := ngram.NewTrainer(3)
+		trainer.TrainText("to be or not")
+		model := trainer.Build(rand.New(rand.NewSource(1)))
+		assert.Equal(t, "to be or not", model.GenerateText(100))
+		assert.Equal(t, 5, model.Prefixes())
+	})
+	t.Run("max tokens", func(t *testing.T) {
+		trainer := ngram.NewTrainer(1)
+		trainer.TrainText(corpus)
+		model := trainer.Build(rand.New(rand.NewSource(1)))
+		assert.LessOrEqual(t, len(model.Generate(3)), 3)
+		assert.Empty(t, model.Generate(0))
+		assert.Empty(t, model.Generate(-1))
+	})
+}
+
+func BenchmarkBuild(b *testing.B) {
+	trainer := ngram.NewTrainer(3)
+	for range 100 {
+		trainer.TrainText(corpus)
+	}
+	r := rand.New(rand.NewSource(1))
+	for b.Loop() {
+		_ = trainer.Build(r)
+	}
+}
@@ -0,0 +1,162 @@
+package weightedrand
+
+import "fmt"
+
+// OneOf2 holds a value of exactly one of the types A or B. It allows items of
+// different types to be weighted against each other in a single table, and
+// the selection to be handled safely by type rather than through any.
+//
+// The zero value holds the zero value of A.
+type OneOf2[A any, B any] struct {
+	index  int
+	first  A
+	second B
+}
+
+// OneOf2First returns a OneOf2 holding a value of type A.
+func OneOf2First[A any, B any](value A) OneOf2[A, B] {
+	return OneOf2[A, B]{index: 0, first: value}
+}
+
+// OneOf2Second returns a OneOf2 holding a value of type B.
+func OneOf2Second[A any, B any](value B) OneOf2[A, B] {
+	return OneOf2[A, B]{index: 1, second: value}
+}
+
+// Index returns 0 if the value is of type A, or 1 if it is of type B.
+func (oneOf OneOf2[A, B]) Index() int {
+	return oneOf.index
+}
+
+// First returns the value if it is of type A.
+func (oneOf OneOf2[A, B]) First() (A, bool) {
+	return oneOf.first, oneOf.index == 0
+}
+
+// Second returns the value if it is of type B.
+func (oneOf OneOf2[A, B]) Second() (B, bool) {
+	return oneOf.second, oneOf.index == 1
+}
+
+// Switch calls the function matching the type of the value.
+func (oneOf OneOf2[A, B]) Switch(onFirst func(A), onSecond func(B)) {
+	if oneOf.index == 0 {
+		onFirst(oneOf.first)
+	} else {
+		onSecond(oneOf.second)
+	}
+}
+
+func (oneOf OneOf2[A, B]) String() string {
+	if oneOf.index == 0 {
+		return fmt.Sprintf("%v", oneOf.first)
+	}
+	return fmt.Sprintf("%v", oneOf.second)
+}
+
+// Match2 returns the result of the function matching the type of the value,
+// which requires every type to be handled.
+func Match2[A any, B any, TResult any](oneOf OneOf2[A, B], onFirst func(A) TResult, onSecond func(B) TResult) TResult {
+	if oneOf.index == 0 {
+		return onFirst(oneOf.first)
+	}
+	return onSecond(oneOf.second)
+}
+
+// OneOf3 holds a value of exactly one of the types A, B or C. It allows items
+// of different types to be weighted against each other in a single table,
+// and the selection to be handled safely by type rather than through any.
+//
+// The zero value holds the zero value of A.
+//
+// Example usage:
+//
+//	type Response = OneOf3[Success, Failure, Timeout]
+//	wr := NewAliasVoseMethod(randSource,
+//		WeightedItem[Response, int]{Item: OneOf3First[Success, Failure, Timeout](Success{Body: "ok"}), Weight: 90},
+//		WeightedItem[Response, int]{Item: OneOf3Second[Success, Failure, Timeout](Failure{Status: 500}), Weight: 9},
+//		WeightedItem[Response, int]{Item: OneOf3Third[Success, Failure, Timeout](Timeout{}), Weight: 1},
+//	)
+//	wr.Next().Switch(
+//		func(s Success) { respond(s) },
+//		func(f Failure) { fail(f) },
+//		func(Timeout) { hang() },
+//	)
+type OneOf3[A any, B any, C any] struct {
+	index  int
+	first  A
+	second B
+	third  C
+}
+
+// OneOf3First returns a OneOf3 holding a value of type A.
+func OneOf3First[A any, B any, C any](value A) OneOf3[A, B, C] {
+	return OneOf3[A, B, C]{index: 0, first: value}
+}
+
+// OneOf3Second returns a OneOf3 holding a value of type B.
+func OneOf3Second[A any, B any, C any](value B) OneOf3[A, B, C] {
+	return OneOf3[A, B, C]{index: 1, second: value}
+}
+
+// OneOf3Third returns a OneOf3 holding a value of type C.
+func OneOf3Third[A any, B any, C any](value C) OneOf3[A, B, C] {
+	return OneOf3[A, B, C]{index: 2, third: value}
+}
+
+// Index returns 0 if the value is of type A, 1 if it is of type B, or 2 if it
+// is of type C.
+func (oneOf OneOf3[A, B, C]) Index() int {
+	return oneOf.index
+}
+
+// First returns the value if it is of type A.
+func (oneOf OneOf3[A, B, C]) First() (A, bool) {
+	return oneOf.first, oneOf.index == 0
+}
+
+// Second returns the value if it is of type B.
+func (oneOf OneOf3[A, B, C]) Second() (B, bool) {
+	return oneOf.second, oneOf.index == 1
+}
+
+// Third returns the value if it is of type C.
+func (oneOf OneOf3[A, B, C]) Third() (C, bool) {
+	return oneOf.third, oneOf.index == 2
+}
+
+// Switch calls the function matching the type of the value.
+func (oneOf OneOf3[A, B, C]) Switch(onFirst func(A), onSecond func(B), onThird func(C)) {
+	switch oneOf.index {
+	case 0:
+		onFirst(oneOf.first)
+	case 1:
+		onSecond(oneOf.second)
+	default:
+		onThird(oneOf.third)
+	}
+}
+
+func (oneOf OneOf3[A, B, C]) String() string {
+	switch oneOf.index {
+	case 0:
+		return fmt.Sprintf("%v", oneOf.first)
+	case 1:
+		return fmt.Sprintf("%v", oneOf.second)
+	default:
+		return fmt.Sprintf("%v", oneOf.third)
+	}
+}
+
+// Match3 returns the result of the function matching the type of the value,
+// which requires every type to be handled.
+func Match3[A any, B any, C any, TResult any](oneOf OneOf3[A, B, C], onFirst func(A) TResult, onSecond func(B) TResult, onThird func(C) TResult) TResult {
+	switch oneOf.index {
+	case 0:
+		return onFirst(oneOf.first)
+	case 1:
+		return onSecond(oneOf.second)
+	default:
+		return onThird(oneOf.third)
+	}
+}
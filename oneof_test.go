@@ -0,0 +1,68 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixtureTimeout struct{}
+
+func TestOneOf2(t *testing.T) {
+	number := OneOf2First[int, string](42)
+	text := OneOf2Second[int, string]("forty-two")
+	value, ok := number.First()
+	assert.True(t, ok)
+	assert.Equal(t, 42, value)
+	_, ok = number.Second()
+	assert.False(t, ok)
+	assert.Equal(t, 1, text.Index())
+	assert.Equal(t, "forty-two", text.String())
+	assert.Equal(t, "42", Match2(number, strconv.Itoa, func(s string) string { return s }))
+	var zero OneOf2[int, string]
+	assert.Equal(t, 0, zero.Index())
+}
+
+func TestOneOf3(t *testing.T) {
+	type response = OneOf3[int, error, fixtureTimeout]
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	wr := NewAliasVoseMethod(r,
+		WeightedItem[response, int]{Item: OneOf3First[int, error, fixtureTimeout](200), Weight: 2},
+		WeightedItem[response, int]{Item: OneOf3Second[int, error, fixtureTimeout](assert.AnError), Weight: 1},
+		WeightedItem[response, int]{Item: OneOf3Third[int, error, fixtureTimeout](fixtureTimeout{}), Weight: 1},
+	)
+	counts := make(map[string]int)
+	for range 100_000 {
+		wr.Next().Switch(
+			func(status int) {
+				assert.Equal(t, 200, status)
+				counts["status"] += 1
+			},
+			func(err error) {
+				assert.ErrorIs(t, err, assert.AnError)
+				counts["error"] += 1
+			},
+			func(fixtureTimeout) {
+				counts["timeout"] += 1
+			},
+		)
+	}
+	assert.InDeltaf(t, 0.5, float64(counts["status"])/100_000, tolerance, "%v", counts)
+	assert.InDeltaf(t, 0.25, float64(counts["timeout"])/100_000, tolerance, "%v", counts)
+
+	timeout := OneOf3Third[int, error, fixtureTimeout](fixtureTimeout{})
+	_, ok := timeout.Third()
+	assert.True(t, ok)
+	_, ok = timeout.First()
+	assert.False(t, ok)
+	assert.Equal(t, 2, timeout.Index())
+	assert.Equal(t, "timeout", Match3(timeout,
+		func(int) string { return "status" },
+		func(error) string { return "error" },
+		func(fixtureTimeout) string { return "timeout" },
+	))
+}
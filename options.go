@@ -0,0 +1,200 @@
+package weightedrand
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Option configures how NewAliasVoseMethodWithOptions constructs its
+// WeightedRandom instance, regardless of the type of its items. An Option is
+// accepted wherever an ItemOption is.
+type Option = func(*options)
+
+// ItemOption configures how NewAliasVoseMethodWithOptions constructs its
+// WeightedRandom instance for items of type TItem, such as the hook of
+// WithOnSelect. Unlike an Option, it is only accepted by constructors for
+// items of the same type, so that a mismatched item type is caught by the
+// compiler.
+type ItemOption[TItem any] func(*options)
+
+type options struct {
+	integerEngine bool
+	emptyBehavior emptyBehavior
+	// fallback and onSelect hold the settings of WithEmptyFallback and
+	// WithOnSelect, whose item type is only known at construction, but is
+	// ensured by ItemOption.
+	fallback      any
+	onSelect      any
+	onBuild       func(BuildInfo)
+	buildProgress func(done, total int)
+	// tieBreak is the policy ordering items of equal weight, and
+	// tieBreakKey holds the key function of WithTieBreakKey, whose item type
+	// is only known at construction, but is ensured by ItemOption.
+	tieBreak    TieBreak
+	tieBreakKey any
+	// exactThresholds reports whether the weights are scaled into whole
+	// numbers for the integer engine.
+	exactThresholds bool
+	// categoryBalance holds the categoryBalance of WithCategoryBalance,
+	// whose item type is only known at construction, but is ensured by
+	// ItemOption.
+	categoryBalance any
+	// bounded reports whether the probabilities are clamped within
+	// [minProbability, maxProbability].
+	bounded        bool
+	minProbability decimal.Decimal
+	maxProbability decimal.Decimal
+}
+
+func newOptions[TItem any](opts []ItemOption[TItem]) options {
+	result := options{
+		maxProbability: One,
+	}
+	for _, opt := range opts {
+		opt(&result)
+	}
+	return result
+}
+
+// WithIntegerEngine builds the alias table with int64 arithmetic instead of
+// decimal.Decimal, keeping each weight as a numerator over the total weight.
+// Selections are exact, and both construction and selection avoid the cost
+// of decimal arithmetic. Decimal weights are accepted as long as they are
+// whole numbers that fit in a uint64. Total weights beyond the range of
+// int64 are held in 128-bit integers instead, which remain exact but make
+// selections slower.
+func WithIntegerEngine() Option {
+	return func(o *options) {
+		o.integerEngine = true
+	}
+}
+
+// NewAliasVoseMethodWithOptions constructs a new WeightedRandom instance
+// using the Alias Method (Vose's algorithm), like NewAliasVoseMethod, but
+// with its construction configured by the options.
+//
+// The function panics if no items are provided, weights are negative, the
+// weights overflow the integer engine of WithIntegerEngine or
+// WithExactThresholds, the bounds of WithMinProbability and
+// WithMaxProbability cannot be satisfied by the number of items, or an item
+// has no target in WithCategoryBalance. Errors are panicked as error
+// values, so that they can be matched with errors.Is once recovered. Use
+// NewAliasVoseMethodWithOptionsE to receive an error instead.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - items:  The WeightedItem values, each containing an item and its associated weight.
+//   - opts:   A variadic list of Option and ItemOption values.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, []WeightedItem[string, int]{{Item: "A", Weight: 2}, {Item: "B", Weight: 3}}, WithIntegerEngine())
+func NewAliasVoseMethodWithOptions[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], opts ...ItemOption[TItem]) WeightedRandom[TItem] {
+	return newAliasVoseMethodWithOptions(random, items, newOptions(opts))
+}
+
+// NewAliasVoseMethodWithOptionsE is like NewAliasVoseMethodWithOptions, but
+// returns an error instead of panicking when the table cannot be built from
+// the items, such as ErrNoItems, ErrNegativeWeight, or ErrOverflow when the
+// weights overflow the integer engine of WithIntegerEngine or
+// WithExactThresholds.
+//
+// Example usage:
+//
+//	wr, err := NewAliasVoseMethodWithOptionsE(randSource, items, WithExactThresholds())
+//	if errors.Is(err, ErrOverflow) {
+//		wr, err = NewAliasVoseMethodE(randSource, items...)
+//	}
+func NewAliasVoseMethodWithOptionsE[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], opts ...ItemOption[TItem]) (WeightedRandom[TItem], error) {
+	return buildAliasVoseMethodWithOptions(random, items, newOptions(opts))
+}
+
+// newAliasVoseMethodWithOptions panics with the error of
+// buildAliasVoseMethodWithOptions.
+func newAliasVoseMethodWithOptions[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], o options) WeightedRandom[TItem] {
+	aliasMethod, err := buildAliasVoseMethodWithOptions(random, items, o)
+	if err != nil {
+		panic(err)
+	}
+	return aliasMethod
+}
+
+func buildAliasVoseMethodWithOptions[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], o options) (WeightedRandom[TItem], error) {
+	if o.tieBreak != 0 {
+		arranged, _, err := tieBrokenItems(random, items, o)
+		if err != nil {
+			return nil, err
+		}
+		// Adjustments keep the order of the items, so the arranged items are
+		// only kept in order from now on.
+		o.tieBreak, o.tieBreakKey = TieBreakInputOrder, nil
+		items = arranged
+	}
+	if adjusted, ok, err := adjustedItems(items, o); err != nil {
+		return nil, err
+	} else if ok {
+		o.bounded = false
+		o.categoryBalance = nil
+		if o.integerEngine || o.exactThresholds {
+			// The integer engine requires whole weights, so the probabilities
+			// are kept to 18 decimal places as numerators.
+			for i := range adjusted {
+				adjusted[i].Weight = adjusted[i].Weight.Shift(probabilityScale).Round(0)
+			}
+		}
+		return buildAliasVoseMethodWithOptions(random, adjusted, o)
+	}
+	if o.exactThresholds {
+		scaled, scale, err := exactItems(items)
+		if err != nil {
+			return nil, err
+		}
+		o.exactThresholds, o.integerEngine = false, true
+		if onBuild := o.onBuild; onBuild != nil {
+			o.onBuild = func(info BuildInfo) {
+				info.TotalWeight = info.TotalWeight.DivRound(decimal.NewFromBigInt(scale, 0), probabilityScale+int32(len(scale.String())))
+				info.Engine = "exact"
+				onBuild(info)
+			}
+		}
+		return buildAliasVoseMethodWithOptions(random, scaled, o)
+	}
+	start := time.Now()
+	build := buildContext{
+		progress: o.buildProgress,
+		stable:   o.tieBreak != 0,
+	}
+	var aliasMethod WeightedRandom[TItem]
+	var err error
+	if onSelect := onSelectHook[TItem](o); onSelect != nil {
+		aliasMethod, err = buildHookedAliasMethod(build, random, items, o.integerEngine, onSelect)
+	} else if o.integerEngine {
+		aliasMethod, err = buildIntegerAliasMethod(build, random, items)
+	} else {
+		aliasMethod, err = buildVoseAliasMethodContext(build, random, items)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.onBuild != nil {
+		info := BuildInfo{
+			Items:       len(items),
+			TotalWeight: decimal.Zero,
+			Engine:      "decimal",
+			Duration:    time.Since(start),
+		}
+		for _, item := range items {
+			info.TotalWeight = info.TotalWeight.Add(effectiveWeight(item.Weight))
+		}
+		if o.integerEngine {
+			info.Engine = "integer"
+		}
+		o.onBuild(info)
+	}
+	return aliasMethod, nil
+}
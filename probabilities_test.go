@@ -0,0 +1,41 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromProbabilities(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "at least one item must be provided", func() {
+			NewFromProbabilities[MarbleColor](nil, nil, nil)
+		})
+		assert.PanicsWithValue(t, "2 items were provided with 1 probabilities", func() {
+			NewFromProbabilities(nil, []MarbleColor{Red, Blue}, []float64{1})
+		})
+		assert.PanicsWithValue(t, "probability must be a finite non-negative value, but was -0.5", func() {
+			NewFromProbabilities(nil, []MarbleColor{Red, Blue}, []float64{1.5, -0.5})
+		})
+		assert.PanicsWithValue(t, "probabilities must sum to 1, but summed to 0.5", func() {
+			NewFromProbabilities(nil, []MarbleColor{Red, Blue}, []float64{0.25, 0.25})
+		})
+	})
+	t.Run("items with probabilities", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromProbabilities(r, []MarbleColor{Red, Green, Blue}, []float64{0.25, 0, 0.75})
+		counts := Counts(wr, 100_000)
+		assert.Zero(t, counts[Green])
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/100_000, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("rounding errors", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromProbabilities(r, []MarbleColor{Red, Blue}, []float64{0.4999999999, 0.4999999999})
+		counts := Counts(wr, 100_000)
+		assert.InDeltaf(t, 0.5, float64(counts[Red])/100_000, tolerance, "%v", counts)
+	})
+}
@@ -0,0 +1,146 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Profile is a named weight set of Profiles, which overlays the weights of
+// the profile it inherits from.
+type Profile[TItem comparable, TWeight Weight] struct {
+	// Parent is the name of the profile inherited from. If it is empty, the
+	// base weights are inherited.
+	Parent string
+	// Weights replace the weights of the inherited items, and add the items
+	// that are not inherited.
+	Weights []WeightedItem[TItem, TWeight]
+	// Remove are the inherited items that are not selected in the profile.
+	Remove []TItem
+}
+
+// Profiles holds multiple named weight sets for the same items, such as one
+// for each environment or region, as overlays of base weights or of each
+// other. For example, a "prod-eu" profile may inherit from "prod", which
+// inherits from the base weights, and only replace the weight of a single
+// item.
+//
+// Profiles is safe for concurrent use.
+type Profiles[TItem comparable, TWeight Weight] struct {
+	random   RandIntN
+	mutex    sync.RWMutex
+	base     []WeightedItem[TItem, TWeight]
+	profiles map[string]Profile[TItem, TWeight]
+}
+
+// NewProfiles constructs a new Profiles instance over the base weights,
+// which has no profiles until they are configured with WithProfile.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation by the selected choosers.
+//   - base:   A variadic list of WeightedItem values, each containing an item and its base weight.
+//
+// Example usage:
+//
+//	profiles := NewProfiles(randSource,
+//		WeightedItem[string, int]{Item: "v1", Weight: 9},
+//		WeightedItem[string, int]{Item: "v2", Weight: 1},
+//	).
+//		WithProfile("staging", Profile[string, int]{Weights: []WeightedItem[string, int]{{Item: "v2", Weight: 9}}}).
+//		WithProfile("staging-eu", Profile[string, int]{Parent: "staging", Remove: []string{"v1"}})
+//	wr, err := profiles.Select(os.Getenv("ENVIRONMENT"))
+func NewProfiles[TItem comparable, TWeight Weight](random RandIntN, base ...WeightedItem[TItem, TWeight]) *Profiles[TItem, TWeight] {
+	return &Profiles[TItem, TWeight]{
+		random:   random,
+		base:     slices.Clone(base),
+		profiles: make(map[string]Profile[TItem, TWeight]),
+	}
+}
+
+// WithProfile defines the profile with the name, replacing any profile of
+// the same name. Its parent does not need to be defined yet. It returns the
+// Profiles to allow chaining.
+//
+// Panics:
+//   - If the name is empty, which refers to the base weights.
+func (profiles *Profiles[TItem, TWeight]) WithProfile(name string, profile Profile[TItem, TWeight]) *Profiles[TItem, TWeight] {
+	if name == "" {
+		panic("profile name must not be empty")
+	}
+	profile.Weights = slices.Clone(profile.Weights)
+	profile.Remove = slices.Clone(profile.Remove)
+	profiles.mutex.Lock()
+	defer profiles.mutex.Unlock()
+	profiles.profiles[name] = profile
+	return profiles
+}
+
+// Weights returns the effective weights of the profile, after overlaying it
+// on every profile it inherits from. The empty name returns the base
+// weights. Inherited items keep their order, and added items follow them.
+//
+// Returns an error wrapping ErrUnknownProfile if the profile, or a profile
+// it inherits from, is not defined, or an *ErrProfileCycle naming the
+// profiles if they inherit from each other in a cycle.
+func (profiles *Profiles[TItem, TWeight]) Weights(name string) ([]WeightedItem[TItem, TWeight], error) {
+	profiles.mutex.RLock()
+	defer profiles.mutex.RUnlock()
+	// Collect the overlays from the profile up to the base weights.
+	var lineage []Profile[TItem, TWeight]
+	var names []string
+	for current := name; current != ""; {
+		if start := slices.Index(names, current); start >= 0 {
+			return nil, &ErrProfileCycle{
+				Cycle: append(slices.Clone(names[start:]), current),
+			}
+		}
+		names = append(names, current)
+		profile, ok := profiles.profiles[current]
+		if !ok {
+			return nil, fmt.Errorf("profile %q: %w", current, ErrUnknownProfile)
+		}
+		lineage = append(lineage, profile)
+		current = profile.Parent
+	}
+	weights := slices.Clone(profiles.base)
+	for _, profile := range slices.Backward(lineage) {
+		weights = slices.DeleteFunc(weights, func(item WeightedItem[TItem, TWeight]) bool {
+			return slices.Contains(profile.Remove, item.Item)
+		})
+		for _, override := range profile.Weights {
+			replaced := false
+			for i := range weights {
+				if weights[i].Item == override.Item {
+					weights[i].Weight = override.Weight
+					replaced = true
+				}
+			}
+			if !replaced {
+				weights = append(weights, override)
+			}
+		}
+	}
+	return weights, nil
+}
+
+// Select returns a new chooser over the effective weights of the profile,
+// as returned by Weights. The chooser is built on every call, so it should
+// be kept rather than selected again for every selection.
+//
+// Returns an error if the effective weights cannot be resolved, no items
+// remain in the profile, or weights are negative.
+func (profiles *Profiles[TItem, TWeight]) Select(profile string) (WeightedRandom[TItem], error) {
+	weights, err := profiles.Weights(profile)
+	if err != nil {
+		return nil, err
+	}
+	aliasMethod, err := buildVoseAliasMethod(profiles.random, weights)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile, err)
+	}
+	return aliasMethod, nil
+}
@@ -0,0 +1,85 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	profiles := NewProfiles(random,
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 9},
+		WeightedItem[MarbleColor, int]{Item: Green, Weight: 1},
+	).
+		WithProfile("staging-eu", Profile[MarbleColor, int]{
+			Parent: "staging",
+			Remove: []MarbleColor{Red},
+		}).
+		WithProfile("staging", Profile[MarbleColor, int]{
+			Weights: []WeightedItem[MarbleColor, int]{
+				{Item: Green, Weight: 9},
+				{Item: Blue, Weight: 2},
+			},
+		}).
+		WithProfile("loop-a", Profile[MarbleColor, int]{Parent: "loop-b"}).
+		WithProfile("loop-b", Profile[MarbleColor, int]{Parent: "loop-a"}).
+		WithProfile("into-loop", Profile[MarbleColor, int]{Parent: "loop-b"}).
+		WithProfile("self", Profile[MarbleColor, int]{Parent: "self"}).
+		WithProfile("orphan", Profile[MarbleColor, int]{Parent: "missing"}).
+		WithProfile("empty", Profile[MarbleColor, int]{Remove: []MarbleColor{Red, Green}})
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			profiles.WithProfile("", Profile[MarbleColor, int]{})
+		})
+	})
+	t.Run("weights", func(t *testing.T) {
+		weights, err := profiles.Weights("")
+		require.NoError(t, err)
+		assert.Equal(t, []WeightedItem[MarbleColor, int]{{Item: Red, Weight: 9}, {Item: Green, Weight: 1}}, weights)
+		weights, err = profiles.Weights("staging")
+		require.NoError(t, err)
+		assert.Equal(t, []WeightedItem[MarbleColor, int]{{Item: Red, Weight: 9}, {Item: Green, Weight: 9}, {Item: Blue, Weight: 2}}, weights)
+		weights, err = profiles.Weights("staging-eu")
+		require.NoError(t, err)
+		assert.Equal(t, []WeightedItem[MarbleColor, int]{{Item: Green, Weight: 9}, {Item: Blue, Weight: 2}}, weights)
+	})
+	t.Run("select", func(t *testing.T) {
+		wr, err := profiles.Select("staging-eu")
+		require.NoError(t, err)
+		counts := MarbleColorCounts{}
+		for range 11_000 {
+			counts[wr.Next()]++
+		}
+		assert.Zero(t, counts[Red])
+		assert.InDelta(t, 9_000, counts[Green], 300)
+		assert.InDelta(t, 2_000, counts[Blue], 300)
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := profiles.Select("unknown")
+		assert.ErrorIs(t, err, ErrUnknownProfile)
+		_, err = profiles.Select("orphan")
+		assert.ErrorIs(t, err, ErrUnknownProfile)
+		_, err = profiles.Select("empty")
+		assert.ErrorIs(t, err, ErrNoItems)
+	})
+	t.Run("cycles", func(t *testing.T) {
+		for name, expected := range map[string][]string{
+			"loop-a":    {"loop-a", "loop-b", "loop-a"},
+			"into-loop": {"loop-b", "loop-a", "loop-b"},
+			"self":      {"self", "self"},
+		} {
+			_, err := profiles.Select(name)
+			var cycleErr *ErrProfileCycle
+			if assert.ErrorAs(t, err, &cycleErr, name) {
+				assert.Equal(t, expected, cycleErr.Cycle)
+			}
+			assert.NotErrorIs(t, err, ErrUnknownProfile)
+		}
+		_, err := profiles.Weights("loop-a")
+		assert.EqualError(t, err, "profiles inherit from each other in a cycle: loop-a -> loop-b -> loop-a")
+	})
+}
@@ -0,0 +1,50 @@
+package weightedrand
+
+import "context"
+
+// WithBuildProgress reports the progress of building the alias table of
+// NewAliasVoseMethodWithOptions or BuildTableWithOptions to the callback, so
+// that services building tables of millions of items can expose it in health
+// endpoints and logs. The callback is called with no buckets done,
+// periodically while buckets are filled, and once more when all of them are.
+// It is called on the goroutine building the table, so it should return
+// quickly.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, catalog, WithBuildProgress(func(done, total int) {
+//		progress.Store(float64(done) / float64(total))
+//	}))
+func WithBuildProgress(progress func(done, total int)) Option {
+	return func(o *options) {
+		o.buildProgress = progress
+	}
+}
+
+// buildContext is threaded through the construction of alias tables, which
+// reports its progress to it and checks it for cancellation periodically.
+// The zero value is never cancelled, and reports progress to no one.
+type buildContext struct {
+	ctx      context.Context
+	progress func(done, total int)
+	// stable reports whether items of equal weight must keep the order they
+	// are provided in, as arranged by the tie-breaking policy.
+	stable bool
+}
+
+// report reports that done out of total buckets are filled.
+func (build buildContext) report(done, total int) {
+	if build.progress != nil {
+		build.progress(done, total)
+	}
+}
+
+// checkpoint reports the progress like report, and returns the error of the
+// context if it is done.
+func (build buildContext) checkpoint(done, total int) error {
+	build.report(done, total)
+	if build.ctx == nil {
+		return nil
+	}
+	return build.ctx.Err()
+}
@@ -0,0 +1,48 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildProgress(t *testing.T) {
+	const count = 10_000
+	items := make([]WeightedItem[int, int], count)
+	for i := range items {
+		items[i] = WeightedItem[int, int]{Item: i, Weight: i%7 + 1}
+	}
+	assertProgress := func(t *testing.T, reports [][2]int) {
+		require.Greater(t, len(reports), 2)
+		assert.Equal(t, [2]int{0, count}, reports[0])
+		assert.Equal(t, [2]int{count, count}, reports[len(reports)-1])
+		for i := 1; i < len(reports); i++ {
+			assert.LessOrEqual(t, reports[i-1][0], reports[i][0])
+			assert.Equal(t, count, reports[i][1])
+		}
+	}
+	for name, opts := range map[string][]ItemOption[int]{
+		"decimal": nil,
+		"integer": {WithIntegerEngine()},
+		"hooked":  {WithOnSelect(func(int, decimal.Decimal) {})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var reports [][2]int
+			NewAliasVoseMethodWithOptions(nil, items, append(opts, WithBuildProgress(func(done, total int) {
+				reports = append(reports, [2]int{done, total})
+			}))...)
+			assertProgress(t, reports)
+		})
+	}
+	t.Run("table", func(t *testing.T) {
+		var reports [][2]int
+		_, err := BuildTableWithOptions(items, WithBuildProgress(func(done, total int) {
+			reports = append(reports, [2]int{done, total})
+		}))
+		require.NoError(t, err)
+		assertProgress(t, reports)
+	})
+}
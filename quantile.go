@@ -0,0 +1,129 @@
+package weightedrand
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Number is a type constraint for the numeric item types whose weighted
+// distribution can be queried by value.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// DiscreteDistribution is the distribution of numeric items described by
+// their weights, which can be queried by value rather than only sampled.
+type DiscreteDistribution[TItem Number] struct {
+	values     []TItem
+	cumulative []decimal.Decimal
+	total      decimal.Decimal
+}
+
+// NewDiscreteDistribution constructs the distribution of the items, where
+// the probability of each item is its weight relative to the total weight.
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1. Duplicated items are combined, with their
+// weights summed.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	d := NewDiscreteDistribution(WeightedItem[int, int]{Item: 10, Weight: 3}, WeightedItem[int, int]{Item: 20, Weight: 1})
+//	d.CDF(10) // 0.75
+//	d.Quantile(decimal.RequireFromString("0.9")) // 20
+func NewDiscreteDistribution[TItem Number, TWeight Weight](items ...WeightedItem[TItem, TWeight]) *DiscreteDistribution[TItem] {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	}
+	decimalItems := make([]weightedItem[TItem], 0, len(items))
+	for _, item := range items {
+		decimalItems = append(decimalItems, weightedItem[TItem]{
+			Item:   item.Item,
+			Weight: effectiveWeight(item.Weight),
+		})
+	}
+	return newDiscreteDistribution(decimalItems)
+}
+
+// DistributionOf returns the distribution a WeightedRandom instance selects
+// from, such as the result of Convolve.
+//
+// The function panics if the distribution cannot be determined, because the
+// instance was not constructed by this package.
+func DistributionOf[TItem Number](wr WeightedRandom[TItem]) *DiscreteDistribution[TItem] {
+	return newDiscreteDistribution(distributionOf(wr).weights())
+}
+
+func newDiscreteDistribution[TItem Number](items []weightedItem[TItem]) *DiscreteDistribution[TItem] {
+	merged := make(map[TItem]decimal.Decimal, len(items))
+	for _, item := range items {
+		merged[item.Item] = merged[item.Item].Add(item.Weight)
+	}
+	values := slices.SortedFunc(maps.Keys(merged), cmp.Compare[TItem])
+	weights := make([]decimal.Decimal, len(values))
+	for i, value := range values {
+		weights[i] = merged[value]
+	}
+	cumulative := cumulativeWeights(weights)
+	return &DiscreteDistribution[TItem]{
+		values:     values,
+		cumulative: cumulative,
+		total:      cumulative[len(cumulative)-1],
+	}
+}
+
+// Values returns the distinct items of the distribution, in increasing
+// order.
+func (distribution *DiscreteDistribution[TItem]) Values() []TItem {
+	return slices.Clone(distribution.values)
+}
+
+// Probability returns the probability of the item.
+func (distribution *DiscreteDistribution[TItem]) Probability(x TItem) decimal.Decimal {
+	index, ok := slices.BinarySearch(distribution.values, x)
+	if !ok {
+		return decimal.Zero
+	}
+	weight := distribution.cumulative[index]
+	if index > 0 {
+		weight = weight.Sub(distribution.cumulative[index-1])
+	}
+	return weight.Div(distribution.total)
+}
+
+// CDF returns the probability of selecting an item less than or equal to x.
+func (distribution *DiscreteDistribution[TItem]) CDF(x TItem) decimal.Decimal {
+	count := sort.Search(len(distribution.values), func(i int) bool {
+		return distribution.values[i] > x
+	})
+	if count == 0 {
+		return decimal.Zero
+	}
+	return distribution.cumulative[count-1].Div(distribution.total)
+}
+
+// Quantile returns the smallest item whose CDF is at least p, so that
+// Quantile(0.5) is the weighted median.
+//
+// Panics:
+//   - If p is not within [0, 1].
+func (distribution *DiscreteDistribution[TItem]) Quantile(p decimal.Decimal) TItem {
+	if p.IsNegative() || p.GreaterThan(One) {
+		panic(fmt.Sprintf("quantile must be within [0, 1], but was %s", p.String()))
+	}
+	// Compare against the cumulative weights rather than probabilities, to
+	// avoid the rounding of the division.
+	target := p.Mul(distribution.total)
+	index := sort.Search(len(distribution.cumulative), func(i int) bool {
+		return distribution.cumulative[i].GreaterThanOrEqual(target)
+	})
+	return distribution.values[min(index, len(distribution.values)-1)]
+}
@@ -0,0 +1,73 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscreteDistribution(t *testing.T) {
+	d := NewDiscreteDistribution(
+		WeightedItem[int, int]{Item: 30, Weight: 1},
+		WeightedItem[int, int]{Item: 10, Weight: 2},
+		WeightedItem[int, int]{Item: 20, Weight: 1},
+		WeightedItem[int, int]{Item: 10, Weight: 1},
+	)
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewDiscreteDistribution[int, int]()
+		})
+		assert.Panics(t, func() {
+			d.Quantile(decimal.NewFromInt(2))
+		})
+		assert.Panics(t, func() {
+			DistributionOf[int](opaqueWeightedRandom{})
+		})
+	})
+	t.Run("values", func(t *testing.T) {
+		assert.Equal(t, []int{10, 20, 30}, d.Values())
+		assert.Equal(t, "0.6", d.Probability(10).String())
+		assert.Equal(t, "0", d.Probability(15).String())
+	})
+	t.Run("cdf", func(t *testing.T) {
+		for x, expected := range map[int]string{
+			5:  "0",
+			10: "0.6",
+			15: "0.6",
+			20: "0.8",
+			30: "1",
+			99: "1",
+		} {
+			assert.Equal(t, expected, d.CDF(x).String(), x)
+		}
+	})
+	t.Run("quantile", func(t *testing.T) {
+		for p, expected := range map[string]int{
+			"0":    10,
+			"0.6":  10,
+			"0.61": 20,
+			"0.8":  20,
+			"0.9":  30,
+			"1":    30,
+		} {
+			assert.Equal(t, expected, d.Quantile(FixtureDecimal(t, p)), p)
+		}
+	})
+	t.Run("floats", func(t *testing.T) {
+		f := NewDiscreteDistribution(
+			WeightedItem[float64, int]{Item: 0.5, Weight: 1},
+			WeightedItem[float64, int]{Item: -1.5, Weight: 3},
+		)
+		assert.Equal(t, "0.75", f.CDF(0).String())
+		assert.Equal(t, 0.5, f.Quantile(FixtureDecimal(t, "0.8")))
+	})
+	t.Run("of a weighted random", func(t *testing.T) {
+		coin := NewAliasVoseMethod(nil, WeightedItem[int, int]{Item: 0, Weight: 1}, WeightedItem[int, int]{Item: 1, Weight: 1})
+		twoCoins := DistributionOf(Convolve(coin, coin))
+		assert.Equal(t, []int{0, 1, 2}, twoCoins.Values())
+		assert.Equal(t, 1, twoCoins.Quantile(FixtureDecimal(t, "0.5")))
+		assert.Equal(t, "0.75", twoCoins.CDF(1).String())
+	})
+}
@@ -0,0 +1,101 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// WeightedRange represents a range of identifiers, [Start, End), with an
+// associated weight. Every identifier within a range is equally likely once
+// the range has been selected.
+type WeightedRange[TWeight Weight] struct {
+	Start  uint64
+	End    uint64
+	Weight TWeight
+}
+
+func (weightedRange WeightedRange[TWeight]) String() string {
+	return fmt.Sprintf(
+		"{weight: %v, range: [%d, %d)}",
+		weightedRange.Weight,
+		weightedRange.Start,
+		weightedRange.End,
+	)
+}
+
+type uint64Range struct {
+	start uint64
+	end   uint64
+}
+
+type rangeRandom struct {
+	random RandIntN
+	ranges WeightedRandom[uint64Range]
+}
+
+// NewRangeSampler constructs a new WeightedRandom instance that selects a
+// range by weight, and then an identifier uniformly within it. This allows
+// sampling from very large identifier spaces, such as user IDs grouped into
+// cohorts, without a WeightedItem per identifier. To sample identifiers
+// uniformly overall, weight each range by its size.
+//
+// The function panics if no ranges are provided, if a range is empty, or if
+// weights are negative.
+//
+// Type Parameters:
+//   - TWeight: The type representing the weight of each range.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - ranges: A variadic list of WeightedRange values.
+//
+// Example usage:
+//
+//	wr := NewRangeSampler(randSource,
+//		WeightedRange[int]{Start: 0, End: 1_000_000, Weight: 1},
+//		WeightedRange[int]{Start: 5_000_000, End: 5_001_000, Weight: 9},
+//	)
+//	userID := wr.Next()
+func NewRangeSampler[TWeight Weight](random RandIntN, ranges ...WeightedRange[TWeight]) WeightedRandom[uint64] {
+	if len(ranges) == 0 {
+		panic("at least one range must be provided")
+	}
+	items := make([]weightedItem[uint64Range], 0, len(ranges))
+	for _, weightedRange := range ranges {
+		if weightedRange.End <= weightedRange.Start {
+			panic(fmt.Sprintf("range must not be empty, but was %s", weightedRange.String()))
+		}
+		items = append(items, weightedItem[uint64Range]{
+			Item: uint64Range{
+				start: weightedRange.Start,
+				end:   weightedRange.End,
+			},
+			Weight: effectiveWeight(weightedRange.Weight),
+		})
+	}
+	return rangeRandom{
+		random: random,
+		ranges: newVoseAliasMethodFromDecimals(random, items),
+	}
+}
+
+func (sampler rangeRandom) Next() uint64 {
+	selected := sampler.ranges.Next()
+	return selected.start + uint64n(sampler.random, selected.end-selected.start)
+}
+
+// uint64n returns a uniformly distributed value within [0, n), including
+// values of n beyond what Int63n supports.
+func uint64n(random RandIntN, n uint64) uint64 {
+	if n <= math.MaxInt64 {
+		return uint64(random.Int63n(int64(n)))
+	}
+	// Assemble 64 uniform bits, and reject values outside of the range. As n
+	// is beyond 2^63, at least half of the values are accepted.
+	for {
+		value := uint64(random.Int63n(1<<62))<<2 | uint64(random.Int63n(4))
+		if value < n {
+			return value
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package weightedrand_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeSampler(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewRangeSampler[int](nil)
+		})
+		assert.Panics(t, func() {
+			NewRangeSampler(nil, WeightedRange[int]{Start: 10, End: 10})
+		})
+		assert.Panics(t, func() {
+			NewRangeSampler(nil, WeightedRange[int]{Start: 0, End: 10, Weight: -1})
+		})
+	})
+	t.Run("within weighted ranges", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewRangeSampler(r,
+			WeightedRange[int]{Start: 0, End: 10, Weight: 1},
+			WeightedRange[int]{Start: 1_000, End: 1_002, Weight: 3},
+			WeightedRange[int]{Start: math.MaxUint64 - 1<<63, End: math.MaxUint64, Weight: 4},
+		)
+		const iterations = 100_000
+		counts := make(map[string]int)
+		values := make(map[uint64]int)
+		for range iterations {
+			switch value := wr.Next(); {
+			case value < 10:
+				counts["low"]++
+				values[value]++
+			case value == 1_000 || value == 1_001:
+				counts["middle"]++
+			case value >= math.MaxUint64-1<<63:
+				counts["high"]++
+			default:
+				assert.Failf(t, "value outside of every range", "%d", value)
+			}
+		}
+		assert.InDeltaf(t, 0.125, float64(counts["low"])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.375, float64(counts["middle"])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.5, float64(counts["high"])/iterations, tolerance, "%v", counts)
+		assert.Len(t, values, 10)
+	})
+}
@@ -0,0 +1,141 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimited is a WeightedRandom where items may be limited to a maximum
+// rate of selections, such as a downstream target that must not exceed its
+// rated throughput regardless of its weight. The rate of an item is enforced
+// by a token bucket: every selection takes a token, and tokens are added at
+// the rate up to a burst of one second's worth. While an item has no token
+// left, it is excluded, and its share of the selections goes to the
+// remaining items in proportion to their weights.
+//
+// RateLimited is safe for concurrent use, given that the random number
+// generator is.
+type RateLimited[TItem comparable] struct {
+	mutex   sync.Mutex
+	clock   Clock
+	table   *adjustableTable[TItem]
+	indices map[TItem][]int
+	buckets map[TItem]*tokenBucket
+}
+
+// tokenBucket holds the tokens of a rate-limited item.
+type tokenBucket struct {
+	rate    float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimited constructs a new RateLimited instance. No item is limited
+// until a rate is configured with WithMaxRate.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - clock:  A Clock implementation used to add tokens as time passes.
+//   - items:  A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Example usage:
+//
+//	r := NewRateLimited(randSource, SystemClock, WeightedItem[string, int]{Item: "gpu-pool", Weight: 8}, WeightedItem[string, int]{Item: "cpu-pool", Weight: 2}).
+//		WithMaxRate("gpu-pool", 50)
+func NewRateLimited[TItem comparable, TWeight Weight](random RandIntN, clock Clock, items ...WeightedItem[TItem, TWeight]) *RateLimited[TItem] {
+	limited := &RateLimited[TItem]{
+		clock:   clock,
+		table:   newAdjustableTable(random, items),
+		indices: make(map[TItem][]int, len(items)),
+		buckets: make(map[TItem]*tokenBucket),
+	}
+	for index, item := range items {
+		limited.indices[item.Item] = append(limited.indices[item.Item], index)
+	}
+	return limited
+}
+
+// WithMaxRate limits the item to the rate of selections per second, with a
+// burst of one second's worth, or of a single selection for rates below 1.
+// The bucket of the item starts full. It returns the RateLimited to allow
+// chaining.
+//
+// Panics:
+//   - If the rate is not a positive finite number.
+func (limited *RateLimited[TItem]) WithMaxRate(item TItem, perSecond float64) *RateLimited[TItem] {
+	if !(perSecond > 0) || math.IsInf(perSecond, 1) {
+		panic(fmt.Sprintf("rate must be a positive finite number, but was %g", perSecond))
+	}
+	limited.mutex.Lock()
+	defer limited.mutex.Unlock()
+	burst := max(perSecond, 1)
+	limited.buckets[item] = &tokenBucket{
+		rate:    perSecond,
+		burst:   burst,
+		tokens:  burst,
+		updated: limited.clock.Now(),
+	}
+	limited.refresh(item)
+	return limited
+}
+
+// Next selects an item from the items within their rates.
+//
+// Panics:
+//   - If every item has exceeded its rate. Use TryNext to avoid this.
+func (limited *RateLimited[TItem]) Next() TItem {
+	item, ok := limited.TryNext()
+	if !ok {
+		panic("every item has exceeded its rate")
+	}
+	return item
+}
+
+// TryNext selects an item from the items within their rates, and takes a
+// token of its bucket. If every item has exceeded its rate, it returns
+// false.
+func (limited *RateLimited[TItem]) TryNext() (TItem, bool) {
+	limited.mutex.Lock()
+	defer limited.mutex.Unlock()
+	now := limited.clock.Now()
+	for item, bucket := range limited.buckets {
+		if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+			bucket.tokens = min(bucket.tokens+elapsed.Seconds()*bucket.rate, bucket.burst)
+			bucket.updated = now
+			limited.refresh(item)
+		}
+	}
+	index, ok := limited.table.next()
+	if !ok {
+		var zero TItem
+		return zero, false
+	}
+	item := limited.table.item(index)
+	if bucket, ok := limited.buckets[item]; ok {
+		bucket.tokens--
+		limited.refresh(item)
+	}
+	return item, true
+}
+
+// refresh excludes or restores the item depending on whether its bucket
+// has a token left.
+func (limited *RateLimited[TItem]) refresh(item TItem) {
+	available := limited.buckets[item].tokens >= 1
+	for _, index := range limited.indices[item] {
+		if available {
+			limited.table.restore(index)
+		} else {
+			limited.table.exclude(index)
+		}
+	}
+}
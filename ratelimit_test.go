@@ -0,0 +1,74 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimited(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1_000},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewRateLimited[MarbleColor, int](nil, SystemClock)
+		})
+		assert.Panics(t, func() {
+			NewRateLimited(rand.New(rand.NewSource(1)), SystemClock, items...).WithMaxRate(Blue, 0)
+		})
+	})
+	t.Run("capped at rate", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := NewRateLimited(rand.New(rand.NewSource(1)), clock, items...).
+			WithMaxRate(Blue, 10)
+		for range 60 {
+			counts := make(MarbleColorCounts)
+			for range 100 {
+				counts[r.Next()]++
+			}
+			assert.LessOrEqualf(t, counts[Blue], int64(10), "%v", counts)
+			clock.Advance(time.Second)
+		}
+	})
+	t.Run("redistributes excess", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := NewRateLimited(rand.New(rand.NewSource(1)), clock, items...).
+			WithMaxRate(Blue, 1)
+		for r.Next() != Blue {
+		}
+		for range 100 {
+			assert.Equal(t, Red, r.Next())
+		}
+		clock.Advance(500 * time.Millisecond)
+		assert.Equal(t, Red, r.Next())
+		clock.Advance(500 * time.Millisecond)
+		counts := make(MarbleColorCounts)
+		for range 100 {
+			counts[r.Next()]++
+		}
+		assert.Equalf(t, int64(1), counts[Blue], "%v", counts)
+	})
+	t.Run("every item exceeded", func(t *testing.T) {
+		clock := NewFixtureClock()
+		r := NewRateLimited(rand.New(rand.NewSource(1)), clock, items...).
+			WithMaxRate(Red, 0.5).
+			WithMaxRate(Blue, 0.5)
+		_, ok := r.TryNext()
+		assert.True(t, ok)
+		_, ok = r.TryNext()
+		assert.True(t, ok)
+		_, ok = r.TryNext()
+		assert.False(t, ok)
+		assert.Panics(t, func() {
+			r.Next()
+		})
+		clock.Advance(2 * time.Second)
+		_, ok = r.TryNext()
+		assert.True(t, ok)
+	})
+}
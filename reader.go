@@ -0,0 +1,109 @@
+package weightedrand
+
+import (
+	"fmt"
+	"io"
+)
+
+// mixedReader is the io.Reader of NewMixedReader.
+type mixedReader struct {
+	random  RandIntN
+	chunk   int
+	readers []weightedItem[io.Reader]
+	table   voseAliasMethodRandom[int]
+	// current is the index of the reader of the chunk being read, and
+	// remaining the number of bytes left to read from it.
+	current   int
+	remaining int
+}
+
+// NewMixedReader constructs an io.Reader that mixes the readers chunk by
+// chunk, reading every chunk from one of them selected by weight, such as
+// 70% of a corpus A and 30% of a corpus B. When a reader is exhausted, the
+// chunks are mixed from the remaining readers in proportion to their
+// weights, and the mixed reader is exhausted with the last of them. Errors
+// of the readers other than io.EOF are returned as they are.
+//
+// The function panics if no readers are provided, the chunk size is not
+// positive, or weights are negative.
+//
+// Type Parameters:
+//   - TWeight: The type representing the weight of each reader.
+//
+// Parameters:
+//   - random:    A RandIntN implementation used for random number generation.
+//   - chunkSize: The number of bytes read from a reader before another one is selected.
+//   - readers:   A variadic list of WeightedItem values, each containing a reader and its associated weight.
+//
+// Example usage:
+//
+//	mixed := NewMixedReader(randSource, 4096,
+//		WeightedItem[io.Reader, int]{Item: corpusA, Weight: 7},
+//		WeightedItem[io.Reader, int]{Item: corpusB, Weight: 3},
+//	)
+//	_, err := io.Copy(trainingStream, mixed)
+func NewMixedReader[TWeight Weight](random RandIntN, chunkSize int, readers ...WeightedItem[io.Reader, TWeight]) io.Reader {
+	if len(readers) == 0 {
+		panic("at least one reader must be provided")
+	} else if chunkSize <= 0 {
+		panic(fmt.Sprintf("chunk size must be positive, but was %d", chunkSize))
+	}
+	reader := &mixedReader{
+		random:  random,
+		chunk:   chunkSize,
+		readers: make([]weightedItem[io.Reader], len(readers)),
+	}
+	for i, item := range readers {
+		reader.readers[i] = weightedItem[io.Reader]{
+			Item:   item.Item,
+			Weight: effectiveWeight(item.Weight),
+		}
+	}
+	reader.build()
+	return reader
+}
+
+func (reader *mixedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for {
+		if reader.remaining == 0 {
+			if len(reader.readers) == 0 {
+				return 0, io.EOF
+			}
+			reader.current = reader.table.Next()
+			reader.remaining = reader.chunk
+		}
+		n, err := reader.readers[reader.current].Item.Read(p[:min(len(p), reader.remaining)])
+		reader.remaining -= n
+		if err == io.EOF {
+			reader.remove(reader.current)
+		} else if err != nil {
+			return n, err
+		}
+		if n > 0 || err == nil {
+			return n, nil
+		}
+	}
+}
+
+// remove discards the exhausted reader, and abandons its chunk.
+func (reader *mixedReader) remove(index int) {
+	reader.readers = append(reader.readers[:index], reader.readers[index+1:]...)
+	reader.remaining = 0
+	if len(reader.readers) > 0 {
+		reader.build()
+	}
+}
+
+func (reader *mixedReader) build() {
+	indices := make([]weightedItem[int], len(reader.readers))
+	for i, item := range reader.readers {
+		indices[i] = weightedItem[int]{
+			Item:   i,
+			Weight: item.Weight,
+		}
+	}
+	reader.table = newVoseAliasMethodFromDecimals(reader.random, indices)
+}
@@ -0,0 +1,64 @@
+package weightedrand_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func TestMixedReader(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewMixedReader[int](nil, 1)
+		})
+		assert.Panics(t, func() {
+			NewMixedReader(nil, 0, WeightedItem[io.Reader, int]{Item: strings.NewReader("a")})
+		})
+	})
+	t.Run("readers with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		const size = 1 << 20
+		mixed := NewMixedReader(r, 64,
+			WeightedItem[io.Reader, int]{Item: bytes.NewReader(bytes.Repeat([]byte("a"), size)), Weight: 7},
+			WeightedItem[io.Reader, int]{Item: bytes.NewReader(bytes.Repeat([]byte("b"), size)), Weight: 3},
+		)
+		buffer := make([]byte, size)
+		_, err := io.ReadFull(mixed, buffer)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.7, float64(bytes.Count(buffer, []byte("a")))/size, tolerance)
+		assert.InDelta(t, 0.3, float64(bytes.Count(buffer, []byte("b")))/size, tolerance)
+	})
+	t.Run("exhausted readers", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		mixed := NewMixedReader(r, 3,
+			WeightedItem[io.Reader, int]{Item: strings.NewReader("aaaaaaaaaa"), Weight: 1},
+			WeightedItem[io.Reader, int]{Item: strings.NewReader("bbbbbbbbbbbbbbbbbbbb"), Weight: 1},
+		)
+		data, err := io.ReadAll(mixed)
+		require.NoError(t, err)
+		assert.Len(t, data, 30)
+		assert.Equal(t, 10, strings.Count(string(data), "a"))
+		n, err := mixed.Read(make([]byte, 1))
+		assert.Zero(t, n)
+		assert.ErrorIs(t, err, io.EOF)
+	})
+	t.Run("errors", func(t *testing.T) {
+		mixed := NewMixedReader(rand.New(rand.NewSource(1)), 3, WeightedItem[io.Reader, int]{Item: failingReader{}})
+		_, err := io.ReadAll(mixed)
+		assert.EqualError(t, err, "disk on fire")
+	})
+}
@@ -0,0 +1,145 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Lister provides the current items and their weights, such as the
+// endpoints of a Kubernetes EndpointSlice weighted by topology hints or
+// annotations.
+type Lister[TItem any, TWeight Weight] func(ctx context.Context) ([]WeightedItem[TItem, TWeight], error)
+
+// Refresher is a WeightedRandom whose items are periodically reloaded from a
+// Lister. When the listed items change, a new alias table is built and
+// atomically swapped in; when they do not change, nothing is rebuilt. If the
+// Lister fails, the last successfully listed items remain in service.
+//
+// Refresher is safe for concurrent use, given that the random number
+// generator is.
+type Refresher[TItem comparable, TWeight Weight] struct {
+	swappable *Swappable[TItem]
+	random    RandIntN
+	lister    Lister[TItem, TWeight]
+	mutex     sync.Mutex
+	current   map[TItem]decimal.Decimal
+	onError   func(error)
+}
+
+// NewRefresher constructs a new Refresher, listing the initial items
+// synchronously so that it is ready for selection when returned. The items
+// are only reloaded by Refresh, or by Run.
+//
+// The function returns an error if the initial listing fails, is empty or
+// has negative weights.
+//
+// Example usage:
+//
+//	refresher, err := NewRefresher(ctx, randSource, listEndpoints)
+//	if err != nil {
+//		return err
+//	}
+//	go refresher.Run(ctx, 10*time.Second)
+//	endpoint := refresher.Next()
+func NewRefresher[TItem comparable, TWeight Weight](
+	ctx context.Context, random RandIntN, lister Lister[TItem, TWeight],
+) (*Refresher[TItem, TWeight], error) {
+	refresher := &Refresher[TItem, TWeight]{
+		random:  random,
+		lister:  lister,
+		onError: func(error) {},
+	}
+	if _, err := refresher.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return refresher, nil
+}
+
+// WithErrorHandler sets the function called by Run when a refresh fails. It
+// returns the Refresher to allow chaining.
+func (refresher *Refresher[TItem, TWeight]) WithErrorHandler(onError func(error)) *Refresher[TItem, TWeight] {
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+	refresher.onError = onError
+	return refresher
+}
+
+// Refresh lists the items, and swaps in a new alias table if they changed.
+// It reports whether the items changed. The order of the listed items does
+// not matter, and duplicated items are combined with their weights summed.
+func (refresher *Refresher[TItem, TWeight]) Refresh(ctx context.Context) (bool, error) {
+	items, err := refresher.lister(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not list items: %w", err)
+	} else if len(items) == 0 {
+		return false, fmt.Errorf("could not list items: %w", ErrNoItems)
+	}
+	listed := make(map[TItem]decimal.Decimal, len(items))
+	for index, item := range items {
+		weight, err := decimalWeight(index, item.Weight)
+		if err != nil {
+			return false, fmt.Errorf("could not list items: %w", err)
+		}
+		listed[item.Item] = listed[item.Item].Add(weight)
+	}
+
+	refresher.mutex.Lock()
+	defer refresher.mutex.Unlock()
+	if sameWeights(refresher.current, listed) {
+		return false, nil
+	}
+	table := NewAliasVoseMethod(refresher.random, items...)
+	if refresher.swappable == nil {
+		refresher.swappable = NewSwappable(table)
+	} else {
+		refresher.swappable.Swap(table)
+	}
+	refresher.current = listed
+	return true, nil
+}
+
+// Next selects an item from the most recently listed items.
+func (refresher *Refresher[TItem, TWeight]) Next() TItem {
+	return refresher.swappable.Next()
+}
+
+// Load returns the WeightedRandom built from the most recently listed items.
+func (refresher *Refresher[TItem, TWeight]) Load() WeightedRandom[TItem] {
+	return refresher.swappable.Load()
+}
+
+// Run refreshes the items every interval until the context is done. Errors
+// are passed to the handler set by WithErrorHandler, and do not stop Run.
+func (refresher *Refresher[TItem, TWeight]) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := refresher.Refresh(ctx); err != nil {
+				refresher.mutex.Lock()
+				onError := refresher.onError
+				refresher.mutex.Unlock()
+				onError(err)
+			}
+		}
+	}
+}
+
+func sameWeights[TItem comparable](a, b map[TItem]decimal.Decimal) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for item, weight := range a {
+		if other, ok := b[item]; !ok || !weight.Equal(other) {
+			return false
+		}
+	}
+	return true
+}
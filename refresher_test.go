@@ -0,0 +1,94 @@
+package weightedrand_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixtureLister struct {
+	mutex sync.Mutex
+	items []WeightedItem[MarbleColor, int]
+	err   error
+}
+
+func (lister *fixtureLister) set(err error, items ...WeightedItem[MarbleColor, int]) {
+	lister.mutex.Lock()
+	defer lister.mutex.Unlock()
+	lister.items, lister.err = items, err
+}
+
+func (lister *fixtureLister) list(context.Context) ([]WeightedItem[MarbleColor, int], error) {
+	lister.mutex.Lock()
+	defer lister.mutex.Unlock()
+	return lister.items, lister.err
+}
+
+func TestRefresher(t *testing.T) {
+	ctx := context.Background()
+	r := rand.New(rand.NewSource(1))
+	t.Run("initial listing fails", func(t *testing.T) {
+		lister := &fixtureLister{}
+		lister.set(errors.New("unavailable"))
+		_, err := NewRefresher(ctx, r, lister.list)
+		assert.Error(t, err)
+		lister.set(nil)
+		_, err = NewRefresher(ctx, r, lister.list)
+		assert.Error(t, err)
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Red, Weight: -1})
+		_, err = NewRefresher(ctx, r, lister.list)
+		assert.Error(t, err)
+	})
+	t.Run("refresh", func(t *testing.T) {
+		lister := &fixtureLister{}
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Red, Weight: 1}, WeightedItem[MarbleColor, int]{Item: Blue, Weight: 0})
+		refresher, err := NewRefresher(ctx, r, lister.list)
+		require.NoError(t, err)
+
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1}, WeightedItem[MarbleColor, int]{Item: Red, Weight: 1})
+		changed, err := refresher.Refresh(ctx)
+		require.NoError(t, err)
+		assert.False(t, changed)
+
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Green, Weight: 1})
+		changed, err = refresher.Refresh(ctx)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Equal(t, Green, refresher.Next())
+
+		lister.set(errors.New("unavailable"))
+		_, err = refresher.Refresh(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, Green, refresher.Next())
+	})
+	t.Run("run", func(t *testing.T) {
+		lister := &fixtureLister{}
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Red})
+		refresher, err := NewRefresher(ctx, r, lister.list)
+		require.NoError(t, err)
+		errs := make(chan error, 1)
+		refresher.WithErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go refresher.Run(runCtx, time.Millisecond)
+
+		lister.set(nil, WeightedItem[MarbleColor, int]{Item: Yellow})
+		assert.Eventually(t, func() bool {
+			return refresher.Next() == Yellow
+		}, time.Second, time.Millisecond)
+		lister.set(errors.New("unavailable"))
+		assert.Error(t, <-errs)
+	})
+}
@@ -0,0 +1,160 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"time"
+)
+
+// TraceBucket is a bucket of the histogram of a traffic trace: the number of
+// events that occurred within a span of time, and the weights of their
+// types.
+type TraceBucket[TItem any, TWeight Weight] struct {
+	// Duration is the span of time of the bucket in the trace.
+	Duration time.Duration
+	// Events is the number of events within the bucket.
+	Events int
+	// Mix holds the weights of the types of the events. If it is empty, the
+	// mix of the previous bucket is used.
+	Mix []WeightedItem[TItem, TWeight]
+}
+
+// ReplayEvent is an event emitted by a Replayer.
+type ReplayEvent[TItem any] struct {
+	// At is the offset of the event from the start of the replay, after the
+	// speed-up is applied.
+	At time.Duration
+	// Item is the type of the event.
+	Item TItem
+}
+
+// Replayer replays the histogram of a traffic trace, emitting as many events
+// within every bucket as it counted, at random offsets within the bucket and
+// of types selected by its mix. The replay can be sped up, which compresses
+// the offsets of the events without changing how many there are.
+type Replayer[TItem any, TWeight Weight] struct {
+	random  RandIntN
+	speed   float64
+	buckets []TraceBucket[TItem, TWeight]
+	mixes   []WeightedRandom[TItem]
+}
+
+// NewReplayer constructs a new Replayer over the buckets of a trace, in the
+// order they occurred.
+//
+// The function panics if no buckets are provided, the speed-up is not a
+// positive finite number, a bucket has a negative duration or number of
+// events, a bucket with events has no duration, the first bucket has no
+// mix, or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the events.
+//   - TWeight: The type representing the weight of each type of event.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - speedUp: The factor by which the replay is faster than the trace, such as 10.
+//   - buckets: A variadic list of TraceBucket values.
+//
+// Example usage:
+//
+//	replayer := NewReplayer(randSource, 60,
+//		TraceBucket[string, int]{Duration: time.Minute, Events: 120, Mix: []WeightedItem[string, int]{{Item: "GET", Weight: 9}, {Item: "POST", Weight: 1}}},
+//		TraceBucket[string, int]{Duration: time.Minute, Events: 300},
+//	)
+//	err := replayer.Run(ctx, func(event ReplayEvent[string]) { send(event.Item) })
+func NewReplayer[TItem any, TWeight Weight](random RandIntN, speedUp float64, buckets ...TraceBucket[TItem, TWeight]) *Replayer[TItem, TWeight] {
+	if len(buckets) == 0 {
+		panic("at least one bucket must be provided")
+	} else if !(speedUp > 0) || math.IsInf(speedUp, 1) {
+		panic(fmt.Sprintf("speed-up must be a positive finite number, but was %g", speedUp))
+	} else if len(buckets[0].Mix) == 0 {
+		panic("the first bucket must have a mix")
+	}
+	replayer := &Replayer[TItem, TWeight]{
+		random:  random,
+		speed:   speedUp,
+		buckets: slices.Clone(buckets),
+		mixes:   make([]WeightedRandom[TItem], len(buckets)),
+	}
+	for index, bucket := range buckets {
+		if bucket.Duration < 0 || bucket.Events < 0 {
+			panic(fmt.Sprintf("bucket %d must have a non-negative duration and number of events, but had %s and %d", index, bucket.Duration, bucket.Events))
+		} else if bucket.Duration == 0 && bucket.Events > 0 {
+			panic(fmt.Sprintf("bucket %d has %d events, but no duration", index, bucket.Events))
+		}
+		if len(bucket.Mix) == 0 {
+			replayer.mixes[index] = replayer.mixes[index-1]
+		} else {
+			replayer.mixes[index] = NewAliasVoseMethod(random, bucket.Mix...)
+		}
+	}
+	return replayer
+}
+
+// Duration returns the duration of the replay, which is the duration of the
+// trace divided by the speed-up.
+func (replayer *Replayer[TItem, TWeight]) Duration() time.Duration {
+	total := time.Duration(0)
+	for _, bucket := range replayer.buckets {
+		total += bucket.Duration
+	}
+	return replayer.scaled(total)
+}
+
+// Events returns the events of a replay in the order they occur, without
+// waiting for them. Every iteration draws new offsets and types.
+func (replayer *Replayer[TItem, TWeight]) Events() iter.Seq[ReplayEvent[TItem]] {
+	return func(yield func(ReplayEvent[TItem]) bool) {
+		start := time.Duration(0)
+		for index, bucket := range replayer.buckets {
+			// The offsets are uniform within the bucket, which is how a
+			// Poisson process distributes a known number of events.
+			offsets := make([]time.Duration, bucket.Events)
+			for i := range offsets {
+				offsets[i] = time.Duration(replayer.random.Int63n(int64(bucket.Duration)))
+			}
+			slices.Sort(offsets)
+			for _, offset := range offsets {
+				event := ReplayEvent[TItem]{
+					At:   replayer.scaled(start + offset),
+					Item: replayer.mixes[index].Next(),
+				}
+				if !yield(event) {
+					return
+				}
+			}
+			start += bucket.Duration
+		}
+	}
+}
+
+// Run replays the trace in real time, calling emit with every event when
+// its offset from the start of the replay is reached. It returns once every
+// event is emitted, or the error of the context when it is done first.
+func (replayer *Replayer[TItem, TWeight]) Run(ctx context.Context, emit func(ReplayEvent[TItem])) error {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for event := range replayer.Events() {
+		if wait := time.Until(start.Add(event.At)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		emit(event)
+	}
+	return nil
+}
+
+func (replayer *Replayer[TItem, TWeight]) scaled(offset time.Duration) time.Duration {
+	return time.Duration(float64(offset) / replayer.speed)
+}
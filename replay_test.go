@@ -0,0 +1,75 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayer(t *testing.T) {
+	mix := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 3},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewReplayer[MarbleColor, int](nil, 1)
+		})
+		assert.Panics(t, func() {
+			NewReplayer(nil, 0, TraceBucket[MarbleColor, int]{Duration: time.Second, Mix: mix})
+		})
+		assert.Panics(t, func() {
+			NewReplayer(nil, 1, TraceBucket[MarbleColor, int]{Duration: time.Second})
+		})
+		assert.Panics(t, func() {
+			NewReplayer(nil, 1, TraceBucket[MarbleColor, int]{Events: 1, Mix: mix})
+		})
+		assert.Panics(t, func() {
+			NewReplayer(nil, 1, TraceBucket[MarbleColor, int]{Duration: time.Second, Events: -1, Mix: mix})
+		})
+	})
+	t.Run("temporal density and mix", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		replayer := NewReplayer(r, 10,
+			TraceBucket[MarbleColor, int]{Duration: time.Minute, Events: 10_000, Mix: mix},
+			TraceBucket[MarbleColor, int]{Duration: time.Minute},
+			TraceBucket[MarbleColor, int]{Duration: time.Minute, Events: 30_000, Mix: []WeightedItem[MarbleColor, int]{{Item: Green}}},
+		)
+		assert.Equal(t, 18*time.Second, replayer.Duration())
+		perBucket := make([]MarbleColorCounts, 3)
+		for i := range perBucket {
+			perBucket[i] = make(MarbleColorCounts)
+		}
+		previous := time.Duration(0)
+		for event := range replayer.Events() {
+			assert.GreaterOrEqual(t, event.At, previous)
+			previous = event.At
+			perBucket[event.At/(6*time.Second)][event.Item] += 1
+		}
+		assert.InDeltaf(t, 0.25, float64(perBucket[0][Red])/10_000, tolerance, "%s", perBucket[0])
+		assert.InDeltaf(t, 0.75, float64(perBucket[0][Blue])/10_000, tolerance, "%s", perBucket[0])
+		assert.Empty(t, perBucket[1])
+		assert.Equal(t, MarbleColorCounts{Green: 30_000}, perBucket[2])
+	})
+	t.Run("run", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		replayer := NewReplayer(r, 100, TraceBucket[MarbleColor, int]{Duration: 5 * time.Second, Events: 20, Mix: mix})
+		start := time.Now()
+		var emitted []ReplayEvent[MarbleColor]
+		assert.NoError(t, replayer.Run(context.Background(), func(event ReplayEvent[MarbleColor]) {
+			assert.GreaterOrEqual(t, time.Since(start), event.At)
+			emitted = append(emitted, event)
+		}))
+		assert.Len(t, emitted, 20)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, replayer.Run(ctx, func(ReplayEvent[MarbleColor]) {
+			t.Fatal("no event should be emitted")
+		}), context.Canceled)
+	})
+}
@@ -0,0 +1,64 @@
+package weightedrand
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// NewRevealSeed generates a seed suitable for a commit-reveal draw, with 256
+// bits of entropy from the operating system. The seed must be kept secret
+// until the draw is revealed.
+func NewRevealSeed() (string, error) {
+	var entropy [32]byte
+	if _, err := rand.Read(entropy[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(entropy[:]), nil
+}
+
+// Commit returns the commitment to the seed of a commit-reveal draw, which
+// is the hexadecimal SHA-256 digest of the seed. The commitment is published
+// before the draw, so that the seed cannot be changed after the entrants are
+// known, and anyone can check it with standard tools once the seed is
+// revealed.
+//
+// The seed should be unpredictable, such as one generated by NewRevealSeed,
+// as a guessable seed allows the result to be computed from the commitment.
+func Commit(seed string) string {
+	digest := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(digest[:])
+}
+
+// Reveal deterministically derives the weighted selection of a
+// commit-reveal draw from the revealed seed. It is the first selection of
+// NewCounterBasedWithOptions(seed, items, WithExactThresholds()), so the same
+// seed and items always produce the same result, and every entrant is drawn
+// with exactly the probability of its weight, however small.
+//
+// The function panics if no items are provided, weights are negative, or
+// the weights span too many orders of magnitude to be scaled into exact
+// thresholds, as by WithExactThresholds.
+//
+// Example usage:
+//
+//	seed, _ := NewRevealSeed()
+//	commitment := Commit(seed) // publish before the draw
+//	winner := Reveal(seed, entrants...)
+//	// publish the seed and the winner
+func Reveal[TItem any, TWeight Weight](seed string, items ...WeightedItem[TItem, TWeight]) TItem {
+	return NewCounterBasedWithOptions(seed, items, WithExactThresholds()).NthSelection(0)
+}
+
+// Verify reports whether the revealed seed matches the commitment, and
+// whether the result is the selection derived from the seed and items. It
+// allows a third party to verify a draw made with Commit and Reveal.
+//
+// The function panics like Reveal.
+func Verify[TItem comparable, TWeight Weight](commitment string, seed string, items []WeightedItem[TItem, TWeight], result TItem) bool {
+	if subtle.ConstantTimeCompare([]byte(Commit(seed)), []byte(commitment)) != 1 {
+		return false
+	}
+	return Reveal(seed, items...) == result
+}
@@ -0,0 +1,67 @@
+package weightedrand_test
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitReveal(t *testing.T) {
+	entrants := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 3},
+	}
+	t.Run("commitment", func(t *testing.T) {
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Commit("hello"))
+	})
+	t.Run("seeds are unique", func(t *testing.T) {
+		first, err := NewRevealSeed()
+		assert.NoError(t, err)
+		second, err := NewRevealSeed()
+		assert.NoError(t, err)
+		assert.Len(t, first, 64)
+		assert.NotEqual(t, first, second)
+	})
+	t.Run("verify", func(t *testing.T) {
+		seed, err := NewRevealSeed()
+		assert.NoError(t, err)
+		commitment := Commit(seed)
+		winner := Reveal(seed, entrants...)
+		assert.Equal(t, winner, Reveal(seed, entrants...))
+		assert.True(t, Verify(commitment, seed, entrants, winner))
+		for _, entrant := range entrants {
+			if entrant.Item != winner {
+				assert.False(t, Verify(commitment, seed, entrants, entrant.Item))
+			}
+		}
+		assert.False(t, Verify(commitment, seed+"0", entrants, Reveal(seed+"0", entrants...)))
+		assert.False(t, Verify(Commit("other"), seed, entrants, winner))
+	})
+	t.Run("distribution", func(t *testing.T) {
+		const iterations = 20_000
+		counts := make(MarbleColorCounts)
+		for index := range iterations {
+			counts[Reveal(strconv.Itoa(index), entrants...)] += 1
+		}
+		assert.InDeltaf(t, 3.0/6, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+	t.Run("rare entrants are drawn exactly", func(t *testing.T) {
+		const iterations = 200_000
+		rare := []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 999},
+		}
+		red := 0
+		for index := range iterations {
+			if Reveal(strconv.Itoa(index), rare...) == Red {
+				red++
+			}
+		}
+		// A coin toss of 1/100 resolution would draw red about 0.005 of the
+		// time.
+		assert.InDelta(t, 0.001, float64(red)/iterations, 0.0003)
+	})
+}
@@ -0,0 +1,167 @@
+package weightedrand
+
+import (
+	"container/heap"
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+// uniformDecimal returns a uniformly distributed decimal within [0, 1),
+// with the precision of probabilityScale.
+func uniformDecimal(random RandIntN) decimal.Decimal {
+	return decimal.New(random.Int63n(probabilityResolution), -probabilityScale)
+}
+
+// Sample selects the index of a single item by weight, without building a
+// table. It performs a single pass over the items using weighted reservoir
+// sampling, calling weightOf once per index, which makes it suited for a
+// single pick from a transient slice. For repeated selections from the same
+// items, NewAliasVoseMethod is more efficient.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - items:    The items to be sampled.
+//   - weightOf: A function returning the weight of the item at an index.
+//   - random:   A RandIntN implementation used for random number generation.
+//
+// Example usage:
+//
+//	i := Sample(candidates, func(i int) int { return candidates[i].Score }, randSource)
+func Sample[TItem any, TWeight Weight](items []TItem, weightOf func(i int) TWeight, random RandIntN) int {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	}
+	selected := 0
+	totalWeight := decimal.Zero
+	for i := range items {
+		weight := effectiveWeight(weightOf(i))
+		totalWeight = totalWeight.Add(weight)
+		// Replace the selection with a probability of weight/totalWeight.
+		if i == 0 || uniformDecimal(random).Mul(totalWeight).LessThan(weight) {
+			selected = i
+		}
+	}
+	return selected
+}
+
+// ChooseOne selects a single item by weight from a sequence of items and
+// their weights, in a single pass, for when the total weight is not known
+// in advance, such as rows streamed from a database. Like Sample, it keeps
+// a weighted reservoir of size one, which the item being visited replaces
+// with a probability of its weight over the total weight so far.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1.
+//
+// The function panics if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - seq:    The sequence of items and their weights.
+//   - random: A RandIntN implementation used for random number generation.
+//
+// Returns:
+//   - TItem: The selected item, or the zero value if the sequence is empty.
+//   - bool:  Whether the sequence had any item.
+//
+// Example usage:
+//
+//	winner, ok := ChooseOne(maps.All(ticketsByUser), randSource)
+func ChooseOne[TItem any, TWeight Weight](seq iter.Seq2[TItem, TWeight], random RandIntN) (TItem, bool) {
+	var selected TItem
+	found := false
+	totalWeight := decimal.Zero
+	for item, weight := range seq {
+		effective := effectiveWeight(weight)
+		totalWeight = totalWeight.Add(effective)
+		if !found || uniformDecimal(random).Mul(totalWeight).LessThan(effective) {
+			selected = item
+			found = true
+		}
+	}
+	return selected, found
+}
+
+// SampleK selects the indices of k distinct items by weight, without
+// replacement and without building a table. It performs a single pass over
+// the items using exponential keys (the A-Res algorithm of Efraimidis and
+// Spirakis), calling weightOf once per index, in O(n log k) time. The
+// indices are returned in the order they were selected; if k is at least the
+// number of items, every index is returned.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1. The keys are computed with float64
+// arithmetic, so weights beyond its precision are approximated.
+//
+// The function panics if k is negative or weights are negative.
+//
+// Example usage:
+//
+//	indices := SampleK(candidates, func(i int) int { return candidates[i].Score }, 3, randSource)
+func SampleK[TItem any, TWeight Weight](items []TItem, weightOf func(i int) TWeight, k int, random RandIntN) []int {
+	if k < 0 {
+		panic(fmt.Sprintf("k must be non-negative value, but was %d", k))
+	}
+	k = min(k, len(items))
+	if k == 0 {
+		return []int{}
+	}
+	// Keep the k largest keys in a min-heap, so the smallest is replaced
+	// when a larger key is found.
+	reservoir := make(keyedIndexHeap, 0, k)
+	for i := range items {
+		weight := effectiveWeight(weightOf(i)).InexactFloat64()
+		// The key is u^(1/weight), compared by its logarithm to avoid
+		// underflow with large weights.
+		u := (float64(random.Int63n(float64Resolution)) + 0.5) / float64Resolution
+		key := math.Log(u) / weight
+		if len(reservoir) < k {
+			heap.Push(&reservoir, keyedIndex{index: i, key: key})
+		} else if key > reservoir[0].key {
+			reservoir[0] = keyedIndex{index: i, key: key}
+			heap.Fix(&reservoir, 0)
+		}
+	}
+	result := make([]int, len(reservoir))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&reservoir).(keyedIndex).index
+	}
+	return result
+}
+
+type keyedIndex struct {
+	index int
+	key   float64
+}
+
+// keyedIndexHeap is a min-heap of indices by their key.
+type keyedIndexHeap []keyedIndex
+
+func (h keyedIndexHeap) Len() int           { return len(h) }
+func (h keyedIndexHeap) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h keyedIndexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *keyedIndexHeap) Push(x any) {
+	*h = append(*h, x.(keyedIndex))
+}
+
+func (h *keyedIndexHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
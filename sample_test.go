@@ -0,0 +1,97 @@
+package weightedrand_test
+
+import (
+	"maps"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSample(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			Sample[MarbleColor](nil, func(int) int { return 1 }, nil)
+		})
+		assert.Panics(t, func() {
+			Sample([]MarbleColor{Red}, func(int) int { return -1 }, nil)
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		colors := []MarbleColor{Red, Green, Blue}
+		weights := []int{1, 0, 2}
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			i := Sample(colors, func(i int) int { return weights[i] }, r)
+			counts[colors[i]] += 1
+		}
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.25, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.50, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+}
+
+func TestChooseOne(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			ChooseOne(maps.All(map[MarbleColor]int{Red: -1}), rand.New(rand.NewSource(1)))
+		})
+	})
+	t.Run("empty", func(t *testing.T) {
+		item, ok := ChooseOne(maps.All(map[MarbleColor]int{}), nil)
+		assert.False(t, ok)
+		assert.Zero(t, item)
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		weights := map[MarbleColor]int{Red: 1, Green: 0, Blue: 2}
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			item, ok := ChooseOne(maps.All(weights), r)
+			assert.True(t, ok)
+			counts[item] += 1
+		}
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.25, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.50, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+}
+
+func TestSampleK(t *testing.T) {
+	colors := []MarbleColor{Red, Orange, Yellow, Green, Blue}
+	weights := []int{1, 1, 1, 1, 96}
+	weightOf := func(i int) int { return weights[i] }
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			SampleK(colors, weightOf, -1, nil)
+		})
+	})
+	t.Run("distinct", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		assert.Empty(t, SampleK(colors, weightOf, 0, r))
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4}, SampleK(colors, weightOf, 10, r))
+		for range 1_000 {
+			indices := SampleK(colors, weightOf, 3, r)
+			assert.Len(t, indices, 3)
+			seen := make(map[int]bool)
+			for _, i := range indices {
+				assert.False(t, seen[i])
+				seen[i] = true
+			}
+		}
+	})
+	t.Run("heavy items are selected first", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		const iterations = 10_000
+		first := make(MarbleColorCounts)
+		for range iterations {
+			first[colors[SampleK(colors, weightOf, 2, r)[0]]] += 1
+		}
+		assert.InDeltaf(t, 0.96, float64(first[Blue])/iterations, tolerance, "%s", first)
+	})
+}
@@ -0,0 +1,114 @@
+package weightedrand
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"time"
+)
+
+// ScheduledEvent is an event emitted by a Scheduler.
+type ScheduledEvent[TItem any] struct {
+	// At is the offset of the event from the start of the schedule.
+	At time.Duration
+	// Item is the scenario of the event.
+	Item TItem
+}
+
+// Scheduler fires scenarios at random, such as the faults of a chaos
+// experiment or the events of a game world, as a compound Poisson process:
+// the intervals between events are exponentially distributed around a mean
+// interval, and the scenario of every event is selected by weight. Each
+// scenario in turn fires as a Poisson process of its own, at its share of
+// the total rate.
+//
+// Scheduler is safe for concurrent use, given that the random number
+// generator is.
+type Scheduler[TItem any] struct {
+	random    RandIntN
+	mean      time.Duration
+	scenarios WeightedRandom[TItem]
+}
+
+// NewScheduler constructs a new Scheduler using the Alias Method (Vose's
+// algorithm) to select the scenarios.
+//
+// The function panics if the mean interval is not positive, no scenarios
+// are provided, or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the scenarios, such as callbacks.
+//   - TWeight: The type representing the weight of each scenario.
+//
+// Parameters:
+//   - random:       A RandIntN implementation used for random number generation.
+//   - meanInterval: The mean interval between events.
+//   - scenarios:    The WeightedItem values, each containing a scenario and its associated weight.
+//
+// Example usage:
+//
+//	scheduler := NewScheduler(randSource, time.Minute,
+//		WeightedItem[func(), int]{Item: killPod, Weight: 3},
+//		WeightedItem[func(), int]{Item: partitionNetwork, Weight: 1},
+//	)
+//	err := scheduler.Run(ctx, func(event ScheduledEvent[func()]) { event.Item() })
+func NewScheduler[TItem any, TWeight Weight](random RandIntN, meanInterval time.Duration, scenarios ...WeightedItem[TItem, TWeight]) *Scheduler[TItem] {
+	if meanInterval <= 0 {
+		panic(fmt.Sprintf("mean interval must be positive, but was %s", meanInterval))
+	}
+	return &Scheduler[TItem]{
+		random:    random,
+		mean:      meanInterval,
+		scenarios: NewAliasVoseMethod(random, scenarios...),
+	}
+}
+
+// Next returns the interval until the next event, and its scenario.
+func (scheduler *Scheduler[TItem]) Next() (time.Duration, TItem) {
+	// The inverse of the exponential distribution, for a uniform u in (0, 1].
+	uniform := float64(scheduler.random.Int63n(1<<53)+1) / (1 << 53)
+	interval := time.Duration(-math.Log(uniform) * float64(scheduler.mean))
+	return interval, scheduler.scenarios.Next()
+}
+
+// Events returns the events of a schedule in the order they occur, without
+// waiting for them. The sequence is endless; every iteration draws new
+// intervals and scenarios.
+func (scheduler *Scheduler[TItem]) Events() iter.Seq[ScheduledEvent[TItem]] {
+	return func(yield func(ScheduledEvent[TItem]) bool) {
+		at := time.Duration(0)
+		for {
+			interval, item := scheduler.Next()
+			at += interval
+			if !yield(ScheduledEvent[TItem]{At: at, Item: item}) {
+				return
+			}
+		}
+	}
+}
+
+// Run fires the events of a schedule in real time, calling fire with every
+// event when its offset from the start of the schedule is reached, until
+// the context is done, and returns its error.
+func (scheduler *Scheduler[TItem]) Run(ctx context.Context, fire func(ScheduledEvent[TItem])) error {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	at := time.Duration(0)
+	for {
+		interval, item := scheduler.Next()
+		at += interval
+		if wait := time.Until(start.Add(at)); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		fire(ScheduledEvent[TItem]{At: at, Item: item})
+	}
+}
@@ -0,0 +1,66 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "mean interval must be positive, but was 0s", func() {
+			NewScheduler(nil, 0, WeightedItem[MarbleColor, int]{Item: Red})
+		})
+		assert.Panics(t, func() {
+			NewScheduler[MarbleColor, int](nil, time.Second)
+		})
+	})
+	t.Run("compound poisson process", func(t *testing.T) {
+		const samples = 100_000
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		scheduler := NewScheduler(r, time.Second,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+			WeightedItem[MarbleColor, int]{Item: Blue, Weight: 3},
+		)
+		counts := MarbleColorCounts{}
+		previous, withinMean := time.Duration(0), 0
+		for event := range scheduler.Events() {
+			counts[event.Item]++
+			interval := event.At - previous
+			assert.GreaterOrEqual(t, interval, time.Duration(0))
+			if interval < time.Second {
+				withinMean++
+			}
+			previous = event.At
+			if counts[Red]+counts[Blue] == samples {
+				break
+			}
+		}
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/samples, tolerance, "%v", counts)
+		// The mean interval is a second, and 1 - 1/e of the intervals are
+		// shorter than the mean.
+		assert.InDelta(t, samples, previous.Seconds(), 0.02*samples)
+		assert.InDelta(t, 0.632, float64(withinMean)/samples, tolerance)
+	})
+	t.Run("run", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		fired := 0
+		scheduler := NewScheduler(r, time.Millisecond,
+			WeightedItem[func(), int]{Item: func() { fired++ }},
+		)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		previous := time.Duration(0)
+		err := scheduler.Run(ctx, func(event ScheduledEvent[func()]) {
+			assert.GreaterOrEqual(t, event.At, previous)
+			previous = event.At
+			event.Item()
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Greater(t, fired, 10)
+	})
+}
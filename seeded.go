@@ -0,0 +1,30 @@
+package weightedrand
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/rand"
+)
+
+// NewRandFromString derives a deterministic random number generator from a
+// string seed. The seed is hashed with SHA-256, so similar strings produce
+// unrelated sequences, and the same string produces the same sequence across
+// runs and machines.
+func NewRandFromString(seed string) *rand.Rand {
+	digest := sha256.Sum256([]byte(seed))
+	return rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(digest[:8]))))
+}
+
+// NewSeededFromString constructs a new WeightedRandom instance using the
+// Alias Method (Vose's algorithm) with a random number generator derived
+// from the string seed. Two instances built from the same seed and items
+// produce the same sequence of selections.
+//
+// The function panics if no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	wr := NewSeededFromString("simulation-42", WeightedItem[string, int]{Item: "A", Weight: 2}, WeightedItem[string, int]{Item: "B", Weight: 3})
+func NewSeededFromString[TItem any, TWeight Weight](seed string, items ...WeightedItem[TItem, TWeight]) WeightedRandom[TItem] {
+	return NewAliasVoseMethod(NewRandFromString(seed), items...)
+}
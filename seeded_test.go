@@ -0,0 +1,26 @@
+package weightedrand_test
+
+import (
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSeededFromString(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 3},
+	}
+	sequence := func(seed string) []MarbleColor {
+		wr := NewSeededFromString(seed, items...)
+		result := make([]MarbleColor, 0, 50)
+		for range 50 {
+			result = append(result, wr.Next())
+		}
+		return result
+	}
+	assert.Equal(t, sequence("reproducible"), sequence("reproducible"))
+	assert.NotEqual(t, sequence("reproducible"), sequence("reproducible!"))
+}
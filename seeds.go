@@ -0,0 +1,116 @@
+package weightedrand
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SeedManager derives seeds from a master secret, one for every epoch of a
+// fixed length, such as a day, and purpose, such as a raffle or experiment.
+// Every epoch thus gets fresh randomness, which cannot be predicted without
+// the secret, while any past draw can be reproduced from the secret and its
+// epoch. Epochs are counted from the Unix epoch, so daily epochs start at
+// midnight UTC.
+//
+// SeedManager is safe for concurrent use.
+type SeedManager struct {
+	secret   []byte
+	clock    Clock
+	length   time.Duration
+	mutex    sync.Mutex
+	current  int64
+	observed bool
+	onRotate func(previous, current int64)
+}
+
+// NewSeedManager constructs a new SeedManager from the master secret, which
+// must be kept private, such as one generated by NewRevealSeed.
+//
+// Panics:
+//   - If the secret is empty, or the epoch length is not positive.
+//
+// Parameters:
+//   - secret: The master secret every seed is derived from.
+//   - clock:  A Clock implementation used to determine the current epoch.
+//   - epoch:  The length of an epoch, such as 24 * time.Hour.
+//
+// Example usage:
+//
+//	seeds := NewSeedManager([]byte(os.Getenv("RAFFLE_SECRET")), SystemClock, 24*time.Hour).
+//		WithOnRotate(func(previous, current int64) { log.Printf("raffle epoch %d began", current) })
+//	winner := NewAliasVoseMethod(seeds.Rand("daily-raffle"), entrants...).Next()
+func NewSeedManager(secret []byte, clock Clock, epoch time.Duration) *SeedManager {
+	if len(secret) == 0 {
+		panic("secret must not be empty")
+	} else if epoch <= 0 {
+		panic(fmt.Sprintf("epoch length must be positive, but was %s", epoch))
+	}
+	return &SeedManager{
+		secret: append([]byte(nil), secret...),
+		clock:  clock,
+		length: epoch,
+	}
+}
+
+// WithOnRotate registers a hook called with the previous and the current
+// epoch when a new epoch is observed, by Epoch, Seed or Rand. The hook is
+// called before they return, but without the SeedManager being locked. It
+// returns the SeedManager to allow chaining.
+func (manager *SeedManager) WithOnRotate(hook func(previous, current int64)) *SeedManager {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	manager.onRotate = hook
+	return manager
+}
+
+// Epoch returns the number of the current epoch.
+func (manager *SeedManager) Epoch() int64 {
+	epoch := manager.EpochAt(manager.clock.Now())
+	manager.mutex.Lock()
+	previous, observed, onRotate := manager.current, manager.observed, manager.onRotate
+	rotated := observed && epoch > previous
+	if !observed || rotated {
+		manager.current, manager.observed = epoch, true
+	}
+	manager.mutex.Unlock()
+	if rotated && onRotate != nil {
+		onRotate(previous, epoch)
+	}
+	return epoch
+}
+
+// EpochAt returns the number of the epoch the time falls within.
+func (manager *SeedManager) EpochAt(at time.Time) int64 {
+	nanos := at.UnixNano()
+	epoch := nanos / int64(manager.length)
+	if nanos%int64(manager.length) < 0 {
+		epoch--
+	}
+	return epoch
+}
+
+// Seed returns the seed of the purpose in the current epoch.
+func (manager *SeedManager) Seed(purpose string) string {
+	return manager.SeedAt(purpose, manager.Epoch())
+}
+
+// SeedAt returns the seed of the purpose in the epoch, which is the
+// hexadecimal HMAC-SHA256 of the purpose and epoch keyed by the secret.
+func (manager *SeedManager) SeedAt(purpose string, epoch int64) string {
+	mac := hmac.New(sha256.New, manager.secret)
+	mac.Write([]byte(strconv.Quote(purpose)))
+	mac.Write([]byte(strconv.FormatInt(epoch, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Rand returns a new random number generator derived from the seed of the
+// purpose in the current epoch, as if by NewRandFromString.
+func (manager *SeedManager) Rand(purpose string) *rand.Rand {
+	return NewRandFromString(manager.Seed(purpose))
+}
@@ -0,0 +1,56 @@
+package weightedrand_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedManager(t *testing.T) {
+	secret := []byte("master secret")
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSeedManager(nil, SystemClock, time.Hour)
+		})
+		assert.Panics(t, func() {
+			NewSeedManager(secret, SystemClock, 0)
+		})
+	})
+	t.Run("epochs", func(t *testing.T) {
+		seeds := NewSeedManager(secret, SystemClock, 24*time.Hour)
+		assert.Equal(t, int64(0), seeds.EpochAt(time.Unix(0, 0)))
+		assert.Equal(t, int64(0), seeds.EpochAt(time.Unix(86_399, 0)))
+		assert.Equal(t, int64(1), seeds.EpochAt(time.Unix(86_400, 0)))
+		assert.Equal(t, int64(-1), seeds.EpochAt(time.Unix(-1, 0)))
+	})
+	t.Run("reproducible", func(t *testing.T) {
+		clock := NewFixtureClock()
+		seeds := NewSeedManager(secret, clock, time.Hour)
+		seed := seeds.Seed("raffle")
+		assert.Len(t, seed, 64)
+		assert.Equal(t, seed, NewSeedManager(secret, clock, time.Hour).Seed("raffle"))
+		assert.Equal(t, seed, seeds.SeedAt("raffle", seeds.Epoch()))
+		assert.NotEqual(t, seed, seeds.Seed("experiment"))
+		assert.NotEqual(t, seed, NewSeedManager([]byte("other secret"), clock, time.Hour).Seed("raffle"))
+		assert.Equal(t, seeds.Rand("raffle").Int63(), seeds.Rand("raffle").Int63())
+	})
+	t.Run("rotation", func(t *testing.T) {
+		clock := NewFixtureClock()
+		var rotations [][2]int64
+		seeds := NewSeedManager(secret, clock, time.Hour).
+			WithOnRotate(func(previous, current int64) {
+				rotations = append(rotations, [2]int64{previous, current})
+			})
+		before := seeds.Seed("raffle")
+		first := seeds.Epoch()
+		assert.Empty(t, rotations)
+		clock.Advance(time.Hour)
+		assert.NotEqual(t, before, seeds.Seed("raffle"))
+		assert.Equal(t, [][2]int64{{first, first + 1}}, rotations)
+		seeds.Seed("raffle")
+		assert.Len(t, rotations, 1)
+		assert.Equal(t, before, seeds.SeedAt("raffle", first))
+	})
+}
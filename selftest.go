@@ -0,0 +1,71 @@
+package weightedrand
+
+import "fmt"
+
+// selfTestSignificance is the p-value below which SelfTest reports bias. It
+// is low enough for SelfTest to not fail spuriously in a CI pipeline.
+const selfTestSignificance = 1e-6
+
+// BiasError is returned by SelfTest when the selections deviate from the
+// weights of the chooser.
+type BiasError[TItem comparable] struct {
+	Deviation[TItem]
+	// Iterations is the number of selections that were tested.
+	Iterations int
+}
+
+func (err *BiasError[TItem]) Error() string {
+	return fmt.Sprintf(
+		"selections deviate from the weights after %d iterations (chi-square %.2f, p-value %.3g): observed %v, expected %v",
+		err.Iterations, err.Statistic, err.PValue, err.Observed, err.Expected,
+	)
+}
+
+// SelfTest selects items from the WeightedRandom instance, and runs a
+// chi-square goodness-of-fit test of the selections against its weights.
+// It detects a biased engine or a broken random number generator, and is
+// intended to be run once, such as in the CI pipeline of an application.
+// The selections advance the random number generator of the instance.
+//
+// The test assumes that every item is expected to be selected several times,
+// so the iterations should be large relative to the inverse of the smallest
+// probability. More iterations detect smaller biases.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - wr:         The WeightedRandom instance to test.
+//   - iterations: The number of selections to test.
+//
+// Returns:
+//   - error: A *BiasError if the selections deviate from the weights, or nil.
+//
+// Panics:
+//   - If the iterations are not positive.
+//   - If the distribution of the instance cannot be determined, because it
+//     was not constructed by this package.
+//
+// Example usage:
+//
+//	if err := SelfTest(wr, 1_000_000); err != nil {
+//		t.Fatal(err)
+//	}
+func SelfTest[TItem comparable](wr WeightedRandom[TItem], iterations int) error {
+	if iterations <= 0 {
+		panic(fmt.Sprintf("iterations must be positive value, but was %d", iterations))
+	}
+	frequencies := newExpectedFrequencies(wr)
+	counts := make([]int, len(frequencies.items))
+	for range iterations {
+		counts[frequencies.indices[wr.Next()]]++
+	}
+	deviation := frequencies.test(counts, iterations)
+	if deviation.PValue >= selfTestSignificance {
+		return nil
+	}
+	return &BiasError[TItem]{
+		Deviation:  frequencies.describe(deviation, counts, iterations),
+		Iterations: iterations,
+	}
+}
@@ -0,0 +1,42 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfTest(t *testing.T) {
+	items := []WeightedItem[string, int]{
+		{Item: "A", Weight: 1},
+		{Item: "B", Weight: 2},
+		{Item: "C", Weight: 1},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			SelfTest(NewAliasVoseMethod(nil, items...), 0)
+		})
+		assert.Panics(t, func() {
+			SelfTest[int](opaqueWeightedRandom{}, 10)
+		})
+	})
+	t.Run("unbiased", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		assert.NoError(t, SelfTest(NewAliasVoseMethod(r, items...), 100_000))
+		assert.NoError(t, SelfTest(NewAliasVoseMethodWithOptions(r, items, WithIntegerEngine()), 100_000))
+		assert.NoError(t, SelfTest(NewFloat64AliasMethod(r, []string{"A", "B", "C"}, []float64{0.25, 0.5, 0.25}), 100_000))
+	})
+	t.Run("biased", func(t *testing.T) {
+		err := SelfTest(NewAliasVoseMethod(stuckRand{}, items...), 1_000)
+		var biasErr *BiasError[string]
+		if assert.ErrorAs(t, err, &biasErr) {
+			assert.Equal(t, 1_000, biasErr.Iterations)
+			assert.Less(t, biasErr.PValue, 1e-6)
+			assert.InDelta(t, 500.0, biasErr.Expected["B"], 1e-9)
+			assert.ErrorContains(t, err, "after 1000 iterations")
+		}
+	})
+}
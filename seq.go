@@ -0,0 +1,37 @@
+package weightedrand
+
+import "iter"
+
+// NewFromSeq constructs a new WeightedRandom instance like
+// NewAliasVoseMethod, from a sequence of items and their weights. The items
+// are converted as they are yielded, so that a table can be built while
+// streaming items from a database cursor or a file decoder, without first
+// materializing them as WeightedItem values.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - seq:    A sequence of items and their associated weights.
+//
+// Panics:
+//   - If the sequence is empty or weights are negative.
+//
+// Example usage:
+//
+//	wr := NewFromSeq(randSource, maps.All(map[string]int{"A": 2, "B": 3}))
+func NewFromSeq[TItem any, TWeight Weight](random RandIntN, seq iter.Seq2[TItem, TWeight]) WeightedRandom[TItem] {
+	var items []weightedItem[TItem]
+	for item, weight := range seq {
+		items = append(items, weightedItem[TItem]{
+			Item:   item,
+			Weight: effectiveWeight(weight),
+		})
+	}
+	if len(items) == 0 {
+		panic(ErrNoItems.Error())
+	}
+	return newVoseAliasMethodFromDecimals(random, items)
+}
@@ -0,0 +1,43 @@
+package weightedrand_test
+
+import (
+	"iter"
+	"maps"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromSeq(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewFromSeq(nil, maps.All(map[MarbleColor]int{}))
+		})
+		assert.Panics(t, func() {
+			NewFromSeq(nil, maps.All(map[MarbleColor]int{Red: -1}))
+		})
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		// A sequence that can only be consumed once, like a database cursor.
+		var seq iter.Seq2[MarbleColor, int] = func(yield func(MarbleColor, int) bool) {
+			for _, item := range []WeightedItem[MarbleColor, int]{{Item: Red, Weight: 1}, {Item: Green, Weight: 0}, {Item: Blue, Weight: 8}} {
+				if !yield(item.Item, item.Weight) {
+					return
+				}
+			}
+		}
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewFromSeq(r, seq)
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.1, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.1, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.8, float64(counts[Blue])/iterations, tolerance, "%s", counts)
+	})
+}
@@ -0,0 +1,63 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// NextSet returns k selections from the WeightedRandom in one call, in the
+// order they were selected.
+//
+// With repeats allowed, the selections are a multiset: they are k
+// independent selections, exactly as k calls to Next, so the number of times
+// each item is selected follows a multinomial distribution of k trials with
+// the probabilities of the items.
+//
+// Without repeats, the selections are a set: they are made by successive
+// sampling without replacement, where the first item is selected by weight,
+// and every following item by weight among the items not yet selected. Items
+// are thus included more evenly than their weights, as heavy items cannot be
+// selected twice; only the first selection has the probabilities of Next.
+// If k is at least the number of distinct items, every item is returned.
+// The selections are drawn with float64 arithmetic like SampleK, using the
+// random number generator of the chooser.
+//
+// Panics:
+//   - If k is negative.
+//   - If repeats are not allowed, and the chooser was not constructed by
+//     this package, so that its distribution cannot be determined.
+//
+// Example usage:
+//
+//	recommendations := NextSet(catalog, 5, false)
+func NextSet[TItem comparable](wr WeightedRandom[TItem], k int, allowRepeats bool) []TItem {
+	if k < 0 {
+		panic(fmt.Sprintf("k must be non-negative value, but was %d", k))
+	} else if allowRepeats {
+		return NextN(wr, k)
+	}
+	d := distributionOf(wr)
+	// Merge the weights of each distinct item in the order they are first
+	// reported, so that the selections are reproducible.
+	var items []TItem
+	var weights []decimal.Decimal
+	indices := make(map[TItem]int)
+	for _, item := range d.weights() {
+		if index, ok := indices[item.Item]; ok {
+			weights[index] = weights[index].Add(item.Weight)
+			continue
+		}
+		indices[item.Item] = len(items)
+		items = append(items, item.Item)
+		weights = append(weights, item.Weight)
+	}
+	selected := SampleK(items, func(i int) decimal.Decimal {
+		return weights[i]
+	}, k, d.randomSource())
+	result := make([]TItem, len(selected))
+	for i, index := range selected {
+		result[i] = items[index]
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextSet(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 7},
+	}
+	t.Run("panic", func(t *testing.T) {
+		wr := NewAliasVoseMethod(nil, items...)
+		assert.Panics(t, func() {
+			NextSet(wr, -1, true)
+		})
+		assert.Panics(t, func() {
+			NextSet[int](opaqueWeightedRandom{}, 1, false)
+		})
+	})
+	t.Run("multiset", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		wr := NewAliasVoseMethod(r, items...)
+		selections := NextSet(wr, 1_000, true)
+		assert.Len(t, selections, 1_000)
+	})
+	t.Run("set", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		wr := NewAliasVoseMethod(r, items...)
+		assert.ElementsMatch(t, []MarbleColor{Red, Green, Blue}, NextSet(wr, 5, false))
+		assert.Empty(t, NextSet(wr, 0, false))
+		const iterations = 100_000
+		first := make(MarbleColorCounts)
+		included := make(MarbleColorCounts)
+		for range iterations {
+			selections := NextSet(wr, 2, false)
+			assert.Len(t, selections, 2)
+			assert.NotEqual(t, selections[0], selections[1])
+			first[selections[0]] += 1
+			for _, selection := range selections {
+				included[selection] += 1
+			}
+		}
+		assert.InDeltaf(t, 0.1, float64(first[Red])/iterations, tolerance, "%s", first)
+		assert.InDeltaf(t, 0.7, float64(first[Blue])/iterations, tolerance, "%s", first)
+		// Red is included when it is selected first, or second after Green
+		// or Blue: 0.1 + 0.2*(1/8) + 0.7*(1/3).
+		assert.InDeltaf(t, 0.1+0.2/8+0.7/3, float64(included[Red])/iterations, tolerance, "%s", included)
+	})
+}
@@ -0,0 +1,128 @@
+package weightedrand
+
+import (
+	"math"
+	"sync"
+)
+
+// ShadowReport describes how the selections of a candidate chooser diverge
+// from those of the live one it shadows.
+type ShadowReport[TItem comparable] struct {
+	// Samples is the number of selections made from each chooser.
+	Samples int
+	// Live is the number of times each item was selected by the live
+	// chooser.
+	Live map[TItem]int
+	// Candidate is the number of times each item was selected by the
+	// candidate chooser.
+	Candidate map[TItem]int
+	// TotalVariation is the total variation distance between the observed
+	// distributions of the choosers, from 0 when they are identical to 1
+	// when they share no items.
+	TotalVariation float64
+	// Statistic is the chi-square statistic of a test of homogeneity, of
+	// whether both choosers select from the same distribution.
+	Statistic float64
+	// PValue is the probability of a divergence at least as large, if both
+	// choosers did select from the same distribution. A small p-value, such
+	// as below 0.001, suggests the candidate changes the distribution.
+	PValue float64
+}
+
+// Shadow is a WeightedRandom that serves selections from a live chooser,
+// while also selecting from a candidate chooser to compare them, such as to
+// de-risk a change of weights or engine before cutting over to it. The
+// selections of the candidate are only counted, never served.
+//
+// Shadow is safe for concurrent use, given that the choosers are.
+type Shadow[TItem comparable] struct {
+	live      WeightedRandom[TItem]
+	candidate WeightedRandom[TItem]
+	mutex     sync.Mutex
+	samples   int
+	counts    map[TItem][2]int
+}
+
+// NewShadow constructs a new Shadow instance, serving from the live chooser
+// and shadowing it with the candidate.
+//
+// The function panics if either chooser is nil.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - live:      The WeightedRandom instance selections are served from.
+//   - candidate: The WeightedRandom instance compared to it.
+//
+// Example usage:
+//
+//	shadow := NewShadow(live, NewAliasVoseMethodWithOptions(randSource, proposedItems, WithIntegerEngine()))
+//	// ...serve traffic with shadow.Next()...
+//	if report := shadow.Report(); report.PValue < 0.001 {
+//		log.Printf("candidate diverges by %.2f%%", 100*report.TotalVariation)
+//	}
+func NewShadow[TItem comparable](live, candidate WeightedRandom[TItem]) *Shadow[TItem] {
+	if live == nil || candidate == nil {
+		panic("live and candidate choosers must not be nil")
+	}
+	return &Shadow[TItem]{
+		live:      live,
+		candidate: candidate,
+		counts:    make(map[TItem][2]int),
+	}
+}
+
+// Next selects an item from the live chooser, which is returned, and one
+// from the candidate, which is counted.
+func (shadow *Shadow[TItem]) Next() TItem {
+	item := shadow.live.Next()
+	shadowed := shadow.candidate.Next()
+	shadow.mutex.Lock()
+	defer shadow.mutex.Unlock()
+	shadow.samples++
+	counts := shadow.counts[item]
+	counts[0]++
+	shadow.counts[item] = counts
+	counts = shadow.counts[shadowed]
+	counts[1]++
+	shadow.counts[shadowed] = counts
+	return item
+}
+
+// Report returns the divergence of the selections since the Shadow was
+// constructed or last reset.
+func (shadow *Shadow[TItem]) Report() ShadowReport[TItem] {
+	shadow.mutex.Lock()
+	defer shadow.mutex.Unlock()
+	report := ShadowReport[TItem]{
+		Samples:   shadow.samples,
+		Live:      make(map[TItem]int, len(shadow.counts)),
+		Candidate: make(map[TItem]int, len(shadow.counts)),
+		PValue:    1,
+	}
+	if shadow.samples == 0 {
+		return report
+	}
+	difference := 0.0
+	for item, counts := range shadow.counts {
+		report.Live[item] = counts[0]
+		report.Candidate[item] = counts[1]
+		// With samples of equal size, the statistic of every item reduces
+		// to the squared difference of its counts over their sum.
+		delta := float64(counts[0] - counts[1])
+		difference += math.Abs(delta)
+		report.Statistic += delta * delta / float64(counts[0]+counts[1])
+	}
+	report.TotalVariation = difference / float64(2*shadow.samples)
+	report.PValue = chiSquarePValue(report.Statistic, len(shadow.counts)-1)
+	return report
+}
+
+// Reset discards the counted selections.
+func (shadow *Shadow[TItem]) Reset() {
+	shadow.mutex.Lock()
+	defer shadow.mutex.Unlock()
+	shadow.samples = 0
+	clear(shadow.counts)
+}
@@ -0,0 +1,59 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShadow(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 5},
+		{Item: Green, Weight: 3},
+		{Item: Blue, Weight: 2},
+	}
+	live := NewAliasVoseMethod(random, items...)
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewShadow(live, nil)
+		})
+	})
+	t.Run("empty", func(t *testing.T) {
+		report := NewShadow(live, live).Report()
+		assert.Zero(t, report.Samples)
+		assert.Equal(t, 1.0, report.PValue)
+	})
+	t.Run("same distribution", func(t *testing.T) {
+		candidate := NewAliasVoseMethodWithOptions(random, items, WithIntegerEngine())
+		shadow := NewShadow(live, candidate)
+		for range 10_000 {
+			shadow.Next()
+		}
+		report := shadow.Report()
+		assert.Equal(t, 10_000, report.Samples)
+		assert.Less(t, report.TotalVariation, 0.02)
+		assert.Greater(t, report.PValue, 0.001)
+		shadow.Reset()
+		assert.Zero(t, shadow.Report().Samples)
+	})
+	t.Run("diverging distribution", func(t *testing.T) {
+		candidate := NewAliasVoseMethod(random,
+			WeightedItem[MarbleColor, int]{Item: Red, Weight: 5},
+			WeightedItem[MarbleColor, int]{Item: Green, Weight: 5},
+		)
+		shadow := NewShadow(live, candidate)
+		counts := MarbleColorCounts{}
+		for range 10_000 {
+			counts[shadow.Next()]++
+		}
+		assert.Positive(t, counts[Blue])
+		report := shadow.Report()
+		assert.Equal(t, int(counts[Blue]), report.Live[Blue])
+		assert.Zero(t, report.Candidate[Blue])
+		assert.InDelta(t, 0.2, report.TotalVariation, 0.02)
+		assert.Less(t, report.PValue, 0.001)
+	})
+}
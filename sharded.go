@@ -0,0 +1,187 @@
+package weightedrand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/shopspring/decimal"
+)
+
+// Sharded is a WeightedRandom over items that are split into shards, each
+// with its own alias table, for catalogs too large to hold in a single
+// table. It first selects a shard by the total weight of its items, and then
+// an item from the shard, so that every item is selected in proportion to
+// its weight as if there were a single table. Shards are built in parallel,
+// and each can be reloaded on its own without rebuilding the others.
+//
+// Sharded is safe for concurrent use, given that the random number
+// generator is. Reloads do not block selections.
+type Sharded[TItem any, TWeight Weight] struct {
+	random  RandIntN
+	mutex   sync.Mutex
+	current atomic.Pointer[shardedTables[TItem]]
+}
+
+// shardedTables is an immutable snapshot of the tables of a Sharded.
+type shardedTables[TItem any] struct {
+	shards []voseAliasMethodRandom[TItem]
+	totals []decimal.Decimal
+	// top selects the index of a shard by its total weight. It is nil while
+	// every shard is empty.
+	top *voseAliasMethodRandom[int]
+}
+
+// NewSharded constructs a new Sharded instance, building the table of every
+// shard in parallel. Shards may be empty, and are then never selected.
+//
+// The function panics if every shard is empty, or if weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - shards: The WeightedItem values of every shard.
+//
+// Example usage:
+//
+//	s := NewSharded(randSource, [][]WeightedItem[SKU, int]{loadShard(0), loadShard(1), loadShard(2)})
+//	s.Reload(1, loadShard(1))
+func NewSharded[TItem any, TWeight Weight](random RandIntN, shards [][]WeightedItem[TItem, TWeight]) *Sharded[TItem, TWeight] {
+	sharded, err := NewShardedContext(context.Background(), random, shards)
+	if err != nil {
+		panic(err.Error())
+	}
+	return sharded
+}
+
+// NewShardedContext is like NewSharded, but returns an error rather than
+// panicking, and stops building every shard early when the context is done.
+//
+// Returns:
+//   - *Sharded[TItem, TWeight]: The Sharded instance.
+//   - error:                    If every shard is empty, weights are
+//     negative, or the context is done before every shard is built.
+//
+// Example usage:
+//
+//	s, err := NewShardedContext(ctx, randSource, shards)
+//	if err != nil {
+//		return err
+//	}
+func NewShardedContext[TItem any, TWeight Weight](ctx context.Context, random RandIntN, shards [][]WeightedItem[TItem, TWeight]) (*Sharded[TItem, TWeight], error) {
+	tables := &shardedTables[TItem]{
+		shards: make([]voseAliasMethodRandom[TItem], len(shards)),
+		totals: make([]decimal.Decimal, len(shards)),
+	}
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for index, items := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tables.shards[index], tables.totals[index], errs[index] = buildShard(ctx, items)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for index, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("shard %d: %w", index, err)
+		}
+	}
+	tables.buildTop()
+	if tables.top == nil {
+		return nil, errors.New("at least one non-empty shard must be provided")
+	}
+	sharded := &Sharded[TItem, TWeight]{
+		random: random,
+	}
+	sharded.current.Store(tables)
+	return sharded, nil
+}
+
+// Next selects a shard by its total weight, and then an item from it.
+//
+// Panics:
+//   - If every shard was emptied by Reload.
+func (sharded *Sharded[TItem, TWeight]) Next() TItem {
+	tables := sharded.current.Load()
+	if tables.top == nil {
+		panic("every shard is empty")
+	}
+	shard := tables.top.NextUsing(sharded.random)
+	return tables.shards[shard].NextUsing(sharded.random)
+}
+
+// Reload replaces the items of the shard, and rebuilds only its table and
+// the selection between shards. The items may be empty, which empties the
+// shard.
+//
+// Panics:
+//   - If the index is not that of a shard, or if weights are negative.
+func (sharded *Sharded[TItem, TWeight]) Reload(index int, items []WeightedItem[TItem, TWeight]) {
+	shard, total, err := buildShard(context.Background(), items)
+	if err != nil {
+		panic(fmt.Sprintf("shard %d: %s", index, err.Error()))
+	}
+	sharded.mutex.Lock()
+	defer sharded.mutex.Unlock()
+	previous := sharded.current.Load()
+	if index < 0 || index >= len(previous.shards) {
+		panic(fmt.Sprintf("index must be within [0, %d), but was %d", len(previous.shards), index))
+	}
+	tables := &shardedTables[TItem]{
+		shards: append([]voseAliasMethodRandom[TItem]{}, previous.shards...),
+		totals: append([]decimal.Decimal{}, previous.totals...),
+	}
+	tables.shards[index], tables.totals[index] = shard, total
+	tables.buildTop()
+	sharded.current.Store(tables)
+}
+
+// Len returns the number of shards.
+func (sharded *Sharded[TItem, TWeight]) Len() int {
+	return len(sharded.current.Load().shards)
+}
+
+// buildShard builds the table of the shard, and sums its total weight. An
+// empty shard has a total weight of zero.
+func buildShard[TItem any, TWeight Weight](ctx context.Context, items []WeightedItem[TItem, TWeight]) (voseAliasMethodRandom[TItem], decimal.Decimal, error) {
+	if len(items) == 0 {
+		return voseAliasMethodRandom[TItem]{}, decimal.Zero, nil
+	}
+	table, err := buildVoseAliasMethodContext[TItem](buildContext{ctx: ctx}, nil, items)
+	if err != nil {
+		return voseAliasMethodRandom[TItem]{}, decimal.Zero, err
+	}
+	total := decimal.Zero
+	for _, item := range items {
+		total = total.Add(effectiveWeight(item.Weight))
+	}
+	return table, total, nil
+}
+
+// buildTop builds the selection between the non-empty shards.
+func (tables *shardedTables[TItem]) buildTop() {
+	shards := make([]weightedItem[int], 0, len(tables.shards))
+	for index, total := range tables.totals {
+		if total.IsPositive() {
+			shards = append(shards, weightedItem[int]{
+				Item:   index,
+				Weight: total,
+			})
+		}
+	}
+	tables.top = nil
+	if len(shards) > 0 {
+		top := newVoseAliasMethodFromDecimals[int](nil, shards)
+		tables.top = &top
+	}
+}
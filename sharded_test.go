@@ -0,0 +1,68 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharded(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSharded[MarbleColor, int](nil, [][]WeightedItem[MarbleColor, int]{{}, {}})
+		})
+		assert.PanicsWithValue(t, "shard 1: weight of item 0 must be non-negative value, but was -1", func() {
+			NewSharded(nil, [][]WeightedItem[MarbleColor, int]{{{Item: Red}}, {{Item: Blue, Weight: -1}}})
+		})
+		s := NewSharded(nil, [][]WeightedItem[MarbleColor, int]{{{Item: Red}}})
+		assert.Panics(t, func() {
+			s.Reload(1, nil)
+		})
+		s.Reload(0, nil)
+		assert.Panics(t, func() {
+			s.Next()
+		})
+	})
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewShardedContext(context.Background(), nil, [][]WeightedItem[MarbleColor, int]{{{Item: Red}}, {{Item: Blue, Weight: -1}}})
+		var negativeErr *ErrNegativeWeight
+		assert.ErrorAs(t, err, &negativeErr)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = NewShardedContext(ctx, nil, [][]WeightedItem[MarbleColor, int]{{{Item: Red}}, {{Item: Blue}}})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("items with weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		s := NewSharded(r, [][]WeightedItem[MarbleColor, int]{
+			{{Item: Red, Weight: 1}, {Item: Orange, Weight: 3}},
+			{},
+			{{Item: Yellow, Weight: 6}},
+		})
+		assert.Equal(t, 3, s.Len())
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[s.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.1, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.3, float64(counts[Orange])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.6, float64(counts[Yellow])/iterations, tolerance, "%s", counts)
+		t.Run("reload", func(t *testing.T) {
+			s.Reload(1, []WeightedItem[MarbleColor, int]{{Item: Green, Weight: 10}})
+			s.Reload(2, nil)
+			counts := make(MarbleColorCounts)
+			for range iterations {
+				counts[s.Next()] += 1
+			}
+			assert.Zero(t, counts[Yellow])
+			assert.InDeltaf(t, 1.0/14, float64(counts[Red])/iterations, tolerance, "%s", counts)
+			assert.InDeltaf(t, 3.0/14, float64(counts[Orange])/iterations, tolerance, "%s", counts)
+			assert.InDeltaf(t, 10.0/14, float64(counts[Green])/iterations, tolerance, "%s", counts)
+		})
+	})
+}
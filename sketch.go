@@ -0,0 +1,206 @@
+package weightedrand
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// SketchEntry is an item tracked by a Sketch, with its estimated weight.
+type SketchEntry[TItem any] struct {
+	// Item is the tracked item.
+	Item TItem
+	// Weight is the estimated total weight of the item, which never
+	// underestimates its true total weight.
+	Weight decimal.Decimal
+	// Error bounds how much Weight may overestimate the true total weight,
+	// which is at least Weight minus Error.
+	Error decimal.Decimal
+}
+
+// Sketch samples items approximately in proportion to their total weight
+// over a stream of weight increments, such as the traffic of millions of
+// keys, with bounded memory. It tracks the heaviest items with the weighted
+// Space-Saving algorithm: when a new item arrives and every counter is in
+// use, the item takes over the counter of the lightest tracked item,
+// inheriting its weight as the error of its estimate. Heavy hitters are
+// therefore always tracked, and items are selected in proportion to their
+// estimated weights, while the long tail is only represented by whichever
+// of its items hold counters at the time.
+//
+// Changes are applied by rebuilding the alias table on the next selection,
+// so a burst of increments only costs a single rebuild.
+//
+// Sketch is safe for concurrent use, given that the random number generator
+// is.
+type Sketch[TItem comparable, TWeight Weight] struct {
+	mutex    sync.Mutex
+	random   RandIntN
+	capacity int
+	counters sketchHeap[TItem]
+	tracked  map[TItem]*sketchCounter[TItem]
+	table    *voseAliasMethodRandom[TItem]
+}
+
+// NewSketch constructs a new, empty Sketch that tracks at most capacity
+// items.
+//
+// The function panics if the capacity is not positive.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each increment.
+//
+// Parameters:
+//   - random:   A RandIntN implementation used for random number generation.
+//   - capacity: The maximum number of items tracked at once.
+//
+// Example usage:
+//
+//	sketch := NewSketch[string, int64](randSource, 10_000)
+//	for request := range requests {
+//		sketch.Observe(request.Path, request.Bytes)
+//	}
+//	path := sketch.Next()
+func NewSketch[TItem comparable, TWeight Weight](random RandIntN, capacity int) *Sketch[TItem, TWeight] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("capacity must be positive, but was %d", capacity))
+	}
+	return &Sketch[TItem, TWeight]{
+		random:   random,
+		capacity: capacity,
+		counters: make(sketchHeap[TItem], 0, capacity),
+		tracked:  make(map[TItem]*sketchCounter[TItem], capacity),
+	}
+}
+
+// Observe adds the weight to the total weight of the item. If no weight is
+// provided, it is assumed to be 1.
+//
+// Panics:
+//   - If the weight is negative.
+func (sketch *Sketch[TItem, TWeight]) Observe(item TItem, weight TWeight) {
+	increment := effectiveWeight(weight)
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+	sketch.table = nil
+	if counter, ok := sketch.tracked[item]; ok {
+		counter.weight = counter.weight.Add(increment)
+		heap.Fix(&sketch.counters, counter.index)
+		return
+	}
+	if len(sketch.counters) < sketch.capacity {
+		counter := &sketchCounter[TItem]{item: item, weight: increment}
+		sketch.tracked[item] = counter
+		heap.Push(&sketch.counters, counter)
+		return
+	}
+	// Take over the counter of the lightest item.
+	counter := sketch.counters[0]
+	delete(sketch.tracked, counter.item)
+	sketch.tracked[item] = counter
+	counter.item = item
+	counter.err = counter.weight
+	counter.weight = counter.weight.Add(increment)
+	heap.Fix(&sketch.counters, 0)
+}
+
+// Len returns the number of items tracked.
+func (sketch *Sketch[TItem, TWeight]) Len() int {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+	return len(sketch.counters)
+}
+
+// Top returns the n heaviest tracked items, ordered by their estimated
+// weights, heaviest first. If n is not positive, every tracked item is
+// returned.
+func (sketch *Sketch[TItem, TWeight]) Top(n int) []SketchEntry[TItem] {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+	entries := make([]SketchEntry[TItem], len(sketch.counters))
+	for i, counter := range sketch.counters {
+		entries[i] = SketchEntry[TItem]{
+			Item:   counter.item,
+			Weight: counter.weight,
+			Error:  counter.err,
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b SketchEntry[TItem]) int {
+		return b.Weight.Cmp(a.Weight)
+	})
+	if n > 0 && n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// Next selects a tracked item in proportion to its estimated weight.
+//
+// Panics:
+//   - If no items were observed.
+func (sketch *Sketch[TItem, TWeight]) Next() TItem {
+	item, ok := sketch.TryNext()
+	if !ok {
+		panic("there are no items to select from")
+	}
+	return item
+}
+
+// TryNext selects a tracked item in proportion to its estimated weight.
+// When no items were observed, it returns the zero value and false.
+func (sketch *Sketch[TItem, TWeight]) TryNext() (TItem, bool) {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+	if len(sketch.counters) == 0 {
+		var zero TItem
+		return zero, false
+	}
+	if sketch.table == nil {
+		items := make([]weightedItem[TItem], len(sketch.counters))
+		for i, counter := range sketch.counters {
+			items[i] = weightedItem[TItem]{
+				Item:   counter.item,
+				Weight: counter.weight,
+			}
+		}
+		table := newVoseAliasMethodFromDecimals(sketch.random, items)
+		sketch.table = &table
+	}
+	return sketch.table.Next(), true
+}
+
+type sketchCounter[TItem any] struct {
+	item   TItem
+	weight decimal.Decimal
+	err    decimal.Decimal
+	// index is the index of the counter within the heap.
+	index int
+}
+
+// sketchHeap is a min-heap of counters by their weight.
+type sketchHeap[TItem any] []*sketchCounter[TItem]
+
+func (h sketchHeap[TItem]) Len() int           { return len(h) }
+func (h sketchHeap[TItem]) Less(i, j int) bool { return h[i].weight.LessThan(h[j].weight) }
+
+func (h sketchHeap[TItem]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *sketchHeap[TItem]) Push(x any) {
+	counter := x.(*sketchCounter[TItem])
+	counter.index = len(*h)
+	*h = append(*h, counter)
+}
+
+func (h *sketchHeap[TItem]) Pop() any {
+	old := *h
+	counter := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return counter
+}
@@ -0,0 +1,70 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSketch(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "capacity must be positive, but was 0", func() {
+			NewSketch[MarbleColor, int](nil, 0)
+		})
+		sketch := NewSketch[MarbleColor, int](nil, 1)
+		assert.PanicsWithValue(t, "there are no items to select from", func() {
+			sketch.Next()
+		})
+		assert.Panics(t, func() {
+			sketch.Observe(Red, -1)
+		})
+		color, ok := sketch.TryNext()
+		assert.False(t, ok)
+		assert.Zero(t, color)
+	})
+	t.Run("exact within capacity", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		sketch := NewSketch[MarbleColor, int](r, 3)
+		sketch.Observe(Red, 1)
+		sketch.Observe(Blue, 2)
+		sketch.Observe(Blue, 1)
+		assert.Equal(t, 2, sketch.Len())
+		top := sketch.Top(0)
+		if assert.Len(t, top, 2) {
+			assert.Equal(t, Blue, top[0].Item)
+			assert.Equal(t, "3", top[0].Weight.String())
+			assert.True(t, top[0].Error.IsZero())
+		}
+		counts := Counts[MarbleColor](sketch, 100_000)
+		assert.InDeltaf(t, 0.75, float64(counts[Blue])/100_000, tolerance, "%v", counts)
+	})
+	t.Run("heavy hitters", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		sketch := NewSketch[int, int](r, 10)
+		// Two heavy hitters among a long tail of 10,000 light items.
+		for i := range 10_000 {
+			sketch.Observe(-1, 3)
+			sketch.Observe(-2, 1)
+			sketch.Observe(i, 1)
+		}
+		assert.Equal(t, 10, sketch.Len())
+		top := sketch.Top(2)
+		if assert.Len(t, top, 2) {
+			assert.Equal(t, -1, top[0].Item)
+			assert.Equal(t, "30000", top[0].Weight.String())
+			assert.Equal(t, -2, top[1].Item)
+			assert.Equal(t, "10000", top[1].Weight.String())
+		}
+		counts := map[int]int{}
+		for range 100_000 {
+			counts[sketch.Next()]++
+		}
+		// The counters always sum to the total weight, so the 8 counters of
+		// the tail together estimate its whole weight.
+		assert.InDeltaf(t, 0.6, float64(counts[-1])/100_000, tolerance, "%v", counts)
+		assert.Greater(t, counts[-1], 2*counts[-2])
+	})
+}
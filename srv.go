@@ -0,0 +1,105 @@
+package weightedrand
+
+import (
+	"cmp"
+	"net"
+	"slices"
+)
+
+// SRVSelector selects endpoints following the weighting semantics of DNS SRV
+// records (RFC 2782): records of the lowest priority value are always
+// preferred, and records within the same priority are selected in
+// proportion to their weight. Records with a weight of zero are only
+// selected with a very small chance, unless every record of the priority
+// has a weight of zero, in which case they are selected uniformly.
+//
+// Records do not need to come from a DNS lookup; a static list of endpoints
+// can be expressed as net.SRV values as well.
+type SRVSelector struct {
+	random RandIntN
+	groups [][]*net.SRV
+}
+
+// NewSRVSelector constructs a new SRVSelector from the records, such as those
+// returned by net.LookupSRV. The records are not modified.
+//
+// The function panics if no records are provided.
+//
+// Example usage:
+//
+//	_, records, err := net.LookupSRV("xmpp-server", "tcp", "example.com")
+//	if err != nil {
+//		return err
+//	}
+//	target := NewSRVSelector(randSource, records...).Next()
+func NewSRVSelector(random RandIntN, records ...*net.SRV) *SRVSelector {
+	if len(records) == 0 {
+		panic("at least one record must be provided")
+	}
+	sorted := slices.Clone(records)
+	slices.SortStableFunc(sorted, func(a, b *net.SRV) int {
+		return cmp.Compare(a.Priority, b.Priority)
+	})
+	selector := &SRVSelector{
+		random: random,
+	}
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && sorted[end].Priority == sorted[start].Priority {
+			end++
+		}
+		selector.groups = append(selector.groups, sorted[start:end])
+		start = end
+	}
+	return selector
+}
+
+// Next selects a record from the records with the lowest priority value.
+func (selector *SRVSelector) Next() *net.SRV {
+	group := selector.groups[0]
+	return group[selector.pick(group)]
+}
+
+// Order returns every record in the order they should be attempted: by
+// ascending priority value, and by a weighted shuffle within each priority.
+func (selector *SRVSelector) Order() []*net.SRV {
+	result := make([]*net.SRV, 0, len(selector.groups)*2)
+	for _, group := range selector.groups {
+		remaining := slices.Clone(group)
+		for len(remaining) > 0 {
+			index := selector.pick(remaining)
+			result = append(result, remaining[index])
+			remaining = slices.Delete(remaining, index, index+1)
+		}
+	}
+	return result
+}
+
+// pick selects the index of a record within a single priority, using the
+// running sum algorithm of RFC 2782 where records with a weight of zero are
+// placed first.
+func (selector *SRVSelector) pick(group []*net.SRV) int {
+	sum := 0
+	for _, record := range group {
+		sum += int(record.Weight)
+	}
+	if sum == 0 {
+		return selector.random.Intn(len(group))
+	}
+	threshold := selector.random.Intn(sum + 1)
+	running := 0
+	// Zero weights come first, so that they are only selected when the
+	// threshold is exactly zero.
+	for index, record := range group {
+		if record.Weight == 0 && threshold == 0 {
+			return index
+		}
+	}
+	for index, record := range group {
+		running += int(record.Weight)
+		if record.Weight > 0 && running >= threshold {
+			return index
+		}
+	}
+	return len(group) - 1
+}
@@ -0,0 +1,61 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSRVSelector(t *testing.T) {
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSRVSelector(nil)
+		})
+	})
+	records := []*net.SRV{
+		{Target: "backup.example.com.", Priority: 20, Weight: 1},
+		{Target: "a.example.com.", Priority: 10, Weight: 60},
+		{Target: "b.example.com.", Priority: 10, Weight: 20},
+		{Target: "c.example.com.", Priority: 10, Weight: 0},
+	}
+	t.Run("next prefers the lowest priority by weight", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		selector := NewSRVSelector(r, records...)
+		const iterations = 100_000
+		counts := make(map[string]int)
+		for range iterations {
+			counts[selector.Next().Target] += 1
+		}
+		assert.Zero(t, counts["backup.example.com."])
+		assert.InDeltaf(t, 60.0/81, float64(counts["a.example.com."])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 20.0/81, float64(counts["b.example.com."])/iterations, tolerance, "%v", counts)
+		// A weight of zero is only selected when the running sum threshold is
+		// exactly zero, which is 1 in (sum + 1).
+		assert.InDeltaf(t, 1.0/81, float64(counts["c.example.com."])/iterations, 0.005, "%v", counts)
+	})
+	t.Run("order", func(t *testing.T) {
+		selector := NewSRVSelector(rand.New(rand.NewSource(time.Now().Unix())), records...)
+		for range 100 {
+			order := selector.Order()
+			require.Len(t, order, len(records))
+			assert.ElementsMatch(t, records, order)
+			assert.Equal(t, "backup.example.com.", order[3].Target)
+		}
+	})
+	t.Run("all zero weights are uniform", func(t *testing.T) {
+		selector := NewSRVSelector(rand.New(rand.NewSource(time.Now().Unix())),
+			&net.SRV{Target: "a", Weight: 0},
+			&net.SRV{Target: "b", Weight: 0},
+		)
+		counts := make(map[string]int)
+		for range 10_000 {
+			counts[selector.Next().Target] += 1
+		}
+		assert.InDeltaf(t, 0.5, float64(counts["a"])/10_000, tolerance, "%v", counts)
+	})
+}
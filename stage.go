@@ -0,0 +1,116 @@
+package weightedrand
+
+import (
+	"sync"
+)
+
+// stageState is the progress of a Staged table through its two phases.
+type stageState int
+
+const (
+	stagePending stageState = iota
+	stageCommitted
+	stageRolledBack
+)
+
+// Staged is a table staged by Stage to replace the current WeightedRandom of
+// a Swappable, which is not in service until it is committed. Its
+// selections can be sampled in the meantime, such as with NextN, to verify
+// the new weights before they go live.
+//
+// Staged is safe for concurrent use, given that the random number generator
+// is.
+type Staged[TItem any] struct {
+	swappable *Swappable[TItem]
+	mutex     sync.Mutex
+	state     stageState
+	// previous is the WeightedRandom in service when the table was staged,
+	// and staged the table itself once committed.
+	previous *WeightedRandom[TItem]
+	staged   *WeightedRandom[TItem]
+}
+
+// Stage builds a new table from the items, to replace the current
+// WeightedRandom of the Swappable once it is committed. Building the table
+// validates the items, so that invalid weights are rejected before they go
+// live, rather than panicking.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - swappable: The Swappable whose WeightedRandom is replaced on commit.
+//   - random:    A RandIntN implementation used for random number generation.
+//   - items:     A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Returns:
+//   - *Staged[TItem]: The staged table.
+//   - error:          If no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	staged, err := Stage(live, randSource, pushedItems...)
+//	if err != nil {
+//		return err
+//	}
+//	if !acceptable(NextN[string](staged, 10_000)) {
+//		return staged.Rollback()
+//	}
+//	return staged.Commit()
+func Stage[TItem any, TWeight Weight](swappable *Swappable[TItem], random RandIntN, items ...WeightedItem[TItem, TWeight]) (*Staged[TItem], error) {
+	aliasMethod, err := buildVoseAliasMethod(random, items)
+	if err != nil {
+		return nil, err
+	}
+	staged := WeightedRandom[TItem](aliasMethod)
+	return &Staged[TItem]{
+		swappable: swappable,
+		previous:  swappable.current.Load(),
+		staged:    &staged,
+	}, nil
+}
+
+// Next selects an item from the staged table, without affecting the
+// selections of the Swappable.
+func (staged *Staged[TItem]) Next() TItem {
+	return (*staged.staged).Next()
+}
+
+// Commit atomically puts the staged table into service.
+//
+// Returns ErrSwapConflict if the Swappable was swapped since the table was
+// staged, in which case the table is still staged and may be rolled back,
+// or ErrStageFinished if it was already committed or rolled back.
+func (staged *Staged[TItem]) Commit() error {
+	staged.mutex.Lock()
+	defer staged.mutex.Unlock()
+	if staged.state != stagePending {
+		return ErrStageFinished
+	} else if !staged.swappable.current.CompareAndSwap(staged.previous, staged.staged) {
+		return ErrSwapConflict
+	}
+	staged.state = stageCommitted
+	return nil
+}
+
+// Rollback discards the staged table if it was not committed, or puts the
+// WeightedRandom it replaced back into service if it was.
+//
+// Returns ErrSwapConflict if the Swappable was swapped since the table was
+// committed, in which case the change is not undone, or ErrStageFinished
+// if it was already rolled back.
+func (staged *Staged[TItem]) Rollback() error {
+	staged.mutex.Lock()
+	defer staged.mutex.Unlock()
+	switch staged.state {
+	case stageRolledBack:
+		return ErrStageFinished
+	case stageCommitted:
+		if !staged.swappable.current.CompareAndSwap(staged.staged, staged.previous) {
+			return ErrSwapConflict
+		}
+	}
+	staged.state = stageRolledBack
+	return nil
+}
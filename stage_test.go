@@ -0,0 +1,61 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStage(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	red := NewAliasVoseMethod(random, WeightedItem[MarbleColor, int]{Item: Red})
+	blue := NewAliasVoseMethod(random, WeightedItem[MarbleColor, int]{Item: Blue})
+	t.Run("invalid", func(t *testing.T) {
+		s := NewSwappable(red)
+		_, err := Stage[MarbleColor, int](s, random)
+		assert.ErrorIs(t, err, ErrNoItems)
+		_, err = Stage(s, random, WeightedItem[MarbleColor, int]{Item: Blue, Weight: -1})
+		var negative *ErrNegativeWeight
+		assert.ErrorAs(t, err, &negative)
+		assert.Equal(t, Red, s.Next())
+	})
+	t.Run("commit", func(t *testing.T) {
+		s := NewSwappable(red)
+		staged, err := Stage(s, random, WeightedItem[MarbleColor, int]{Item: Green})
+		require.NoError(t, err)
+		assert.Equal(t, []MarbleColor{Green, Green}, NextN[MarbleColor](staged, 2))
+		assert.Equal(t, Red, s.Next())
+		require.NoError(t, staged.Commit())
+		assert.Equal(t, Green, s.Next())
+		assert.ErrorIs(t, staged.Commit(), ErrStageFinished)
+		require.NoError(t, staged.Rollback())
+		assert.Equal(t, Red, s.Next())
+		assert.ErrorIs(t, staged.Rollback(), ErrStageFinished)
+	})
+	t.Run("rollback", func(t *testing.T) {
+		s := NewSwappable(red)
+		staged, err := Stage(s, random, WeightedItem[MarbleColor, int]{Item: Green})
+		require.NoError(t, err)
+		require.NoError(t, staged.Rollback())
+		assert.ErrorIs(t, staged.Commit(), ErrStageFinished)
+		assert.Equal(t, Red, s.Next())
+	})
+	t.Run("conflict", func(t *testing.T) {
+		s := NewSwappable(red)
+		staged, err := Stage(s, random, WeightedItem[MarbleColor, int]{Item: Green})
+		require.NoError(t, err)
+		s.Swap(blue)
+		assert.ErrorIs(t, staged.Commit(), ErrSwapConflict)
+		assert.Equal(t, Blue, s.Next())
+
+		staged, err = Stage(s, random, WeightedItem[MarbleColor, int]{Item: Green})
+		require.NoError(t, err)
+		require.NoError(t, staged.Commit())
+		s.Swap(red)
+		assert.ErrorIs(t, staged.Rollback(), ErrSwapConflict)
+		assert.Equal(t, Red, s.Next())
+	})
+}
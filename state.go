@@ -0,0 +1,221 @@
+package weightedrand
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The choosers that accumulate state, such as the cooldowns of Cooldown or
+// the budgets of Budgeted, serialize it to JSON, so that it survives
+// restarts and can be migrated between instances. Their configuration, such
+// as the random number generator, clock, items and limits, is not
+// serialized: the state is restored into an instance constructed with the
+// same configuration. Items are encoded as they are by encoding/json.
+
+// dynamicState is the serialized form of an item of Dynamic.
+type dynamicState[TItem any] struct {
+	Item   TItem           `json:"item"`
+	Weight decimal.Decimal `json:"weight"`
+}
+
+// MarshalJSON encodes the items of the Dynamic with their current weights.
+func (dynamic *Dynamic[TItem, TWeight]) MarshalJSON() ([]byte, error) {
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	states := make([]dynamicState[TItem], len(dynamic.items))
+	for i, item := range dynamic.items {
+		states[i] = dynamicState[TItem]{
+			Item:   item.Item,
+			Weight: item.Weight,
+		}
+	}
+	return json.Marshal(states)
+}
+
+// UnmarshalJSON replaces the items of the Dynamic with those encoded by
+// MarshalJSON, which may be none.
+func (dynamic *Dynamic[TItem, TWeight]) UnmarshalJSON(data []byte) error {
+	var states []dynamicState[TItem]
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	items := make([]weightedItem[TItem], 0, len(states))
+	indices := make(map[TItem]int, len(states))
+	for index, state := range states {
+		weight, err := decimalWeight(index, state.Weight)
+		if err != nil {
+			return err
+		} else if _, ok := indices[state.Item]; ok {
+			return fmt.Errorf("item %v is duplicated", state.Item)
+		}
+		indices[state.Item] = len(items)
+		items = append(items, weightedItem[TItem]{
+			Item:   state.Item,
+			Weight: weight,
+		})
+	}
+	dynamic.mutex.Lock()
+	defer dynamic.mutex.Unlock()
+	dynamic.items, dynamic.indices, dynamic.table = items, indices, nil
+	dynamic.added.Broadcast()
+	return nil
+}
+
+// cooldownState is the serialized form of an item cooling down.
+type cooldownState[TItem any] struct {
+	Item  TItem     `json:"item"`
+	Until time.Time `json:"until"`
+}
+
+// MarshalJSON encodes the items that are cooling down, with the time their
+// cooldown expires.
+func (cooldown *Cooldown[TItem]) MarshalJSON() ([]byte, error) {
+	cooldown.mutex.Lock()
+	defer cooldown.mutex.Unlock()
+	states := make([]cooldownState[TItem], 0, len(cooldown.expiries))
+	for item, expiry := range cooldown.expiries {
+		states = append(states, cooldownState[TItem]{
+			Item:  item,
+			Until: expiry,
+		})
+	}
+	return json.Marshal(states)
+}
+
+// UnmarshalJSON replaces the cooldowns of the Cooldown with those encoded
+// by MarshalJSON. Every item must be one of the items of the Cooldown.
+func (cooldown *Cooldown[TItem]) UnmarshalJSON(data []byte) error {
+	var states []cooldownState[TItem]
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	cooldown.mutex.Lock()
+	defer cooldown.mutex.Unlock()
+	if err := knownItems(cooldown.indices, states, func(state cooldownState[TItem]) TItem { return state.Item }); err != nil {
+		return err
+	}
+	for item := range cooldown.expiries {
+		for _, index := range cooldown.indices[item] {
+			cooldown.table.restore(index)
+		}
+	}
+	clear(cooldown.expiries)
+	for _, state := range states {
+		cooldown.expiries[state.Item] = state.Until
+		for _, index := range cooldown.indices[state.Item] {
+			cooldown.table.exclude(index)
+		}
+	}
+	return nil
+}
+
+// budgetState is the serialized form of the budget of an item.
+type budgetState[TItem any] struct {
+	Item      TItem           `json:"item"`
+	Remaining decimal.Decimal `json:"remaining"`
+}
+
+// MarshalJSON encodes the remaining budgets of the items that have one.
+func (budgeted *Budgeted[TItem]) MarshalJSON() ([]byte, error) {
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	states := make([]budgetState[TItem], 0, len(budgeted.remaining))
+	for item, remaining := range budgeted.remaining {
+		states = append(states, budgetState[TItem]{
+			Item:      item,
+			Remaining: remaining,
+		})
+	}
+	return json.Marshal(states)
+}
+
+// UnmarshalJSON replaces the budgets of the Budgeted with those encoded by
+// MarshalJSON, leaving the other items unlimited. Every item must be one of
+// the items of the Budgeted.
+func (budgeted *Budgeted[TItem]) UnmarshalJSON(data []byte) error {
+	var states []budgetState[TItem]
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	budgeted.mutex.Lock()
+	defer budgeted.mutex.Unlock()
+	if err := knownItems(budgeted.indices, states, func(state budgetState[TItem]) TItem { return state.Item }); err != nil {
+		return err
+	}
+	previous := budgeted.remaining
+	budgeted.remaining = make(map[TItem]decimal.Decimal, len(states))
+	for item := range previous {
+		budgeted.refresh(item)
+	}
+	for _, state := range states {
+		budgeted.remaining[state.Item] = state.Remaining
+		budgeted.refresh(state.Item)
+	}
+	return nil
+}
+
+// frequencyState is the serialized form of the selections of a capped item.
+type frequencyState[TItem any] struct {
+	Item       TItem       `json:"item"`
+	Selections []time.Time `json:"selections"`
+}
+
+// MarshalJSON encodes the times of the selections of the capped items
+// within their current windows.
+func (frequencyCap *FrequencyCap[TItem]) MarshalJSON() ([]byte, error) {
+	frequencyCap.mutex.Lock()
+	defer frequencyCap.mutex.Unlock()
+	now := frequencyCap.clock.Now()
+	states := make([]frequencyState[TItem], 0, len(frequencyCap.selections))
+	for item := range frequencyCap.caps {
+		frequencyCap.refresh(item, now)
+		if selections := frequencyCap.selections[item]; len(selections) > 0 {
+			states = append(states, frequencyState[TItem]{
+				Item:       item,
+				Selections: selections,
+			})
+		}
+	}
+	return json.Marshal(states)
+}
+
+// UnmarshalJSON replaces the selections of the FrequencyCap with those
+// encoded by MarshalJSON. Every item must be one of the items of the
+// FrequencyCap, and selections of items without a cap are discarded.
+func (frequencyCap *FrequencyCap[TItem]) UnmarshalJSON(data []byte) error {
+	var states []frequencyState[TItem]
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	frequencyCap.mutex.Lock()
+	defer frequencyCap.mutex.Unlock()
+	if err := knownItems(frequencyCap.indices, states, func(state frequencyState[TItem]) TItem { return state.Item }); err != nil {
+		return err
+	}
+	clear(frequencyCap.selections)
+	for _, state := range states {
+		if _, ok := frequencyCap.caps[state.Item]; ok {
+			frequencyCap.selections[state.Item] = slices.SortedFunc(slices.Values(state.Selections), time.Time.Compare)
+		}
+	}
+	now := frequencyCap.clock.Now()
+	for item := range frequencyCap.caps {
+		frequencyCap.refresh(item, now)
+	}
+	return nil
+}
+
+// knownItems returns an error if the item of any of the states is not one
+// of the indexed items.
+func knownItems[TItem comparable, TState any](indices map[TItem][]int, states []TState, item func(TState) TItem) error {
+	for _, state := range states {
+		if _, ok := indices[item(state)]; !ok {
+			return fmt.Errorf("item %v is not one of the items", item(state))
+		}
+	}
+	return nil
+}
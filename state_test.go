@@ -0,0 +1,104 @@
+package weightedrand_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestState(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Blue, Weight: 1_000},
+	}
+	t.Run("dynamic", func(t *testing.T) {
+		d := NewDynamic(rand.New(rand.NewSource(1)), items)
+		d.Add(Green, 5)
+		d.Remove(Red)
+		data, err := json.Marshal(d)
+		require.NoError(t, err)
+
+		restored := NewDynamic[MarbleColor, int](rand.New(rand.NewSource(1)), nil)
+		require.NoError(t, json.Unmarshal(data, restored))
+		assert.Equal(t, 2, restored.Len())
+		counts := Counts[MarbleColor](restored, 10_000)
+		assert.Zero(t, counts[Red])
+		assert.Positive(t, counts[Green])
+
+		assert.Error(t, json.Unmarshal([]byte(`[{"item":"RED","weight":"-1"}]`), restored))
+		assert.Error(t, json.Unmarshal([]byte(`[{"item":"RED","weight":"1"},{"item":"RED","weight":"2"}]`), restored))
+		assert.Equal(t, 2, restored.Len())
+	})
+	t.Run("cooldown", func(t *testing.T) {
+		clock := NewFixtureClock()
+		c := NewCooldown(rand.New(rand.NewSource(1)), clock, items...).
+			WithCooldown(Blue, time.Minute)
+		for c.Next() != Blue {
+		}
+		data, err := json.Marshal(c)
+		require.NoError(t, err)
+
+		restored := NewCooldown(rand.New(rand.NewSource(1)), clock, items...).
+			WithCooldown(Blue, time.Minute)
+		require.NoError(t, json.Unmarshal(data, restored))
+		expiry, ok := restored.CoolingDown(Blue)
+		assert.True(t, ok)
+		assert.Equal(t, clock.Now().Add(time.Minute), expiry)
+		for range 100 {
+			assert.Equal(t, Red, restored.Next())
+		}
+		clock.Advance(time.Minute)
+		counts := Counts[MarbleColor](restored, 100)
+		assert.Positive(t, counts[Blue])
+
+		assert.Error(t, json.Unmarshal([]byte(`[{"item":"GREEN","until":"2024-01-01T00:00:00Z"}]`), restored))
+	})
+	t.Run("budgeted", func(t *testing.T) {
+		b := NewBudgeted(rand.New(rand.NewSource(1)), items...).
+			WithBudget(Blue, decimal.NewFromInt(3))
+		for range 3 {
+			for b.Next() != Blue {
+			}
+		}
+		data, err := json.Marshal(b)
+		require.NoError(t, err)
+
+		restored := NewBudgeted(rand.New(rand.NewSource(1)), items...).
+			WithBudget(Red, decimal.NewFromInt(10))
+		require.NoError(t, json.Unmarshal(data, restored))
+		remaining, ok := restored.Remaining(Blue)
+		assert.True(t, ok)
+		assert.True(t, remaining.IsZero())
+		_, ok = restored.Remaining(Red)
+		assert.False(t, ok)
+		for range 100 {
+			assert.Equal(t, Red, restored.Next())
+		}
+	})
+	t.Run("frequency cap", func(t *testing.T) {
+		clock := NewFixtureClock()
+		fc := NewFrequencyCap(rand.New(rand.NewSource(1)), clock, items...).
+			WithCap(Blue, 2, time.Hour)
+		for fc.Selections(Blue) < 2 {
+			fc.Next()
+		}
+		data, err := json.Marshal(fc)
+		require.NoError(t, err)
+
+		restored := NewFrequencyCap(rand.New(rand.NewSource(1)), clock, items...).
+			WithCap(Blue, 2, time.Hour)
+		require.NoError(t, json.Unmarshal(data, restored))
+		assert.Equal(t, 2, restored.Selections(Blue))
+		for range 100 {
+			assert.Equal(t, Red, restored.Next())
+		}
+		clock.Advance(time.Hour)
+		assert.Zero(t, restored.Selections(Blue))
+	})
+}
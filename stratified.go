@@ -0,0 +1,100 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// StratifiedDrawK selects the indices of k distinct items by weight, like
+// SampleK, while guaranteeing that every stratum is represented when k is at
+// least the number of strata. One item is first drawn from every stratum by
+// weight, and the remaining selections are drawn by weight from all the
+// items not selected yet. When k is less than the number of strata, k strata
+// are drawn by their total weight, and one item from each of them.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1. If k is at least the number of items,
+// every index is returned.
+//
+// The function panics if k is negative or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - items:    The items to be sampled.
+//   - weightOf: A function returning the weight of the item at an index.
+//   - k:        The number of items to select.
+//   - strata:   A function returning the stratum of an item.
+//   - random:   A RandIntN implementation used for random number generation.
+//
+// Example usage:
+//
+//	indices := StratifiedDrawK(respondents, func(i int) int { return respondents[i].Weight }, 100,
+//		func(r Respondent) string { return r.Region }, randSource)
+func StratifiedDrawK[TItem any, TWeight Weight](items []TItem, weightOf func(i int) TWeight, k int, strata func(TItem) string, random RandIntN) []int {
+	if k < 0 {
+		panic(fmt.Sprintf("k must be non-negative value, but was %d", k))
+	}
+	k = min(k, len(items))
+	if k == 0 {
+		return []int{}
+	}
+	// Group the indices by stratum, in the order the strata first appear so
+	// that the result only depends on the random number generator.
+	weights := make([]decimal.Decimal, len(items))
+	var names []string
+	members := make(map[string][]int)
+	for i, item := range items {
+		weights[i] = effectiveWeight(weightOf(i))
+		name := strata(item)
+		if _, ok := members[name]; !ok {
+			names = append(names, name)
+		}
+		members[name] = append(members[name], i)
+	}
+	drawFrom := func(indices []int) int {
+		return indices[Sample(indices, func(i int) decimal.Decimal {
+			return weights[indices[i]]
+		}, random)]
+	}
+
+	if k < len(names) {
+		stratumWeights := make([]decimal.Decimal, len(names))
+		for s, name := range names {
+			for _, i := range members[name] {
+				stratumWeights[s] = stratumWeights[s].Add(weights[i])
+			}
+		}
+		chosen := SampleK(names, func(s int) decimal.Decimal {
+			return stratumWeights[s]
+		}, k, random)
+		result := make([]int, 0, k)
+		for _, s := range chosen {
+			result = append(result, drawFrom(members[names[s]]))
+		}
+		return result
+	}
+
+	result := make([]int, 0, k)
+	selected := make([]bool, len(items))
+	for _, name := range names {
+		i := drawFrom(members[name])
+		selected[i] = true
+		result = append(result, i)
+	}
+	remaining := make([]int, 0, len(items)-len(result))
+	for i := range items {
+		if !selected[i] {
+			remaining = append(remaining, i)
+		}
+	}
+	for _, r := range SampleK(remaining, func(r int) decimal.Decimal {
+		return weights[remaining[r]]
+	}, k-len(result), random) {
+		result = append(result, remaining[r])
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStratifiedDrawK(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	type respondent struct {
+		region string
+		weight int
+	}
+	respondents := []respondent{
+		{region: "north", weight: 100},
+		{region: "north", weight: 100},
+		{region: "north", weight: 100},
+		{region: "south", weight: 1},
+		{region: "east", weight: 1},
+		{region: "east", weight: 3},
+	}
+	weightOf := func(i int) int { return respondents[i].weight }
+	regionOf := func(r respondent) string { return r.region }
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			StratifiedDrawK(respondents, weightOf, -1, regionOf, r)
+		})
+	})
+	t.Run("every stratum is represented", func(t *testing.T) {
+		southCounts := 0
+		for range 10_000 {
+			indices := StratifiedDrawK(respondents, weightOf, 4, regionOf, r)
+			assert.Len(t, indices, 4)
+			regions := make(map[string]int)
+			unique := make(map[int]struct{})
+			for _, i := range indices {
+				regions[respondents[i].region] += 1
+				unique[i] = struct{}{}
+				if respondents[i].region == "south" {
+					southCounts += 1
+				}
+			}
+			assert.Len(t, unique, 4)
+			assert.Len(t, regions, 3)
+		}
+		// Despite its weight, the only item of its stratum is always selected.
+		assert.Equal(t, 10_000, southCounts)
+	})
+	t.Run("items are drawn by weight within their stratum", func(t *testing.T) {
+		counts := make(map[int]int)
+		for range 10_000 {
+			for _, i := range StratifiedDrawK(respondents, weightOf, 3, regionOf, r) {
+				counts[i] += 1
+			}
+		}
+		assert.InDeltaf(t, 0.75, float64(counts[5])/10_000, tolerance, "%v", counts)
+	})
+	t.Run("fewer selections than strata", func(t *testing.T) {
+		counts := make(map[string]int)
+		for range 10_000 {
+			indices := StratifiedDrawK(respondents, weightOf, 1, regionOf, r)
+			assert.Len(t, indices, 1)
+			counts[respondents[indices[0]].region] += 1
+		}
+		assert.InDeltaf(t, 300.0/305, float64(counts["north"])/10_000, tolerance, "%v", counts)
+	})
+	t.Run("more selections than items", func(t *testing.T) {
+		assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5}, StratifiedDrawK(respondents, weightOf, 10, regionOf, r))
+		assert.Empty(t, StratifiedDrawK(respondents, weightOf, 0, regionOf, r))
+	})
+}
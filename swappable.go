@@ -0,0 +1,49 @@
+package weightedrand
+
+import (
+	"sync/atomic"
+)
+
+// Swappable is a WeightedRandom that delegates to another WeightedRandom,
+// which can be atomically replaced while selections are in progress. This
+// allows a new table to be built in the background and put into service
+// without locking. Stage validates a new table before it goes into service,
+// and allows the change to be rolled back.
+type Swappable[TItem any] struct {
+	current atomic.Pointer[WeightedRandom[TItem]]
+}
+
+// NewSwappable constructs a new Swappable instance delegating to initial.
+//
+// The function panics if initial is nil.
+func NewSwappable[TItem any](initial WeightedRandom[TItem]) *Swappable[TItem] {
+	swappable := &Swappable[TItem]{}
+	swappable.Swap(initial)
+	return swappable
+}
+
+// Next selects an item from the current WeightedRandom.
+func (swappable *Swappable[TItem]) Next() TItem {
+	return (*swappable.current.Load()).Next()
+}
+
+// Load returns the current WeightedRandom.
+func (swappable *Swappable[TItem]) Load() WeightedRandom[TItem] {
+	return *swappable.current.Load()
+}
+
+// Swap replaces the current WeightedRandom with next, and returns the one it
+// replaced.
+//
+// Panics:
+//   - If next is nil.
+func (swappable *Swappable[TItem]) Swap(next WeightedRandom[TItem]) WeightedRandom[TItem] {
+	if next == nil {
+		panic("cannot swap to a nil WeightedRandom")
+	}
+	previous := swappable.current.Swap(&next)
+	if previous == nil {
+		return nil
+	}
+	return *previous
+}
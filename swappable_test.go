@@ -0,0 +1,52 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwappable(t *testing.T) {
+	red := NewAliasVoseMethod(rand.New(rand.NewSource(1)), WeightedItem[MarbleColor, int]{Item: Red})
+	blue := NewAliasVoseMethod(rand.New(rand.NewSource(1)), WeightedItem[MarbleColor, int]{Item: Blue})
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewSwappable[MarbleColor](nil)
+		})
+		assert.Panics(t, func() {
+			NewSwappable(red).Swap(nil)
+		})
+	})
+	t.Run("swap", func(t *testing.T) {
+		s := NewSwappable(red)
+		assert.Equal(t, Red, s.Next())
+		assert.Equal(t, red, s.Swap(blue))
+		assert.Equal(t, blue, s.Load())
+		assert.Equal(t, Blue, s.Next())
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		s := NewSwappable(red)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1_000 {
+				s.Swap(blue)
+				s.Swap(red)
+			}
+		}()
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 1_000 {
+					assert.NotNil(t, s.Load())
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,55 @@
+package weightedrand
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// SystematicSampleK selects the indices of k items by systematic sampling:
+// the items are laid out on a line by their cumulative weight, and the line
+// is divided into k intervals of equal length, with a single random start
+// within the first interval and an item selected every interval after it.
+// Compared to k independent draws, the number of times each item is selected
+// varies much less, which is why it is standard in survey statistics.
+//
+// Every item is selected either floor or ceil of k times its probability, so
+// an item whose weight exceeds the length of an interval is selected more
+// than once. The indices are returned in increasing order.
+//
+// Weights follow the same rules as NewAliasVoseMethod: if no weight is
+// provided, it is assumed to be 1.
+//
+// The function panics if no items are provided, if k is negative, or if
+// weights are negative.
+//
+// Example usage:
+//
+//	indices := SystematicSampleK(households, func(i int) int { return households[i].Size }, 50, randSource)
+func SystematicSampleK[TItem any, TWeight Weight](items []TItem, weightOf func(i int) TWeight, k int, random RandIntN) []int {
+	if len(items) == 0 {
+		panic("at least one item must be provided")
+	} else if k < 0 {
+		panic(fmt.Sprintf("k must be non-negative value, but was %d", k))
+	}
+	weights := make([]decimal.Decimal, len(items))
+	for i := range items {
+		weights[i] = effectiveWeight(weightOf(i))
+	}
+	cumulative := cumulativeWeights(weights)
+	if k == 0 {
+		return []int{}
+	}
+	step := cumulative[len(cumulative)-1].Div(decimal.NewFromInt(int64(k)))
+	point := uniformDecimal(random).Mul(step)
+	result := make([]int, 0, k)
+	index := 0
+	for range k {
+		// The points are increasing, so the search resumes from the last
+		// selected item.
+		index += searchCumulative(cumulative[index:], point)
+		result = append(result, index)
+		point = point.Add(step)
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystematicSampleK(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	colors := []MarbleColor{Red, Orange, Yellow, Green, Blue}
+	weights := []int{1, 1, 2, 4, 12}
+	weightOf := func(i int) int { return weights[i] }
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			SystematicSampleK([]MarbleColor{}, weightOf, 1, r)
+		})
+		assert.Panics(t, func() {
+			SystematicSampleK(colors, weightOf, -1, r)
+		})
+	})
+	t.Run("counts are within one of expectation", func(t *testing.T) {
+		const k = 10
+		totals := make(map[int]int)
+		for range 10_000 {
+			indices := SystematicSampleK(colors, weightOf, k, r)
+			assert.Len(t, indices, k)
+			assert.True(t, slices.IsSorted(indices))
+			counts := make(map[int]int)
+			for _, i := range indices {
+				counts[i] += 1
+				totals[i] += 1
+			}
+			for i, weight := range weights {
+				expected := float64(k*weight) / 20
+				assert.InDelta(t, expected, counts[i], 1, "%s: %v", colors[i], counts)
+			}
+			// Blue covers 6 of the 10 intervals exactly.
+			assert.Equal(t, 6, counts[4])
+		}
+		assert.InDelta(t, 0.5, float64(totals[0])/10_000, tolerance)
+	})
+	t.Run("empty", func(t *testing.T) {
+		assert.Empty(t, SystematicSampleK(colors, weightOf, 0, r))
+	})
+}
@@ -0,0 +1,165 @@
+package weightedrand
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Table is an alias table built by Vose's algorithm, without a random number
+// generator. It is pure data: immutable once built, safe to share between
+// goroutines, and serializable to JSON. Samplers are cheap views over a
+// table, which pair it with a random number generator without copying it.
+type Table[TItem any] struct {
+	aliasMethod voseAliasMethodRandom[TItem]
+}
+
+// BuildTable builds an alias table from the items, with the same weight
+// rules as NewAliasVoseMethod: if no weight is provided, it is assumed to be
+// 1. Unlike the constructors, it returns an error rather than panicking if
+// no items are provided or weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - items: A variadic list of WeightedItem values, each containing an item and its associated weight.
+//
+// Returns:
+//   - *Table[TItem]: The alias table, which is sampled through Sampler or NextUsing.
+//   - error:         If no items are provided or weights are negative.
+//
+// Example usage:
+//
+//	table, err := BuildTable(WeightedItem[string, int]{Item: "A", Weight: 2}, WeightedItem[string, int]{Item: "B", Weight: 3})
+//	if err != nil {
+//		return err
+//	}
+//	wr := table.Sampler(randSource)
+func BuildTable[TItem any, TWeight Weight](items ...WeightedItem[TItem, TWeight]) (*Table[TItem], error) {
+	return BuildTableContext(context.Background(), items...)
+}
+
+// BuildTableContext is like BuildTable, but stops early when the context is
+// done, and returns its error instead of a table. Building a table from
+// millions of items takes seconds, so servers should tie it to the lifetime
+// of the request or process that needs it.
+//
+// Example usage:
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//	table, err := BuildTableContext(ctx, catalog...)
+func BuildTableContext[TItem any, TWeight Weight](ctx context.Context, items ...WeightedItem[TItem, TWeight]) (*Table[TItem], error) {
+	return buildTable(buildContext{ctx: ctx}, items)
+}
+
+func buildTable[TItem any, TWeight Weight](build buildContext, items []WeightedItem[TItem, TWeight]) (*Table[TItem], error) {
+	aliasMethod, err := buildVoseAliasMethodContext[TItem](build, nil, items)
+	if err != nil {
+		return nil, err
+	}
+	return &Table[TItem]{
+		aliasMethod: aliasMethod,
+	}, nil
+}
+
+// Sampler returns a WeightedRandom selecting from the table with the random
+// number generator. The table is shared rather than copied, so samplers are
+// cheap to create, such as one per request. The returned instance also
+// implements RandomInjectable.
+func (table *Table[TItem]) Sampler(random RandIntN) WeightedRandom[TItem] {
+	return voseAliasMethodRandom[TItem]{
+		random: random,
+		tuples: table.aliasMethod.tuples,
+	}
+}
+
+// NextUsing selects an item from the table by weight, using the random
+// number generator.
+func (table *Table[TItem]) NextUsing(random RandIntN) TItem {
+	return table.aliasMethod.NextUsing(random)
+}
+
+// Len returns the number of buckets of the table, which is the number of
+// items it was built from.
+func (table *Table[TItem]) Len() int {
+	return len(table.aliasMethod.tuples)
+}
+
+func (table *Table[TItem]) String() string {
+	return table.aliasMethod.String()
+}
+
+// tableBucket is the serialized form of an aliasTuple.
+type tableBucket[TItem any] struct {
+	Probability decimal.Decimal `json:"probability"`
+	Item        TItem           `json:"item"`
+	Alias       *TItem          `json:"alias,omitempty"`
+}
+
+// MarshalJSON encodes the buckets of the table, so that it can be restored
+// without being rebuilt.
+func (table *Table[TItem]) MarshalJSON() ([]byte, error) {
+	buckets := make([]tableBucket[TItem], 0, len(table.aliasMethod.tuples))
+	for _, tuple := range table.aliasMethod.tuples {
+		buckets = append(buckets, tableBucket[TItem]{
+			Probability: tuple.probability,
+			Item:        tuple.primaryItem,
+			Alias:       tuple.aliasedItem,
+		})
+	}
+	return json.Marshal(buckets)
+}
+
+// UnmarshalJSON decodes buckets encoded by MarshalJSON, and validates that
+// they form an alias table.
+func (table *Table[TItem]) UnmarshalJSON(data []byte) error {
+	var buckets []tableBucket[TItem]
+	if err := json.Unmarshal(data, &buckets); err != nil {
+		return err
+	} else if len(buckets) == 0 {
+		return ErrNoItems
+	}
+	tuples := make([]aliasTuple[TItem], 0, len(buckets))
+	for index, bucket := range buckets {
+		if bucket.Probability.IsNegative() || bucket.Probability.GreaterThan(One) {
+			return fmt.Errorf("probability of bucket %d must be within [0, 1], but was %s", index, bucket.Probability.String())
+		} else if bucket.Alias == nil && bucket.Probability.LessThan(One) {
+			return fmt.Errorf("bucket %d has a probability of %s, but no alias", index, bucket.Probability.String())
+		}
+		tuples = append(tuples, aliasTuple[TItem]{
+			probability: bucket.Probability,
+			primaryItem: bucket.Item,
+			aliasedItem: bucket.Alias,
+		})
+	}
+	table.aliasMethod = voseAliasMethodRandom[TItem]{
+		tuples: tuples,
+	}
+	return nil
+}
+
+// EqualTables reports whether both tables have the same buckets, and thus
+// select the same items given the same random number generator.
+func EqualTables[TItem comparable](a, b *Table[TItem]) bool {
+	if a == nil || b == nil {
+		return a == b
+	} else if len(a.aliasMethod.tuples) != len(b.aliasMethod.tuples) {
+		return false
+	}
+	for i, tuple := range a.aliasMethod.tuples {
+		other := b.aliasMethod.tuples[i]
+		if !tuple.probability.Equal(other.probability) || tuple.primaryItem != other.primaryItem {
+			return false
+		} else if (tuple.aliasedItem == nil) != (other.aliasedItem == nil) {
+			return false
+		} else if tuple.aliasedItem != nil && *tuple.aliasedItem != *other.aliasedItem {
+			return false
+		}
+	}
+	return true
+}
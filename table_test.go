@@ -0,0 +1,89 @@
+package weightedrand_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTable(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 3},
+	}
+	t.Run("errors", func(t *testing.T) {
+		_, err := BuildTable[MarbleColor, int]()
+		assert.ErrorIs(t, err, ErrNoItems)
+		_, err = BuildTable(WeightedItem[MarbleColor, int]{Item: Red, Weight: 1}, WeightedItem[MarbleColor, int]{Item: Blue, Weight: -1})
+		var negativeErr *ErrNegativeWeight
+		if assert.ErrorAs(t, err, &negativeErr) {
+			assert.Equal(t, 1, negativeErr.Index)
+			assert.Equal(t, "-1", negativeErr.Weight.String())
+		}
+		assert.EqualError(t, err, "weight of item 1 must be non-negative value, but was -1")
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := BuildTableContext(ctx, WeightedItem[MarbleColor, int]{Item: Red, Weight: 1})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("samplers share the table", func(t *testing.T) {
+		table, err := BuildTable(items...)
+		require.NoError(t, err)
+		assert.Equal(t, 3, table.Len())
+		first := table.Sampler(NewRandFromString("request-1"))
+		second := table.Sampler(NewRandFromString("request-1"))
+		direct := NewRandFromString("request-1")
+		for range 100 {
+			item := first.Next()
+			assert.Equal(t, item, second.Next())
+			assert.Equal(t, item, table.NextUsing(direct))
+		}
+	})
+	t.Run("distribution", func(t *testing.T) {
+		table, err := BuildTable(items...)
+		require.NoError(t, err)
+		sampler := table.Sampler(rand.New(rand.NewSource(time.Now().Unix())))
+		counts := make(MarbleColorCounts)
+		for range 100_000 {
+			counts[sampler.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+	})
+	t.Run("json", func(t *testing.T) {
+		table, err := BuildTable(items...)
+		require.NoError(t, err)
+		data, err := json.Marshal(table)
+		require.NoError(t, err)
+		var restored Table[MarbleColor]
+		require.NoError(t, json.Unmarshal(data, &restored))
+		assert.True(t, EqualTables(table, &restored))
+		r1, r2 := NewRandFromString("restored"), NewRandFromString("restored")
+		for range 100 {
+			assert.Equal(t, table.NextUsing(r1), restored.NextUsing(r2))
+		}
+	})
+	t.Run("invalid json", func(t *testing.T) {
+		var table Table[MarbleColor]
+		assert.Error(t, json.Unmarshal([]byte(`[]`), &table))
+		assert.Error(t, json.Unmarshal([]byte(`[{"probability":"1.5","item":"red"}]`), &table))
+		assert.Error(t, json.Unmarshal([]byte(`[{"probability":"0.5","item":"red"}]`), &table))
+		assert.NoError(t, json.Unmarshal([]byte(`[{"probability":"1","item":"red"}]`), &table))
+	})
+	t.Run("equality", func(t *testing.T) {
+		a, _ := BuildTable(items...)
+		b, _ := BuildTable(items...)
+		c, _ := BuildTable(items[:2]...)
+		assert.True(t, EqualTables(a, b))
+		assert.False(t, EqualTables(a, c))
+		assert.False(t, EqualTables(a, nil))
+	})
+}
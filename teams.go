@@ -0,0 +1,180 @@
+package weightedrand
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// DrawTeams assigns the players to teams at random, with team sizes
+// proportional to their weights, such as for drafting players into teams of
+// a tournament. Sizes are apportioned by the largest remainder method, with
+// ties broken at random, so they sum to the number of players and differ
+// from their exact share by less than one.
+//
+// The function panics if no teams are provided, a team is duplicated, or
+// weights are negative.
+//
+// Type Parameters:
+//   - TPlayer: The type of the players.
+//   - TTeam:   The type of the teams.
+//   - TWeight: The type representing the weight of each team.
+//
+// Parameters:
+//   - random:  A RandIntN implementation used for random number generation.
+//   - players: The players to assign.
+//   - teams:   A variadic list of WeightedItem values, each containing a team and its associated weight.
+//
+// Example usage:
+//
+//	teams := DrawTeams(randSource, players,
+//		WeightedItem[string, int]{Item: "red", Weight: 2},
+//		WeightedItem[string, int]{Item: "blue", Weight: 1},
+//	)
+func DrawTeams[TPlayer any, TTeam comparable, TWeight Weight](random RandIntN, players []TPlayer, teams ...WeightedItem[TTeam, TWeight]) map[TTeam][]TPlayer {
+	result, err := DrawTeamsFunc(random, players, nil, teams...)
+	if err != nil {
+		// Without constraints, every player can be assigned.
+		panic(err.Error())
+	}
+	return result
+}
+
+// DrawTeamsFunc is like DrawTeams, but only assigns a player to a team if
+// allowed reports true, such as to keep players of the same club apart. The
+// team sizes are the same as for DrawTeams, and the assignment is found by
+// augmenting paths, so it succeeds whenever the constraints allow the sizes
+// to be filled. A nil allowed function allows every assignment.
+//
+// Returns:
+//   - map[TTeam][]TPlayer: The players of every team.
+//   - error:               If the constraints do not allow the team sizes to be filled.
+func DrawTeamsFunc[TPlayer any, TTeam comparable, TWeight Weight](random RandIntN, players []TPlayer, allowed func(TPlayer, TTeam) bool, teams ...WeightedItem[TTeam, TWeight]) (map[TTeam][]TPlayer, error) {
+	sizes := apportion(random, len(players), teams)
+	order := make([]int, len(players))
+	for i := range order {
+		order[i] = i
+	}
+	shuffle(random, order)
+	assignment := teamAssignment[TPlayer, TTeam]{
+		random:  random,
+		players: players,
+		teams:   make([]TTeam, len(teams)),
+		allowed: allowed,
+		sizes:   sizes,
+		members: make([][]int, len(teams)),
+	}
+	for index, team := range teams {
+		assignment.teams[index] = team.Item
+	}
+	for _, player := range order {
+		if !assignment.assign(player, make([]bool, len(teams))) {
+			return nil, fmt.Errorf("player %v cannot be assigned to any team within the constraints", players[player])
+		}
+	}
+	result := make(map[TTeam][]TPlayer, len(teams))
+	for index, team := range teams {
+		members := make([]TPlayer, 0, len(assignment.members[index]))
+		for _, player := range assignment.members[index] {
+			members = append(members, players[player])
+		}
+		result[team.Item] = members
+	}
+	return result, nil
+}
+
+// apportion divides the seats between the teams in proportion to their
+// weights by the largest remainder method.
+func apportion[TTeam comparable, TWeight Weight](random RandIntN, seats int, teams []WeightedItem[TTeam, TWeight]) []int {
+	if len(teams) == 0 {
+		panic("at least one team must be provided")
+	}
+	seen := make(map[TTeam]bool, len(teams))
+	weights := make([]decimal.Decimal, len(teams))
+	total := decimal.Zero
+	for i, team := range teams {
+		if seen[team.Item] {
+			panic(fmt.Sprintf("team %v is duplicated", team.Item))
+		}
+		seen[team.Item] = true
+		weights[i] = effectiveWeight(team.Weight)
+		total = total.Add(weights[i])
+	}
+	sizes := make([]int, len(teams))
+	remainders := make([]decimal.Decimal, len(teams))
+	assigned := 0
+	for i, weight := range weights {
+		quotient, remainder := decimal.NewFromInt(int64(seats)).Mul(weight).QuoRem(total, 0)
+		sizes[i] = int(quotient.IntPart())
+		remainders[i] = remainder
+		assigned += sizes[i]
+	}
+	// The remaining seats go to the largest remainders, with ties in random
+	// order.
+	order := make([]int, len(teams))
+	for i := range order {
+		order[i] = i
+	}
+	shuffle(random, order)
+	slices.SortStableFunc(order, func(a, b int) int {
+		return remainders[b].Cmp(remainders[a])
+	})
+	for _, i := range order[:seats-assigned] {
+		sizes[i]++
+	}
+	return sizes
+}
+
+// shuffle permutes the indices uniformly at random, by the Fisher-Yates
+// shuffle.
+func shuffle(random RandIntN, indices []int) {
+	for i := len(indices) - 1; i > 0; i-- {
+		j := random.Intn(i + 1)
+		indices[i], indices[j] = indices[j], indices[i]
+	}
+}
+
+// teamAssignment matches players to the seats of the teams.
+type teamAssignment[TPlayer any, TTeam comparable] struct {
+	random  RandIntN
+	players []TPlayer
+	teams   []TTeam
+	allowed func(TPlayer, TTeam) bool
+	sizes   []int
+	members [][]int
+}
+
+// assign seats the player in an allowed team with a free seat, or otherwise
+// in a full team whose member can be moved to another team, trying teams in
+// random order. Teams already visited while moving members are skipped.
+func (assignment *teamAssignment[TPlayer, TTeam]) assign(player int, visited []bool) bool {
+	order := make([]int, len(assignment.teams))
+	for i := range order {
+		order[i] = i
+	}
+	shuffle(assignment.random, order)
+	for _, team := range order {
+		if assignment.permits(player, team) && len(assignment.members[team]) < assignment.sizes[team] {
+			assignment.members[team] = append(assignment.members[team], player)
+			return true
+		}
+	}
+	for _, team := range order {
+		if visited[team] || !assignment.permits(player, team) {
+			continue
+		}
+		visited[team] = true
+		for i, member := range assignment.members[team] {
+			if assignment.assign(member, visited) {
+				assignment.members[team][i] = player
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (assignment *teamAssignment[TPlayer, TTeam]) permits(player int, team int) bool {
+	return assignment.allowed == nil || assignment.allowed(assignment.players[player], assignment.teams[team])
+}
@@ -0,0 +1,78 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrawTeams(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	players := make([]int, 10)
+	for i := range players {
+		players[i] = i
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			DrawTeams[int, string, int](r, players)
+		})
+		assert.Panics(t, func() {
+			DrawTeams(r, players, WeightedItem[string, int]{Item: "red"}, WeightedItem[string, int]{Item: "red"})
+		})
+		assert.Panics(t, func() {
+			DrawTeams(r, players, WeightedItem[string, int]{Item: "red", Weight: -1})
+		})
+	})
+	t.Run("sizes proportional to weights", func(t *testing.T) {
+		teams := DrawTeams(r, players,
+			WeightedItem[string, int]{Item: "red", Weight: 2},
+			WeightedItem[string, int]{Item: "green", Weight: 1},
+			WeightedItem[string, int]{Item: "blue", Weight: 2},
+		)
+		assert.Len(t, teams["red"], 4)
+		assert.Len(t, teams["green"], 2)
+		assert.Len(t, teams["blue"], 4)
+		var all []int
+		for _, members := range teams {
+			all = append(all, members...)
+		}
+		assert.ElementsMatch(t, players, all)
+	})
+	t.Run("ties are broken at random", func(t *testing.T) {
+		sizes := make(map[int]int)
+		for range 200 {
+			teams := DrawTeams(r, players[:7],
+				WeightedItem[string, int]{Item: "red", Weight: 1},
+				WeightedItem[string, int]{Item: "blue", Weight: 1},
+			)
+			assert.Len(t, teams["blue"], 7-len(teams["red"]))
+			sizes[len(teams["red"])] += 1
+		}
+		assert.Len(t, sizes, 2)
+		assert.Contains(t, sizes, 3)
+		assert.Contains(t, sizes, 4)
+	})
+	t.Run("constraints", func(t *testing.T) {
+		parity := func(player int, team string) bool {
+			return (player%2 == 0) == (team == "even")
+		}
+		for range 100 {
+			teams, err := DrawTeamsFunc(r, players, parity,
+				WeightedItem[string, int]{Item: "even", Weight: 1},
+				WeightedItem[string, int]{Item: "odd", Weight: 1},
+			)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []int{0, 2, 4, 6, 8}, teams["even"])
+			assert.ElementsMatch(t, []int{1, 3, 5, 7, 9}, teams["odd"])
+		}
+		_, err := DrawTeamsFunc(r, players, parity,
+			WeightedItem[string, int]{Item: "even", Weight: 3},
+			WeightedItem[string, int]{Item: "odd", Weight: 2},
+		)
+		assert.ErrorContains(t, err, "cannot be assigned to any team")
+	})
+}
@@ -0,0 +1,108 @@
+package weightedrand
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TieBreak is a policy deciding the order of items of equal weight in the
+// alias table, which decides the bucket each item is placed in, and thus the
+// sequence of selections under a fixed seed. It never changes the
+// distribution.
+type TieBreak int
+
+const (
+	// TieBreakInputOrder keeps items of equal weight in the order they were
+	// provided in.
+	TieBreakInputOrder TieBreak = iota + 1
+	// TieBreakRandom shuffles items of equal weight with the random number
+	// generator of the constructor, so that no item is favored by its
+	// position. Tables have no random number generator, and do not support
+	// it.
+	TieBreakRandom
+	// tieBreakKey orders items of equal weight by the key of
+	// WithTieBreakKey.
+	tieBreakKey
+	// tieBreakCanonical orders items of equal weight by their formatted
+	// representation, for WithCanonicalOrder.
+	tieBreakCanonical
+)
+
+// WithTieBreak orders items of equal weight by the policy when the alias
+// table is built. Without a policy, their order is unspecified, though
+// deterministic for the same input.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, items, WithTieBreak(TieBreakInputOrder))
+func WithTieBreak(policy TieBreak) Option {
+	if policy != TieBreakInputOrder && policy != TieBreakRandom {
+		panic(fmt.Sprintf("unsupported tie-break policy %d", policy))
+	}
+	return func(o *options) {
+		o.tieBreak = policy
+		o.tieBreakKey = nil
+	}
+}
+
+// WithTieBreakKey orders items of equal weight lexicographically by their
+// keys when the alias table is built. The key function is for items of the
+// same type as the items it is used with.
+//
+// Example usage:
+//
+//	wr := NewAliasVoseMethodWithOptions(randSource, servers, WithTieBreakKey(func(server Server) string {
+//		return server.Hostname
+//	}))
+func WithTieBreakKey[TItem any](key func(TItem) string) ItemOption[TItem] {
+	return func(o *options) {
+		o.tieBreak = tieBreakKey
+		o.tieBreakKey = key
+	}
+}
+
+// tieBrokenItems arranges the items by the tie-breaking policy, and reports
+// whether the alias table must be built keeping items of equal weight in
+// that order.
+func tieBrokenItems[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight], o options) ([]WeightedItem[TItem, TWeight], bool, error) {
+	var key func(TItem) string
+	switch o.tieBreak {
+	case 0:
+		return items, false, nil
+	case TieBreakInputOrder:
+		return items, true, nil
+	case TieBreakRandom:
+		if random == nil {
+			return nil, true, errors.New("random tie-breaking requires a random number generator")
+		}
+		shuffled := slices.Clone(items)
+		for i := len(shuffled) - 1; i > 0; i-- {
+			j := random.Intn(i + 1)
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		}
+		return shuffled, true, nil
+	case tieBreakKey:
+		// ItemOption ensures the key function is for items of type TItem.
+		key = o.tieBreakKey.(func(TItem) string)
+	case tieBreakCanonical:
+		key = func(item TItem) string {
+			return fmt.Sprintf("%v", item)
+		}
+	}
+	keys := make([]string, len(items))
+	order := make([]int, len(items))
+	for i, item := range items {
+		keys[i] = key(item.Item)
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return strings.Compare(keys[a], keys[b])
+	})
+	sorted := make([]WeightedItem[TItem, TWeight], len(items))
+	for i, index := range order {
+		sorted[i] = items[index]
+	}
+	return sorted, true, nil
+}
@@ -0,0 +1,67 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieBreak(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 2},
+		{Item: Orange, Weight: 1},
+		{Item: Yellow, Weight: 2},
+		{Item: Green, Weight: 1},
+		{Item: Blue, Weight: 2},
+	}
+	reordered := []WeightedItem[MarbleColor, int]{
+		items[4], items[3], items[2], items[1], items[0],
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			WithTieBreak(TieBreak(0))
+		})
+		_, err := BuildTableWithOptions(items, WithTieBreak(TieBreakRandom))
+		assert.Error(t, err)
+	})
+	t.Run("input order", func(t *testing.T) {
+		a, err := BuildTableWithOptions(items, WithTieBreak(TieBreakInputOrder))
+		require.NoError(t, err)
+		b, err := BuildTableWithOptions(slices.Clone(items), WithTieBreak(TieBreakInputOrder))
+		require.NoError(t, err)
+		c, err := BuildTableWithOptions(reordered, WithTieBreak(TieBreakInputOrder))
+		require.NoError(t, err)
+		assert.True(t, EqualTables(a, b))
+		assert.False(t, EqualTables(a, c))
+	})
+	t.Run("key", func(t *testing.T) {
+		byName := WithTieBreakKey(func(color MarbleColor) string {
+			return string(color)
+		})
+		for name, opts := range map[string][]ItemOption[MarbleColor]{
+			"decimal": {byName},
+			"integer": {byName, WithIntegerEngine()},
+		} {
+			t.Run(name, func(t *testing.T) {
+				a := NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(1)), items, opts...)
+				b := NewAliasVoseMethodWithOptions(rand.New(rand.NewSource(1)), reordered, opts...)
+				assert.Equal(t, NextN(a, 1_000), NextN(b, 1_000))
+			})
+		}
+	})
+	t.Run("random", func(t *testing.T) {
+		r := rand.New(rand.NewSource(7))
+		wr := NewAliasVoseMethodWithOptions(r, items, WithTieBreak(TieBreakRandom))
+		const iterations = 100_000
+		counts := make(MarbleColorCounts)
+		for range iterations {
+			counts[wr.Next()] += 1
+		}
+		assert.InDeltaf(t, 0.25, float64(counts[Red])/iterations, tolerance, "%s", counts)
+		assert.InDeltaf(t, 0.125, float64(counts[Green])/iterations, tolerance, "%s", counts)
+	})
+}
@@ -0,0 +1,129 @@
+package weightedrand
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Tiered is a WeightedRandom over tiers of items in order of priority, such
+// as a primary pool and its backups. Items are always selected from the
+// highest-priority tier that has an available item, in proportion to the
+// weights of the available items within it, and selections fall back to the
+// next tier only once every item of the tiers above is unavailable, such as
+// when they are unhealthy.
+//
+// Tiered is safe for concurrent use, given that the random number generator
+// is.
+type Tiered[TItem comparable] struct {
+	mutex   sync.Mutex
+	tiers   []*adjustableTable[TItem]
+	indices map[TItem][]tieredIndex
+}
+
+// tieredIndex locates an item within the tiers.
+type tieredIndex struct {
+	tier  int
+	index int
+}
+
+// NewTiered constructs a new Tiered instance over the tiers, in order of
+// priority, highest first. Every item is available until it is marked
+// otherwise with SetAvailable.
+//
+// The function panics if no tiers are provided, a tier has no items, or
+// weights are negative.
+//
+// Type Parameters:
+//   - TItem:   The type of the items to be sampled.
+//   - TWeight: The type representing the weight of each item.
+//
+// Parameters:
+//   - random: A RandIntN implementation used for random number generation.
+//   - tiers:  A variadic list of tiers, each a slice of WeightedItem values containing an item and its associated weight.
+//
+// Example usage:
+//
+//	t := NewTiered(randSource,
+//		[]WeightedItem[string, int]{{Item: "primary-a", Weight: 3}, {Item: "primary-b", Weight: 1}},
+//		[]WeightedItem[string, int]{{Item: "backup", Weight: 1}},
+//	)
+//	t.SetAvailable("primary-a", false)
+func NewTiered[TItem comparable, TWeight Weight](random RandIntN, tiers ...[]WeightedItem[TItem, TWeight]) *Tiered[TItem] {
+	if len(tiers) == 0 {
+		panic("at least one tier must be provided")
+	}
+	tiered := &Tiered[TItem]{
+		tiers:   make([]*adjustableTable[TItem], len(tiers)),
+		indices: make(map[TItem][]tieredIndex),
+	}
+	for tier, items := range tiers {
+		if len(items) == 0 {
+			panic(fmt.Sprintf("tier %d must have at least one item", tier))
+		}
+		tiered.tiers[tier] = newAdjustableTable(random, items)
+		for index, item := range items {
+			tiered.indices[item.Item] = append(tiered.indices[item.Item], tieredIndex{
+				tier:  tier,
+				index: index,
+			})
+		}
+	}
+	return tiered
+}
+
+// SetAvailable marks the item as available or unavailable in every tier it
+// belongs to. Unavailable items are not selected, which may make selections
+// fall back to a lower tier.
+func (tiered *Tiered[TItem]) SetAvailable(item TItem, available bool) {
+	tiered.mutex.Lock()
+	defer tiered.mutex.Unlock()
+	for _, location := range tiered.indices[item] {
+		if available {
+			tiered.tiers[location.tier].restore(location.index)
+		} else {
+			tiered.tiers[location.tier].exclude(location.index)
+		}
+	}
+}
+
+// Active returns the index of the tier items are currently selected from,
+// or -1 if every item is unavailable.
+func (tiered *Tiered[TItem]) Active() int {
+	tiered.mutex.Lock()
+	defer tiered.mutex.Unlock()
+	for tier, table := range tiered.tiers {
+		for index := range table.len() {
+			if !table.excluded(index) {
+				return tier
+			}
+		}
+	}
+	return -1
+}
+
+// Next selects an item from the highest-priority tier with an available
+// item.
+//
+// Panics:
+//   - If every item is unavailable. Use TryNext to avoid this.
+func (tiered *Tiered[TItem]) Next() TItem {
+	item, ok := tiered.TryNext()
+	if !ok {
+		panic("every item is unavailable")
+	}
+	return item
+}
+
+// TryNext selects an item from the highest-priority tier with an available
+// item. If every item is unavailable, it returns false.
+func (tiered *Tiered[TItem]) TryNext() (TItem, bool) {
+	tiered.mutex.Lock()
+	defer tiered.mutex.Unlock()
+	for _, table := range tiered.tiers {
+		if index, ok := table.next(); ok {
+			return table.item(index), true
+		}
+	}
+	var zero TItem
+	return zero, false
+}
@@ -0,0 +1,68 @@
+package weightedrand_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiered(t *testing.T) {
+	primary := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 3},
+		{Item: Orange, Weight: 1},
+	}
+	backup := []WeightedItem[MarbleColor, int]{
+		{Item: Blue, Weight: 1},
+		{Item: Green, Weight: 1},
+	}
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTiered[MarbleColor, int](nil)
+		})
+		assert.Panics(t, func() {
+			NewTiered(nil, primary, nil)
+		})
+	})
+	t.Run("fallback", func(t *testing.T) {
+		tiered := NewTiered(rand.New(rand.NewSource(42)), primary, backup)
+		counts := make(MarbleColorCounts)
+		for range 4_000 {
+			counts[tiered.Next()]++
+		}
+		assert.InDelta(t, 3_000, counts[Red], 150)
+		assert.InDelta(t, 1_000, counts[Orange], 150)
+		assert.Zero(t, counts[Blue]+counts[Green])
+		assert.Equal(t, 0, tiered.Active())
+
+		tiered.SetAvailable(Red, false)
+		for range 100 {
+			assert.Equal(t, Orange, tiered.Next())
+		}
+		tiered.SetAvailable(Orange, false)
+		assert.Equal(t, 1, tiered.Active())
+		counts = make(MarbleColorCounts)
+		for range 2_000 {
+			counts[tiered.Next()]++
+		}
+		assert.InDelta(t, 1_000, counts[Blue], 150)
+		assert.InDelta(t, 1_000, counts[Green], 150)
+
+		tiered.SetAvailable(Red, true)
+		assert.Equal(t, 0, tiered.Active())
+		assert.Equal(t, Red, tiered.Next())
+	})
+	t.Run("every item unavailable", func(t *testing.T) {
+		tiered := NewTiered(rand.New(rand.NewSource(42)), primary, backup)
+		for _, color := range []MarbleColor{Red, Orange, Blue, Green} {
+			tiered.SetAvailable(color, false)
+		}
+		assert.Equal(t, -1, tiered.Active())
+		_, ok := tiered.TryNext()
+		assert.False(t, ok)
+		assert.Panics(t, func() {
+			tiered.Next()
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package weightedrand
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// The attribute keys of the span events recorded for draws, following the
+// naming conventions of OpenTelemetry, so that draws are annotated alike
+// across services.
+const (
+	TraceAttributeItem        = "weightedrand.item"
+	TraceAttributeProbability = "weightedrand.probability"
+	TraceAttributeFingerprint = "weightedrand.fingerprint"
+)
+
+// DrawAnnotation describes a single draw of a Traced chooser.
+type DrawAnnotation[TItem comparable] struct {
+	// Item is the selected item.
+	Item TItem
+	// Probability is the probability the item had to be selected, as
+	// resolved by the coin tosses of the chooser, which may differ from its
+	// share of the weights for choosers built by the decimal engine.
+	Probability decimal.Decimal
+	// Fingerprint identifies the distribution the item was selected from,
+	// as returned by Fingerprint.
+	Fingerprint string
+}
+
+// Traced is a WeightedRandom that annotates every draw made in a request
+// path, such as on the span of the request, so that weighted routing
+// decisions can be correlated in distributed traces. The package does not
+// depend on a tracing library; the draws are passed to a callback, which
+// records them with the tracer of the application.
+//
+// Traced is safe for concurrent use, given that the wrapped instance and the
+// callback are.
+type Traced[TItem comparable] struct {
+	wr            WeightedRandom[TItem]
+	record        func(context.Context, DrawAnnotation[TItem])
+	probabilities map[TItem]decimal.Decimal
+	fingerprint   string
+}
+
+// NewTraced constructs a new Traced instance over the WeightedRandom
+// instance, which records every draw made with NextCtx.
+//
+// The recorded probabilities are those the chooser actually selects with:
+// for choosers whose coin tosses have a resolution of 1/100, such as those
+// built by NewAliasVoseMethod, they are resolved like Starved resolves them,
+// and for the integer engine or WithExactThresholds they are exact.
+//
+// The function panics if the chooser was not constructed by this package,
+// and its distribution cannot be determined.
+//
+// Type Parameters:
+//   - TItem: The type of the items to be sampled.
+//
+// Parameters:
+//   - wr:     The WeightedRandom instance whose draws are recorded.
+//   - record: A function recording a draw within the context it was made in.
+//
+// Example usage:
+//
+//	traced := NewTraced(wr, func(ctx context.Context, draw DrawAnnotation[string]) {
+//		trace.SpanFromContext(ctx).AddEvent("weightedrand.next", trace.WithAttributes(
+//			attribute.String(TraceAttributeItem, draw.Item),
+//			attribute.Float64(TraceAttributeProbability, draw.Probability.InexactFloat64()),
+//			attribute.String(TraceAttributeFingerprint, draw.Fingerprint),
+//		))
+//	})
+//	backend := traced.NextCtx(r.Context())
+func NewTraced[TItem comparable](wr WeightedRandom[TItem], record func(context.Context, DrawAnnotation[TItem])) *Traced[TItem] {
+	weights := mergedWeights(distributionOf(wr))
+	if sampler, ok := wr.(starvingSampler[TItem]); ok {
+		if shares := sampler.resolvedShares(); len(shares) > 0 {
+			weights = make(map[TItem]decimal.Decimal, len(weights))
+			for _, share := range shares {
+				weights[share.Item] = weights[share.Item].Add(share.Weight)
+			}
+		}
+	}
+	total := decimal.Zero
+	for _, weight := range weights {
+		total = total.Add(weight)
+	}
+	probabilities := make(map[TItem]decimal.Decimal, len(weights))
+	for item, weight := range weights {
+		probabilities[item] = weight.DivRound(total, probabilityScale)
+	}
+	return &Traced[TItem]{
+		wr:            wr,
+		record:        record,
+		probabilities: probabilities,
+		fingerprint:   Fingerprint(wr),
+	}
+}
+
+// Next selects an item from the wrapped instance, and records the draw
+// without a context of its own.
+func (traced *Traced[TItem]) Next() TItem {
+	return traced.NextCtx(context.Background())
+}
+
+// NextCtx selects an item from the wrapped instance, and records the draw
+// within the context, such as that of the request it is made for.
+func (traced *Traced[TItem]) NextCtx(ctx context.Context) TItem {
+	item := traced.wr.Next()
+	traced.record(ctx, DrawAnnotation[TItem]{
+		Item:        item,
+		Probability: traced.probabilities[item],
+		Fingerprint: traced.fingerprint,
+	})
+	return item
+}
+
+// Fingerprint returns a short hexadecimal digest of the distribution the
+// chooser selects from, which is the same for choosers that select their
+// items with the same probabilities, regardless of the order the items were
+// provided in or the engine the table was built with. Items are identified
+// by their formatted representation (%v).
+//
+// Panics:
+//   - If the chooser was not constructed by this package, and its
+//     distribution cannot be determined.
+func Fingerprint[TItem any](wr WeightedRandom[TItem]) string {
+	merged := make(map[string]decimal.Decimal)
+	total := decimal.Zero
+	for _, item := range distributionOf(wr).weights() {
+		key := fmt.Sprintf("%v", item.Item)
+		merged[key] = merged[key].Add(item.Weight)
+		total = total.Add(item.Weight)
+	}
+	keys := make([]string, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, cmp.Compare)
+	hash := sha256.New()
+	for _, key := range keys {
+		// Probabilities are rounded coarser than the engines resolve them,
+		// so that the same distribution digests alike.
+		fmt.Fprintf(hash, "%q=%s\n", key, merged[key].DivRound(total, 12).String())
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
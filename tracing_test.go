@@ -0,0 +1,93 @@
+package weightedrand_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraced(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 3},
+		{Item: Blue, Weight: 1},
+	}
+	wr := NewAliasVoseMethod(random, items...)
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTraced[int](opaqueWeightedRandom{}, func(context.Context, DrawAnnotation[int]) {})
+		})
+	})
+	t.Run("records draws", func(t *testing.T) {
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "request-1")
+		var draws []DrawAnnotation[MarbleColor]
+		traced := NewTraced(wr, func(ctx context.Context, draw DrawAnnotation[MarbleColor]) {
+			assert.Equal(t, "request-1", ctx.Value(key{}))
+			draws = append(draws, draw)
+		})
+		for range 100 {
+			item := traced.NextCtx(ctx)
+			require.NotEmpty(t, draws)
+			draw := draws[len(draws)-1]
+			assert.Equal(t, item, draw.Item)
+			assert.Equal(t, Fingerprint(wr), draw.Fingerprint)
+			if item == Red {
+				assert.Equal(t, "0.75", draw.Probability.String())
+			} else {
+				assert.Equal(t, "0.25", draw.Probability.String())
+			}
+		}
+		assert.Len(t, draws, 100)
+	})
+	t.Run("records resolved probabilities", func(t *testing.T) {
+		rare := []WeightedItem[MarbleColor, int]{
+			{Item: Red, Weight: 1},
+			{Item: Blue, Weight: 999},
+		}
+		probabilities := func(wr WeightedRandom[MarbleColor]) map[MarbleColor]string {
+			recorded := make(map[MarbleColor]string)
+			traced := NewTraced(wr, func(_ context.Context, draw DrawAnnotation[MarbleColor]) {
+				recorded[draw.Item] = draw.Probability.String()
+			})
+			for range 10_000 {
+				traced.Next()
+			}
+			return recorded
+		}
+		// The coin tosses of the decimal engine resolve the share of 0.002
+		// of the bucket of red to 1/100.
+		recorded := probabilities(NewAliasVoseMethod(random, rare...))
+		assert.Equal(t, "0.005", recorded[Red])
+		assert.Equal(t, "0.995", recorded[Blue])
+		recorded = probabilities(NewAliasVoseMethodWithOptions(random, rare, WithExactThresholds()))
+		assert.Equal(t, "0.001", recorded[Red])
+		assert.Equal(t, "0.999", recorded[Blue])
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	a := NewAliasVoseMethod(random,
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 3},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+	)
+	b := NewAliasVoseMethodWithOptions(random, []WeightedItem[MarbleColor, int]{
+		{Item: Blue, Weight: 2},
+		{Item: Red, Weight: 6},
+	}, WithIntegerEngine())
+	c := NewAliasVoseMethod(random,
+		WeightedItem[MarbleColor, int]{Item: Red, Weight: 1},
+		WeightedItem[MarbleColor, int]{Item: Blue, Weight: 1},
+	)
+	assert.Len(t, Fingerprint(a), 16)
+	assert.Equal(t, Fingerprint(a), Fingerprint(b))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(c))
+	assert.Panics(t, func() {
+		Fingerprint[int](opaqueWeightedRandom{})
+	})
+}
@@ -0,0 +1,108 @@
+package weightedrand
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transformer downsamples a stream of events, such as the messages of a
+// Kafka consumer or the lines of a log shipper, by keeping every event with
+// the keep-rate of its type. By default, every event is kept or dropped at
+// random. In keyed mode, configured with WithKey, the decision is derived
+// from a key of the event instead, such as a trace or user ID, so that
+// events with the same key are always kept or dropped alike, across
+// processes and restarts. Keys kept at a rate are also kept at every higher
+// rate, so the events of a key are kept together even across types with
+// different rates.
+//
+// Transformer is safe for concurrent use, given that the random number
+// generator is.
+type Transformer[TEvent any] struct {
+	mutex       sync.RWMutex
+	random      RandIntN
+	typeOf      func(TEvent) string
+	keyOf       func(TEvent) string
+	rates       map[string]decimal.Decimal
+	defaultRate decimal.Decimal
+}
+
+// NewTransformer constructs a new Transformer, which keeps events of the
+// types without a keep-rate of their own at the default rate.
+//
+// Panics:
+//   - If the default rate is not within [0, 1].
+//
+// Type Parameters:
+//   - TEvent: The type of the events.
+//
+// Parameters:
+//   - random:      A RandIntN implementation used for random number generation.
+//   - typeOf:      A function returning the type of an event.
+//   - defaultRate: The probability of keeping events of types without a keep-rate.
+//
+// Example usage:
+//
+//	t := NewTransformer(randSource, func(e LogEvent) string { return e.Level }, decimal.NewFromFloat(0.01)).
+//		WithKeepRate("error", decimal.NewFromInt(1)).
+//		WithKeepRate("warn", decimal.NewFromFloat(0.25)).
+//		WithKey(func(e LogEvent) string { return e.TraceID })
+//	if t.Process(event) {
+//		ship(event)
+//	}
+func NewTransformer[TEvent any](random RandIntN, typeOf func(TEvent) string, defaultRate decimal.Decimal) *Transformer[TEvent] {
+	validateProbability(defaultRate)
+	return &Transformer[TEvent]{
+		random:      random,
+		typeOf:      typeOf,
+		rates:       make(map[string]decimal.Decimal),
+		defaultRate: defaultRate,
+	}
+}
+
+// WithKeepRate sets the probability of keeping events of the type. It
+// returns the Transformer to allow chaining.
+//
+// Panics:
+//   - If the rate is not within [0, 1].
+func (transformer *Transformer[TEvent]) WithKeepRate(eventType string, rate decimal.Decimal) *Transformer[TEvent] {
+	validateProbability(rate)
+	transformer.mutex.Lock()
+	defer transformer.mutex.Unlock()
+	transformer.rates[eventType] = rate
+	return transformer
+}
+
+// WithKey switches the Transformer to keyed mode, deriving the decision of
+// every event from its key rather than at random. It returns the
+// Transformer to allow chaining.
+func (transformer *Transformer[TEvent]) WithKey(key func(TEvent) string) *Transformer[TEvent] {
+	transformer.mutex.Lock()
+	defer transformer.mutex.Unlock()
+	transformer.keyOf = key
+	return transformer
+}
+
+// Process reports whether the event is kept.
+func (transformer *Transformer[TEvent]) Process(event TEvent) bool {
+	transformer.mutex.RLock()
+	rate, ok := transformer.rates[transformer.typeOf(event)]
+	if !ok {
+		rate = transformer.defaultRate
+	}
+	keyOf := transformer.keyOf
+	transformer.mutex.RUnlock()
+	if rate.IsZero() {
+		return false
+	} else if rate.Equal(One) {
+		return true
+	}
+	random := transformer.random
+	if keyOf != nil {
+		hash := fnv.New64a()
+		hash.Write([]byte(keyOf(event)))
+		random = newCounterRandom(hash.Sum64(), 0)
+	}
+	return uniformDecimal(random).LessThan(rate)
+}
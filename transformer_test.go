@@ -0,0 +1,69 @@
+package weightedrand_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixtureEvent struct {
+	Type string
+	ID   string
+}
+
+func TestTransformer(t *testing.T) {
+	typeOf := func(event fixtureEvent) string { return event.Type }
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewTransformer(nil, typeOf, decimal.NewFromInt(2))
+		})
+		assert.Panics(t, func() {
+			NewTransformer(nil, typeOf, decimal.Zero).WithKeepRate("error", decimal.NewFromInt(-1))
+		})
+	})
+	t.Run("keep rates", func(t *testing.T) {
+		transformer := NewTransformer(rand.New(rand.NewSource(time.Now().Unix())), typeOf, decimal.NewFromFloat(0.1)).
+			WithKeepRate("error", decimal.NewFromInt(1)).
+			WithKeepRate("debug", decimal.Zero).
+			WithKeepRate("warn", decimal.NewFromFloat(0.5))
+		const iterations = 100_000
+		kept := make(map[string]int)
+		for i := range iterations {
+			for _, eventType := range []string{"error", "debug", "warn", "info"} {
+				if transformer.Process(fixtureEvent{Type: eventType, ID: fmt.Sprint(i)}) {
+					kept[eventType]++
+				}
+			}
+		}
+		assert.Equal(t, iterations, kept["error"])
+		assert.Zero(t, kept["debug"])
+		assert.InDelta(t, 0.5, float64(kept["warn"])/iterations, tolerance)
+		assert.InDelta(t, 0.1, float64(kept["info"])/iterations, tolerance)
+	})
+	t.Run("keyed", func(t *testing.T) {
+		newTransformer := func() *Transformer[fixtureEvent] {
+			return NewTransformer(rand.New(rand.NewSource(time.Now().UnixNano())), typeOf, decimal.NewFromFloat(0.1)).
+				WithKeepRate("warn", decimal.NewFromFloat(0.5)).
+				WithKey(func(event fixtureEvent) string { return event.ID })
+		}
+		first, second := newTransformer(), newTransformer()
+		const iterations = 100_000
+		kept := 0
+		for i := range iterations {
+			info := fixtureEvent{Type: "info", ID: fmt.Sprint(i)}
+			keep := first.Process(info)
+			assert.Equal(t, keep, second.Process(info))
+			assert.Equal(t, keep, first.Process(info))
+			if keep {
+				kept++
+				assert.True(t, first.Process(fixtureEvent{Type: "warn", ID: info.ID}))
+			}
+		}
+		assert.InDelta(t, 0.1, float64(kept)/iterations, tolerance)
+	})
+}
@@ -0,0 +1,76 @@
+package weightedrand
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// uint128 is an unsigned 128-bit integer, which allows the integer engine to
+// hold total weights beyond the range of int64 exactly. Every operation that
+// may overflow reports it, rather than wrapping around.
+type uint128 struct {
+	hi, lo uint64
+}
+
+// mul64 returns the full product of a and b, which cannot overflow.
+func mul64(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	return uint128{hi: hi, lo: lo}
+}
+
+// add returns the sum, and reports whether it overflowed.
+func (u uint128) add(v uint128) (uint128, bool) {
+	lo, carry := bits.Add64(u.lo, v.lo, 0)
+	hi, carry := bits.Add64(u.hi, v.hi, carry)
+	return uint128{hi: hi, lo: lo}, carry != 0
+}
+
+// sub returns the difference, which must not be negative.
+func (u uint128) sub(v uint128) uint128 {
+	lo, borrow := bits.Sub64(u.lo, v.lo, 0)
+	hi, _ := bits.Sub64(u.hi, v.hi, borrow)
+	return uint128{hi: hi, lo: lo}
+}
+
+func (u uint128) less(v uint128) bool {
+	return u.hi < v.hi || (u.hi == v.hi && u.lo < v.lo)
+}
+
+func (u uint128) bitLen() int {
+	if u.hi != 0 {
+		return 64 + bits.Len64(u.hi)
+	}
+	return bits.Len64(u.lo)
+}
+
+func (u uint128) big() *big.Int {
+	value := new(big.Int).SetUint64(u.hi)
+	value.Lsh(value, 64)
+	return value.Or(value, new(big.Int).SetUint64(u.lo))
+}
+
+// randomUint128 returns a uniformly random value within [0, bound), by
+// drawing as many random bits as the bound has, and rejecting values beyond
+// it. Fewer than two attempts are needed on average.
+func randomUint128(random RandIntN, bound uint128) uint128 {
+	// Int63n cannot draw 63 uniform bits, so every draw provides 62.
+	const bitsPerDraw = 62
+	length := bound.bitLen()
+	for {
+		var value uint128
+		for filled := 0; filled < length; filled += bitsPerDraw {
+			value.hi = value.hi<<bitsPerDraw | value.lo>>(64-bitsPerDraw)
+			value.lo = value.lo<<bitsPerDraw | uint64(random.Int63n(1<<bitsPerDraw))
+		}
+		// Discard the bits beyond the length of the bound.
+		if length <= 64 {
+			value.hi = 0
+			value.lo &= 1<<length - 1
+		} else {
+			value.hi &= 1<<(length-64) - 1
+		}
+		if value.less(bound) {
+			return value
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package weightedrand
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The helpers below build weights from common units, each scaled to a
+// single base unit: rates are per second, sizes are in bytes, and amounts of
+// currency are in major units. Every helper returns a plain decimal.Decimal,
+// so the type system does not prevent mixing a rate with a size in one
+// table; keeping the weights of a table in one unit is up to the caller.
+//
+// A quantity of zero is rejected with ErrNonPositiveQuantity rather than
+// returned as a weight of zero, which the constructors would assume to be 1.
+// To exclude such items, leave them out of the table, or use a constructor
+// for which a weight of zero excludes an item, such as NewMetricWeighter.
+
+// WeightFromRate returns the weight of a rate in requests per second.
+//
+// Errors:
+//   - ErrNonPositiveQuantity, wrapped, if the rate is zero or negative.
+//   - ErrNonFiniteWeight, wrapped, if the rate is not finite.
+//
+// Example usage:
+//
+//	weight, err := WeightFromRate(1250.5)
+//	if err != nil {
+//		return err
+//	}
+//	item := WeightedItem[string, decimal.Decimal]{Item: "us-east", Weight: weight}
+func WeightFromRate(qps float64) (decimal.Decimal, error) {
+	if math.IsNaN(qps) || math.IsInf(qps, 0) {
+		return decimal.Zero, fmt.Errorf("rate was %g: %w", qps, ErrNonFiniteWeight)
+	} else if qps <= 0 {
+		return decimal.Zero, fmt.Errorf("rate was %g: %w", qps, ErrNonPositiveQuantity)
+	}
+	return decimal.NewFromFloat(qps), nil
+}
+
+// WeightFromRatePer returns the weight of a rate of count events per
+// interval, such as 300 per minute, scaled to events per second like
+// WeightFromRate.
+//
+// Errors:
+//   - The errors of WeightFromRate for the count.
+//   - An error if the interval is not positive.
+func WeightFromRatePer(count float64, interval time.Duration) (decimal.Decimal, error) {
+	if interval <= 0 {
+		return decimal.Zero, fmt.Errorf("interval must be positive, but was %s", interval)
+	}
+	rate, err := WeightFromRate(count)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return rate.Mul(decimal.NewFromInt(int64(time.Second))).
+		DivRound(decimal.NewFromInt(int64(interval)), probabilityScale), nil
+}
+
+// WeightFromBytes returns the weight of a size in bytes.
+//
+// Errors:
+//   - ErrNonPositiveQuantity, wrapped, if the size is zero or negative.
+func WeightFromBytes(n int64) (decimal.Decimal, error) {
+	if n <= 0 {
+		return decimal.Zero, fmt.Errorf("size was %d bytes: %w", n, ErrNonPositiveQuantity)
+	}
+	return decimal.NewFromInt(n), nil
+}
+
+// WeightFromCurrency returns the weight of an amount of currency in major
+// units, such as dollars, whose minor units have the given number of
+// decimal places, such as 2 for cents.
+//
+// Errors:
+//   - ErrNonPositiveQuantity, wrapped, if the amount is zero or negative.
+//   - An error if the amount has more decimal places than the currency,
+//     which suggests it is not in major units.
+func WeightFromCurrency(amount decimal.Decimal, decimalPlaces int32) (decimal.Decimal, error) {
+	if !amount.IsPositive() {
+		return decimal.Zero, fmt.Errorf("amount was %s: %w", amount.String(), ErrNonPositiveQuantity)
+	} else if !amount.Equal(amount.Truncate(decimalPlaces)) {
+		return decimal.Zero, fmt.Errorf("amount %s has more than %d decimal places", amount.String(), decimalPlaces)
+	}
+	return amount, nil
+}
+
+// WeightFromMinorUnits returns the weight of an amount of currency given in
+// minor units, such as cents, scaled to major units like
+// WeightFromCurrency.
+//
+// Errors:
+//   - ErrNonPositiveQuantity, wrapped, if the amount is zero or negative.
+//
+// Example usage:
+//
+//	weight, err := WeightFromMinorUnits(order.TotalCents, 2)
+func WeightFromMinorUnits(amount int64, decimalPlaces int32) (decimal.Decimal, error) {
+	if amount <= 0 {
+		return decimal.Zero, fmt.Errorf("amount was %d minor units: %w", amount, ErrNonPositiveQuantity)
+	}
+	return decimal.New(amount, -decimalPlaces), nil
+}
@@ -0,0 +1,64 @@
+package weightedrand_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	. "github.com/nikole-dunixi/weightedrand"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnits(t *testing.T) {
+	t.Run("errors", func(t *testing.T) {
+		_, err := WeightFromRate(-1)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromRate(0)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromRate(math.Inf(1))
+		assert.ErrorIs(t, err, ErrNonFiniteWeight)
+		_, err = WeightFromRate(math.NaN())
+		assert.ErrorIs(t, err, ErrNonFiniteWeight)
+		_, err = WeightFromRatePer(1, 0)
+		assert.Error(t, err)
+		_, err = WeightFromRatePer(0, time.Minute)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromBytes(-1)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromBytes(0)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromCurrency(FixtureDecimal(t, "1.005"), 2)
+		assert.Error(t, err)
+		_, err = WeightFromCurrency(decimal.Zero, 2)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromMinorUnits(-1, 2)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+		_, err = WeightFromMinorUnits(0, 2)
+		assert.ErrorIs(t, err, ErrNonPositiveQuantity)
+	})
+	t.Run("rates", func(t *testing.T) {
+		assert.Equal(t, "1250.5", mustWeight(t)(WeightFromRate(1250.5)).String())
+		assert.True(t, mustWeight(t)(WeightFromRatePer(300, time.Minute)).Equal(mustWeight(t)(WeightFromRate(5))))
+		assert.Equal(t, "0.5", mustWeight(t)(WeightFromRatePer(1, 2*time.Second)).String())
+	})
+	t.Run("bytes", func(t *testing.T) {
+		assert.Equal(t, "1048576", mustWeight(t)(WeightFromBytes(1<<20)).String())
+	})
+	t.Run("currency", func(t *testing.T) {
+		assert.True(t, mustWeight(t)(WeightFromCurrency(FixtureDecimal(t, "12.30"), 2)).Equal(mustWeight(t)(WeightFromMinorUnits(1230, 2))))
+		assert.Equal(t, "1230", mustWeight(t)(WeightFromCurrency(FixtureDecimal(t, "1230"), 0)).String())
+	})
+}
+
+// mustWeight returns a function that fails the test if a unit helper
+// returned an error, and otherwise returns the weight.
+func mustWeight(t *testing.T) func(decimal.Decimal, error) decimal.Decimal {
+	t.Helper()
+	return func(weight decimal.Decimal, err error) decimal.Decimal {
+		t.Helper()
+		require.NoError(t, err)
+		return weight
+	}
+}
@@ -2,6 +2,8 @@ package weightedrand
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"slices"
 	"strings"
 
@@ -22,6 +24,10 @@ type Weight interface {
 	int | int8 | int16 | int32 | int64 |
 		// unsigned integers
 		uint | uint8 | uint16 | uint32 | uint64 |
+		// floating-point numbers, such as computed scores
+		float32 | float64 |
+		// arbitrary precision numbers, such as exact rational odds
+		*big.Int | *big.Rat | *big.Float |
 		// support for decimal.Decimal itself
 		decimal.Decimal
 }
@@ -34,6 +40,27 @@ type WeightedRandom[T any] interface {
 	Next() T
 }
 
+// RandomInjectable is implemented by the WeightedRandom instances whose
+// table is immutable, and can be sampled with a random number generator
+// provided per call rather than the one provided at construction. This
+// allows a single table to be shared, such as by requests that each derive a
+// deterministic generator from their ID, instead of building a table per
+// generator.
+//
+// The instances returned by NewAliasVoseMethod, NewAliasVoseMethodWithOptions,
+// NewFloat64AliasMethod, NewSeededFromString and Convolve implement it. When
+// a table is only sampled with NextUsing, the random number generator
+// provided at construction may be nil.
+//
+// Example usage:
+//
+//	table := NewAliasVoseMethod(nil, items...).(RandomInjectable[string])
+//	item := table.NextUsing(NewRandFromString(requestID))
+type RandomInjectable[T any] interface {
+	WeightedRandom[T]
+	NextUsing(random RandIntN) T
+}
+
 // RandIntN defines an interface for random number generators that can produce
 // non-negative pseudo-random integers less than a specified value. It provides
 // methods for generating both int and int64 values within a given range.
@@ -62,7 +89,7 @@ type weightedItem[TItem any] struct {
 
 func (item WeightedItem[TItem, TWeight]) String() string {
 	return fmt.Sprintf(
-		"{weight: %d, item: %v}",
+		"{weight: %v, item: %v}",
 		item.Weight,
 		item.Item,
 	)
@@ -105,21 +132,110 @@ func (tuple aliasTuple[TItem]) String() string {
 //   - WeightedRandom[TItem]: An implementation that supports efficient weighted random selection.
 //
 // Panics:
-//   - If no items are provided or weights are negative.
+//   - If no items are provided or weights are negative. Use
+//     NewAliasVoseMethodE to receive an error instead.
 //
 // Example usage:
 //
 //	wr := NewAliasVoseMethod(randSource, WeightedItem{Item: "A", Weight: 2}, WeightedItem{Item: "B", Weight: 3})
 func NewAliasVoseMethod[TItem any, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) WeightedRandom[TItem] {
+	return newVoseAliasMethod(random, items)
+}
+
+// NewAliasVoseMethodE is like NewAliasVoseMethod, but returns an error
+// rather than panicking, for items that come from configuration or user
+// input and may be invalid.
+//
+// Returns:
+//   - WeightedRandom[TItem]: An implementation that supports efficient weighted random selection.
+//   - error:                 ErrNoItems if no items are provided, or an *ErrNegativeWeight if weights are negative.
+//
+// Example usage:
+//
+//	wr, err := NewAliasVoseMethodE(randSource, config.Backends...)
+//	if err != nil {
+//		return fmt.Errorf("invalid backends: %w", err)
+//	}
+func NewAliasVoseMethodE[TItem any, TWeight Weight](random RandIntN, items ...WeightedItem[TItem, TWeight]) (WeightedRandom[TItem], error) {
+	aliasMethod, err := buildVoseAliasMethod(random, items)
+	if err != nil {
+		return nil, err
+	}
+	return aliasMethod, nil
+}
+
+func newVoseAliasMethod[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight]) voseAliasMethodRandom[TItem] {
+	aliasMethod, err := buildVoseAliasMethod(random, items)
+	if err != nil {
+		panic(err.Error())
+	}
+	return aliasMethod
+}
+
+// cancelInterval is the number of iterations between checks of the context
+// by long-running operations, which keeps the cost of the checks negligible.
+const cancelInterval = 1 << 12
+
+// buildVoseAliasMethod constructs the alias table, and returns an error
+// rather than panicking if no items are provided or weights are negative.
+func buildVoseAliasMethod[TItem any, TWeight Weight](random RandIntN, items []WeightedItem[TItem, TWeight]) (voseAliasMethodRandom[TItem], error) {
+	return buildVoseAliasMethodContext(buildContext{}, random, items)
+}
+
+// buildVoseAliasMethodContext is like buildVoseAliasMethod, but reports its
+// progress to the build context, and stops early with the error of its
+// context when it is done.
+func buildVoseAliasMethodContext[TItem any, TWeight Weight](build buildContext, random RandIntN, items []WeightedItem[TItem, TWeight]) (voseAliasMethodRandom[TItem], error) {
 	if len(items) == 0 {
-		panic("at least one item must be provided")
+		return voseAliasMethodRandom[TItem]{}, ErrNoItems
+	}
+	decimalItems := make([]weightedItem[TItem], 0, len(items))
+	for index, currentItem := range items {
+		if index%cancelInterval == 0 && build.ctx != nil {
+			if err := build.ctx.Err(); err != nil {
+				return voseAliasMethodRandom[TItem]{}, err
+			}
+		}
+		weight, err := decimalWeight(index, currentItem.Weight)
+		if err != nil {
+			return voseAliasMethodRandom[TItem]{}, err
+		}
+		decimalItems = append(decimalItems, weightedItem[TItem]{
+			Item:   currentItem.Item,
+			Weight: weight,
+		})
 	}
+	return newVoseAliasMethodFromDecimalsContext(build, random, decimalItems)
+}
+
+// newVoseAliasMethodFromDecimals constructs the alias table from items whose
+// weights have already been converted, and are known to be positive.
+func newVoseAliasMethodFromDecimals[TItem any](random RandIntN, items []weightedItem[TItem]) voseAliasMethodRandom[TItem] {
+	// The zero build context is never cancelled, so there is never an error.
+	aliasMethod, _ := newVoseAliasMethodFromDecimalsContext(buildContext{}, random, items)
+	return aliasMethod
+}
+
+// newVoseAliasMethodFromDecimalsContext is like
+// newVoseAliasMethodFromDecimals, but reports its progress to the build
+// context, and stops early with the error of its context when it is done.
+func newVoseAliasMethodFromDecimalsContext[TItem any](build buildContext, random RandIntN, items []weightedItem[TItem]) (voseAliasMethodRandom[TItem], error) {
 	// Create two worklists, Small and Large.
-	small, large := createPartitionedItems(items)
+	small, large := createPartitionedItems(items, build.stable)
+	return newVoseAliasMethodFromPartitions(build, random, len(items), small, large)
+}
 
+// newVoseAliasMethodFromPartitions constructs the alias table from the
+// worklists of createPartitionedItems, which hold count items in total.
+func newVoseAliasMethodFromPartitions[TItem any](build buildContext, random RandIntN, count int, small, large []weightedItem[TItem]) (voseAliasMethodRandom[TItem], error) {
 	// Create slices alias and prob, each of size n
-	tuples := make([]aliasTuple[TItem], 0, len(items))
+	tuples := make([]aliasTuple[TItem], 0, count)
 	for ; len(small) > 0 && len(large) > 0; small, large = small[1:], large[1:] {
+		if len(tuples)%cancelInterval == 0 {
+			if err := build.checkpoint(len(tuples), count); err != nil {
+				return voseAliasMethodRandom[TItem]{}, err
+			}
+		}
 		lesser, greater := small[0], large[0]
 		// Using the smaller probability, create the alias for the two items.
 		tuples = append(tuples,
@@ -163,33 +279,23 @@ func NewAliasVoseMethod[TItem any, TWeight Weight](random RandIntN, items ...Wei
 			},
 		)
 	}
+	build.report(len(tuples), count)
 	return voseAliasMethodRandom[TItem]{
 		random: random,
 		tuples: tuples,
-	}
+	}, nil
 }
 
-func createPartitionedItems[TValue any, TWeight Weight](items []WeightedItem[TValue, TWeight]) ([]weightedItem[TValue], []weightedItem[TValue]) {
-	// Create intermediate list to ensure we don't modify the user's
+func createPartitionedItems[TValue any](items []weightedItem[TValue], stable bool) ([]weightedItem[TValue], []weightedItem[TValue]) {
+	// Create intermediate list to ensure we don't modify the caller's
 	// input.
 	itemBuffer := make([]weightedItem[TValue], 0, len(items))
 	totalWeight := decimal.Zero
 	// First pass through the slice creates the duplicate slice
 	// and sums the total weight
 	for _, currentItem := range items {
-		// If no weight is provided, it is assumed to be 1
-		currentWeight := WeightAsDecimal(currentItem.Weight)
-		if currentWeight.Equal(decimal.Zero) {
-			currentWeight = One
-		} else if currentWeight.LessThan(decimal.Zero) {
-			panic(fmt.Sprintf("weight must be non-negative value, but was %s", currentWeight.String()))
-		}
-
-		totalWeight = totalWeight.Add(currentWeight)
-		itemBuffer = append(itemBuffer, weightedItem[TValue]{
-			Item:   currentItem.Item,
-			Weight: currentWeight,
-		})
+		totalWeight = totalWeight.Add(currentItem.Weight)
+		itemBuffer = append(itemBuffer, currentItem)
 	}
 	// Second pass through the slice normalizes the probabilities
 	// and makes them relative to each other.
@@ -202,14 +308,30 @@ func createPartitionedItems[TValue any, TWeight Weight](items []WeightedItem[TVa
 		currentItem.Weight = replacementWeight
 		itemBuffer[i] = currentItem
 	}
+	return partitionItems(itemBuffer, stable)
+}
+
+// partitionItems sorts the items, whose weights are already scaled so that
+// they average 1, and splits them into those below 1 and the others.
+func partitionItems[TValue any](itemBuffer []weightedItem[TValue], stable bool) ([]weightedItem[TValue], []weightedItem[TValue]) {
 	// Sort the items. Find the index of the first item that is >= 1.
-	// Use the index to create sub-slices.
-	slices.SortFunc(itemBuffer, func(a, b weightedItem[TValue]) int {
+	// Use the index to create sub-slices. A stable sort keeps items of equal
+	// weight in the order provided, for tie-breaking.
+	compare := func(a, b weightedItem[TValue]) int {
 		return a.Weight.Cmp(b.Weight)
-	})
+	}
+	if stable {
+		slices.SortStableFunc(itemBuffer, compare)
+	} else {
+		slices.SortFunc(itemBuffer, compare)
+	}
 	index := slices.IndexFunc(itemBuffer, func(item weightedItem[TValue]) bool {
 		return item.Weight.GreaterThanOrEqual(One)
 	})
+	if index < 0 {
+		// Rounding may leave every item just below 1.
+		index = len(itemBuffer)
+	}
 
 	// Copy into dedicated slices. We cannot optimize with subslices, because
 	// we may append items into the list as they are processed.
@@ -222,6 +344,75 @@ func createPartitionedItems[TValue any, TWeight Weight](items []WeightedItem[TVa
 	return resultSmall, resultLarge
 }
 
+// effectiveWeight converts the weight into a decimal.Decimal and applies the
+// defaulting rules shared by every constructor: if no weight is provided, it
+// is assumed to be 1, and negative weights panic.
+func effectiveWeight[TWeight Weight](weight TWeight) decimal.Decimal {
+	currentWeight := WeightAsDecimal(weight)
+	if currentWeight.IsNegative() {
+		panic(fmt.Sprintf("weight must be non-negative value, but was %s", currentWeight.String()))
+	} else if currentWeight.IsZero() {
+		return One
+	}
+	return currentWeight
+}
+
+// decimalWeight is like effectiveWeight, but returns an *ErrNegativeWeight
+// for the item at the index rather than panicking if the weight is negative.
+func decimalWeight[TWeight Weight](index int, weight TWeight) (decimal.Decimal, error) {
+	if nonFiniteWeight(weight) {
+		return decimal.Zero, fmt.Errorf("weight of item %d is %v: %w", index, weight, ErrNonFiniteWeight)
+	}
+	currentWeight := WeightAsDecimal(weight)
+	if currentWeight.IsNegative() {
+		return decimal.Zero, &ErrNegativeWeight{
+			Index:  index,
+			Weight: currentWeight,
+		}
+	} else if currentWeight.IsZero() {
+		return One, nil
+	}
+	return currentWeight, nil
+}
+
+// nonFiniteWeight reports whether the weight is a floating-point number
+// that is not finite.
+func nonFiniteWeight[TWeight Weight](weight TWeight) bool {
+	switch weight := any(weight).(type) {
+	case float32:
+		return !isFinite(float64(weight))
+	case float64:
+		return !isFinite(weight)
+	case *big.Float:
+		return weight != nil && weight.IsInf()
+	default:
+		return false
+	}
+}
+
+func isFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
+
+// normalizedItems converts the items into their decimal form with weights
+// that are relative to the total weight, so that they sum to 1.
+func normalizedItems[TItem any, TWeight Weight](items []WeightedItem[TItem, TWeight]) []weightedItem[TItem] {
+	result := make([]weightedItem[TItem], 0, len(items))
+	totalWeight := decimal.Zero
+	for _, currentItem := range items {
+		currentWeight := effectiveWeight(currentItem.Weight)
+		totalWeight = totalWeight.Add(currentWeight)
+		result = append(result, weightedItem[TItem]{
+			Item:   currentItem.Item,
+			Weight: currentWeight,
+		})
+	}
+	for i := range result {
+		result[i].Weight = result[i].Weight.Div(totalWeight)
+	}
+	return result
+}
+
 // WeightAsDecimal converts a value of a numeric type implementing the Weight interface
 // into a decimal.Decimal. It supports various integer types (signed and unsigned) as well
 // as decimal.Decimal itself. If the input value is already a decimal.Decimal, it is returned
@@ -263,21 +454,56 @@ func WeightAsDecimal[TWeight Weight](value TWeight) decimal.Decimal {
 		return decimal.NewFromUint64(uint64(value))
 	case uint64:
 		return decimal.NewFromUint64(value)
+	case float32:
+		if !isFinite(float64(value)) {
+			panic(fmt.Sprintf("weight must be a finite number, but was %g", value))
+		}
+		return decimal.NewFromFloat32(value)
+	case float64:
+		if !isFinite(value) {
+			panic(fmt.Sprintf("weight must be a finite number, but was %g", value))
+		}
+		return decimal.NewFromFloat(value)
+	case *big.Int:
+		if value == nil {
+			return decimal.Zero
+		}
+		return decimal.NewFromBigInt(value, 0)
+	case *big.Rat:
+		if value == nil {
+			return decimal.Zero
+		}
+		// Keep as many significant digits as a probability, however small
+		// the rational.
+		return decimal.NewFromBigRat(value, probabilityScale+int32(len(value.Denom().String())))
+	case *big.Float:
+		if value == nil {
+			return decimal.Zero
+		} else if value.IsInf() {
+			panic(fmt.Sprintf("weight must be a finite number, but was %s", value.String()))
+		}
+		return decimal.RequireFromString(value.Text('e', -1))
 	case decimal.Decimal:
 		// If we have a decimal already, we just return it back
 		return value
 	default:
-		panic(fmt.Sprintf("unsupported numerical value %d (%T)", value, value))
+		panic(fmt.Sprintf("unsupported numerical value %v (%T)", value, value))
 	}
 }
 
 func (aliasMethod voseAliasMethodRandom[TItem]) Next() TItem {
+	return aliasMethod.NextUsing(aliasMethod.random)
+}
+
+// NextUsing selects an item by weight, using the random number generator
+// instead of the one provided at construction.
+func (aliasMethod voseAliasMethodRandom[TItem]) NextUsing(random RandIntN) TItem {
 	// First, perform a fair dice roll.
-	fairDiceRoll := aliasMethod.random.Intn(len(aliasMethod.tuples))
+	fairDiceRoll := random.Intn(len(aliasMethod.tuples))
 	fairlyChosenTuple := aliasMethod.tuples[fairDiceRoll]
 	// Second, perform an unfair dice roll.
 	max := int64(100)
-	unfairCoinToss := decimal.NewFromInt(aliasMethod.random.Int63n(max)).
+	unfairCoinToss := decimal.NewFromInt(random.Int63n(max)).
 		Div(decimal.NewFromInt(max))
 	if unfairCoinToss.LessThan(fairlyChosenTuple.probability) {
 		return fairlyChosenTuple.primaryItem
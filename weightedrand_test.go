@@ -2,6 +2,8 @@ package weightedrand_test
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 	"math/rand"
 	"sort"
 	"strings"
@@ -146,8 +148,71 @@ func TestWeightedRand(t *testing.T) {
 			testPanicsWithNegativeWeight[int16](t, -1)
 			testPanicsWithNegativeWeight[int32](t, -1)
 			testPanicsWithNegativeWeight[int64](t, -1)
+			testPanicsWithNegativeWeight[float32](t, -1)
+			testPanicsWithNegativeWeight[float64](t, -0.5)
+			testPanicsWithNegativeWeight[*big.Int](t, big.NewInt(-1))
+			testPanicsWithNegativeWeight[*big.Rat](t, big.NewRat(-1, 3))
+			testPanicsWithNegativeWeight[*big.Float](t, big.NewFloat(-0.5))
 			testPanicsWithNegativeWeight[decimal.Decimal](t, decimal.NewFromInt(-1))
 		})
+		t.Run("items with non-finite weight", func(t *testing.T) {
+			for _, weight := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+				assert.Panics(t, func() {
+					NewAliasVoseMethod(nil, WeightedItem[MarbleColor, float64]{Item: Red, Weight: weight})
+				})
+				_, err := NewAliasVoseMethodE(nil, WeightedItem[MarbleColor, float64]{Item: Red, Weight: weight})
+				assert.ErrorIs(t, err, ErrNonFiniteWeight)
+			}
+			assert.Panics(t, func() {
+				NewAliasVoseMethod(nil, WeightedItem[MarbleColor, float32]{Item: Red, Weight: float32(math.NaN())})
+			})
+			_, err := NewAliasVoseMethodE(nil, WeightedItem[MarbleColor, *big.Float]{Item: Red, Weight: new(big.Float).SetInf(false)})
+			assert.ErrorIs(t, err, ErrNonFiniteWeight)
+		})
+	})
+	t.Run("items with arbitrary precision weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		const iterations = 100_000
+		counts := Counts(NewAliasVoseMethod(r,
+			WeightedItem[MarbleColor, *big.Rat]{Item: Blue, Weight: big.NewRat(1, 3)},
+			WeightedItem[MarbleColor, *big.Rat]{Item: Red, Weight: big.NewRat(2, 3)},
+			WeightedItem[MarbleColor, *big.Rat]{Item: Green},
+		), iterations)
+		assert.InDeltaf(t, 1.0/6, float64(counts[Blue])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 1.0/3, float64(counts[Red])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.5, float64(counts[Green])/iterations, tolerance, "%v", counts)
+		huge := new(big.Int).Lsh(big.NewInt(1), 70)
+		counts = Counts(NewAliasVoseMethod(r,
+			WeightedItem[MarbleColor, *big.Int]{Item: Blue, Weight: huge},
+			WeightedItem[MarbleColor, *big.Int]{Item: Red, Weight: new(big.Int).Mul(huge, big.NewInt(3))},
+		), iterations)
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/iterations, tolerance, "%v", counts)
+		counts = Counts(NewAliasVoseMethod(r,
+			WeightedItem[MarbleColor, *big.Float]{Item: Blue, Weight: big.NewFloat(0.5)},
+			WeightedItem[MarbleColor, *big.Float]{Item: Red, Weight: big.NewFloat(1.5)},
+		), iterations)
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/iterations, tolerance, "%v", counts)
+		assert.Panics(t, func() {
+			NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, *big.Rat]{{Item: Blue, Weight: big.NewRat(1, 3)}}, WithIntegerEngine())
+		})
+	})
+	t.Run("items with float weights", func(t *testing.T) {
+		r := rand.New(rand.NewSource(time.Now().Unix()))
+		const iterations = 100_000
+		counts := Counts(NewAliasVoseMethod(r,
+			WeightedItem[MarbleColor, float64]{Item: Blue, Weight: 0.1},
+			WeightedItem[MarbleColor, float64]{Item: Red, Weight: 0.3},
+		), iterations)
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/iterations, tolerance, "%v", counts)
+		assert.InDeltaf(t, 0.75, float64(counts[Red])/iterations, tolerance, "%v", counts)
+		counts = Counts(NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, float32]{
+			{Item: Blue, Weight: 1},
+			{Item: Red, Weight: 3},
+		}, WithIntegerEngine()), iterations)
+		assert.InDeltaf(t, 0.25, float64(counts[Blue])/iterations, tolerance, "%v", counts)
+		assert.Panics(t, func() {
+			NewAliasVoseMethodWithOptions(r, []WeightedItem[MarbleColor, float64]{{Item: Blue, Weight: 0.5}}, WithIntegerEngine())
+		})
 	})
 	t.Run("items with weights", func(t *testing.T) {
 		testWeightedProbabilitiesWithinTolerance(t,
@@ -355,9 +420,62 @@ func testPanicsWithNegativeWeight[TWeight Weight](t *testing.T, weight TWeight)
 	})
 }
 
+func TestNextUsing(t *testing.T) {
+	items := []WeightedItem[MarbleColor, int]{
+		{Item: Red, Weight: 1},
+		{Item: Green, Weight: 2},
+		{Item: Blue, Weight: 3},
+	}
+	probabilities := []float64{1.0 / 6, 2.0 / 6, 3.0 / 6}
+	colors := []MarbleColor{Red, Green, Blue}
+	for name, table := range map[string]WeightedRandom[MarbleColor]{
+		"decimal engine": NewAliasVoseMethod(nil, items...),
+		"integer engine": NewAliasVoseMethodWithOptions(nil, items, WithIntegerEngine()),
+		"float64 engine": NewFloat64AliasMethod(nil, colors, probabilities),
+	} {
+		t.Run(name, func(t *testing.T) {
+			injectable, ok := table.(RandomInjectable[MarbleColor])
+			require.True(t, ok)
+			t.Run("deterministic per generator", func(t *testing.T) {
+				for _, requestID := range []string{"request-1", "request-2"} {
+					first, second := NewRandFromString(requestID), NewRandFromString(requestID)
+					for range 100 {
+						assert.Equal(t, injectable.NextUsing(first), injectable.NextUsing(second))
+					}
+				}
+			})
+			t.Run("distribution", func(t *testing.T) {
+				r := rand.New(rand.NewSource(time.Now().Unix()))
+				counts := make(MarbleColorCounts)
+				for range 100_000 {
+					counts[injectable.NextUsing(r)] += 1
+				}
+				assert.InDeltaf(t, 0.5, float64(counts[Blue])/100_000, tolerance, "%s", counts)
+			})
+		})
+	}
+}
+
 func FixtureDecimal(t *testing.T, v string) decimal.Decimal {
 	t.Helper()
 	result, err := decimal.NewFromString(v)
 	require.NoErrorf(t, err, "testcase had invalid value for expected decimal: %s", v)
 	return result
 }
+
+// FixtureClock is a Clock whose time only changes when advanced.
+type FixtureClock struct {
+	now time.Time
+}
+
+func NewFixtureClock() *FixtureClock {
+	return &FixtureClock{now: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (clock *FixtureClock) Now() time.Time {
+	return clock.now
+}
+
+func (clock *FixtureClock) Advance(duration time.Duration) {
+	clock.now = clock.now.Add(duration)
+}
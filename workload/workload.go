@@ -0,0 +1,319 @@
+// Package workload generates synthetic operations for benchmarking databases
+// and services, in the style of YCSB: a declarative configuration mixes
+// operations by weight, and draws the key and the value size of each
+// operation from its own distribution. A runner emits the operations at a
+// target rate.
+//
+// Workloads can be declared in Go, or loaded from JSON or YAML.
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand"
+	"gopkg.in/yaml.v3"
+)
+
+// The types of key distributions.
+const (
+	// KeysUniform selects every key with the same probability.
+	KeysUniform = "uniform"
+	// KeysZipfian selects the key of rank k with a probability proportional
+	// to 1/k^Skew, so that a few keys are far more popular than the rest.
+	KeysZipfian = "zipfian"
+	// KeysHotspot selects the first HotFraction of the keys for a
+	// HotShare of the operations, and the rest of the keys otherwise, each
+	// uniformly.
+	KeysHotspot = "hotspot"
+)
+
+// Config declares a workload.
+type Config struct {
+	// Rate is the target number of operations per second. If zero, the
+	// runner emits operations as fast as they are handled.
+	Rate float64 `json:"rate,omitempty" yaml:"rate,omitempty"`
+	// Count is the number of operations the runner emits. If zero, it emits
+	// operations until its context is done.
+	Count uint64 `json:"count,omitempty" yaml:"count,omitempty"`
+	// Operations are the operations of the workload, mixed by weight.
+	Operations []Operation `json:"operations" yaml:"operations"`
+}
+
+// Operation declares a kind of operation, such as a read or an update.
+type Operation struct {
+	// Name uniquely identifies the operation, such as "read".
+	Name string `json:"name" yaml:"name"`
+	// Weight is the weight of the operation among the others. If no weight
+	// is provided, it is assumed to be 1.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+	// Keys is the distribution of the keys the operation accesses. If it
+	// has no keys, the operation accesses none.
+	Keys Keys `json:"keys,omitempty" yaml:"keys,omitempty"`
+	// ValueSizes is the distribution of the sizes of the values the
+	// operation writes, in bytes. If empty, the operation writes none.
+	ValueSizes []ValueSize `json:"valueSizes,omitempty" yaml:"valueSizes,omitempty"`
+}
+
+// Keys declares a distribution of keys, each formatted as the prefix
+// followed by the index of the key, such as "user42".
+type Keys struct {
+	// Distribution is the type of the distribution, such as KeysUniform.
+	// If empty, it is assumed to be KeysUniform.
+	Distribution string `json:"distribution,omitempty" yaml:"distribution,omitempty"`
+	// Count is the number of keys, indexed from 0.
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+	// Prefix is prepended to the index of every key.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	// Skew is the exponent of KeysZipfian, which must be positive.
+	Skew float64 `json:"skew,omitempty" yaml:"skew,omitempty"`
+	// HotFraction is the fraction of the keys that are hot for KeysHotspot,
+	// between 0 and 1 exclusive.
+	HotFraction float64 `json:"hotFraction,omitempty" yaml:"hotFraction,omitempty"`
+	// HotShare is the fraction of the operations that access the hot keys
+	// for KeysHotspot, between 0 and 1 exclusive.
+	HotShare float64 `json:"hotShare,omitempty" yaml:"hotShare,omitempty"`
+}
+
+// ValueSize is a size of values, in bytes, with its weight.
+type ValueSize struct {
+	// Size is the size of the values, in bytes.
+	Size int `json:"size" yaml:"size"`
+	// Weight is the weight of the size among the others. If no weight is
+	// provided, it is assumed to be 1.
+	Weight uint64 `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// Op is a single operation generated by a workload.
+type Op struct {
+	// Index is the index of the operation within the run, starting at zero.
+	Index uint64
+	// Name is the name of the operation.
+	Name string
+	// Key is the key the operation accesses, or empty if it accesses none.
+	Key string
+	// ValueSize is the size of the value the operation writes, in bytes.
+	ValueSize int
+}
+
+func (op Op) String() string {
+	return fmt.Sprintf("{index: %d, name: %s, key: %s, valueSize: %d}", op.Index, op.Name, op.Key, op.ValueSize)
+}
+
+type compiledOperation struct {
+	name       string
+	keys       func() string
+	valueSizes weightedrand.WeightedRandom[int]
+}
+
+// Workload generates the operations of a configuration. It is immutable
+// once constructed, and is safe for concurrent use, given that the random
+// number generator is.
+type Workload struct {
+	random     weightedrand.RandIntN
+	rate       float64
+	count      uint64
+	operations weightedrand.WeightedRandom[*compiledOperation]
+}
+
+// New constructs a Workload from its configuration. Operations and value
+// sizes are selected with the integer engine, so that even heavily skewed
+// mixes, such as one write in a thousand reads, are emitted exactly in
+// proportion to their weights.
+//
+// The function panics if no operations are provided, if operation names are
+// empty or duplicated, if the rate is negative, or if a distribution is
+// invalid, such as an unknown type or a non-positive skew.
+//
+// Example usage:
+//
+//	w := workload.New(randSource, workload.Config{
+//		Rate: 1000,
+//		Operations: []workload.Operation{
+//			{Name: "read", Weight: 95, Keys: workload.Keys{Distribution: workload.KeysZipfian, Count: 1_000_000, Prefix: "user", Skew: 0.99}},
+//			{Name: "update", Weight: 5, Keys: workload.Keys{Count: 1_000_000, Prefix: "user"}, ValueSizes: []workload.ValueSize{{Size: 100}}},
+//		},
+//	})
+//	err := w.Run(ctx, func(ctx context.Context, op workload.Op) error {
+//		return db.Execute(ctx, op.Name, op.Key, op.ValueSize)
+//	})
+func New(random weightedrand.RandIntN, config Config) *Workload {
+	workload, err := compile(random, config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return workload
+}
+
+// LoadJSON reads the configuration of a workload from JSON, and constructs
+// the Workload.
+func LoadJSON(random weightedrand.RandIntN, reader io.Reader) (*Workload, error) {
+	var config Config
+	if err := json.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, fmt.Errorf("could not decode workload: %w", err)
+	}
+	return compile(random, config)
+}
+
+// LoadYAML reads the configuration of a workload from YAML, and constructs
+// the Workload.
+func LoadYAML(random weightedrand.RandIntN, reader io.Reader) (*Workload, error) {
+	var config Config
+	if err := yaml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, fmt.Errorf("could not decode workload: %w", err)
+	}
+	return compile(random, config)
+}
+
+func compile(random weightedrand.RandIntN, config Config) (*Workload, error) {
+	if len(config.Operations) == 0 {
+		return nil, errors.New("at least one operation must be provided")
+	} else if config.Rate < 0 || math.IsNaN(config.Rate) || math.IsInf(config.Rate, 0) {
+		return nil, fmt.Errorf("rate must be a finite non-negative value, but was %v", config.Rate)
+	}
+	names := make(map[string]struct{}, len(config.Operations))
+	operations := make([]weightedrand.WeightedItem[*compiledOperation, uint64], 0, len(config.Operations))
+	for _, operation := range config.Operations {
+		if operation.Name == "" {
+			return nil, errors.New("every operation must have a name")
+		} else if _, ok := names[operation.Name]; ok {
+			return nil, fmt.Errorf("operation %q was provided more than once", operation.Name)
+		}
+		names[operation.Name] = struct{}{}
+		keys, err := compileKeys(random, operation.Keys)
+		if err != nil {
+			return nil, fmt.Errorf("operation %q has invalid keys: %w", operation.Name, err)
+		}
+		compiled := &compiledOperation{
+			name: operation.Name,
+			keys: keys,
+		}
+		if len(operation.ValueSizes) > 0 {
+			sizes := make([]weightedrand.WeightedItem[int, uint64], 0, len(operation.ValueSizes))
+			for _, size := range operation.ValueSizes {
+				if size.Size < 0 {
+					return nil, fmt.Errorf("operation %q has a negative value size %d", operation.Name, size.Size)
+				}
+				sizes = append(sizes, weightedrand.WeightedItem[int, uint64]{Item: size.Size, Weight: size.Weight})
+			}
+			compiled.valueSizes = weightedrand.NewAliasVoseMethodWithOptions(random, sizes, weightedrand.WithIntegerEngine())
+		}
+		operations = append(operations, weightedrand.WeightedItem[*compiledOperation, uint64]{
+			Item:   compiled,
+			Weight: operation.Weight,
+		})
+	}
+	return &Workload{
+		random:     random,
+		rate:       config.Rate,
+		count:      config.Count,
+		operations: weightedrand.NewAliasVoseMethodWithOptions(random, operations, weightedrand.WithIntegerEngine()),
+	}, nil
+}
+
+// compileKeys compiles the distribution into a function that selects a key,
+// or nil if there are no keys.
+func compileKeys(random weightedrand.RandIntN, keys Keys) (func() string, error) {
+	if keys.Count < 0 {
+		return nil, fmt.Errorf("count must be non-negative, but was %d", keys.Count)
+	} else if keys.Count == 0 {
+		return nil, nil
+	}
+	format := func(index int) string {
+		return fmt.Sprintf("%s%d", keys.Prefix, index)
+	}
+	switch keys.Distribution {
+	case "", KeysUniform:
+		return func() string {
+			return format(random.Intn(keys.Count))
+		}, nil
+	case KeysZipfian:
+		if !(keys.Skew > 0) || math.IsInf(keys.Skew, 0) {
+			return nil, fmt.Errorf("skew must be a finite positive value, but was %v", keys.Skew)
+		}
+		indices := make([]int, keys.Count)
+		probabilities := make([]float64, keys.Count)
+		for i := range indices {
+			indices[i] = i
+			probabilities[i] = math.Pow(float64(i+1), -keys.Skew)
+		}
+		ranks := weightedrand.NewFloat64AliasMethod(random, indices, probabilities)
+		return func() string {
+			return format(ranks.Next())
+		}, nil
+	case KeysHotspot:
+		if !(keys.HotFraction > 0 && keys.HotFraction < 1) {
+			return nil, fmt.Errorf("hot fraction must be between 0 and 1 exclusive, but was %v", keys.HotFraction)
+		} else if !(keys.HotShare > 0 && keys.HotShare < 1) {
+			return nil, fmt.Errorf("hot share must be between 0 and 1 exclusive, but was %v", keys.HotShare)
+		} else if keys.Count < 2 {
+			return nil, fmt.Errorf("count must be at least 2 to have hot and cold keys, but was %d", keys.Count)
+		}
+		// Both the hot and the cold keys have at least one key.
+		hot := max(1, min(int(float64(keys.Count)*keys.HotFraction), keys.Count-1))
+		isHot := weightedrand.NewFloat64AliasMethod(random, []bool{true, false}, []float64{keys.HotShare, 1 - keys.HotShare})
+		return func() string {
+			if isHot.Next() {
+				return format(random.Intn(hot))
+			}
+			return format(hot + random.Intn(keys.Count-hot))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution %q", keys.Distribution)
+	}
+}
+
+// Next generates the next operation, with the index zero.
+func (workload *Workload) Next() Op {
+	operation := workload.operations.Next()
+	op := Op{Name: operation.name}
+	if operation.keys != nil {
+		op.Key = operation.keys()
+	}
+	if operation.valueSizes != nil {
+		op.ValueSize = operation.valueSizes.Next()
+	}
+	return op
+}
+
+// Run generates operations, and emits them at the target rate of the
+// configuration, until it emitted the configured count of operations, the
+// context is done, or emit returns an error, which it returns. Operations
+// are emitted one at a time; when emit falls behind the target rate, the
+// operations that are due are emitted without delay until it catches up.
+func (workload *Workload) Run(ctx context.Context, emit func(ctx context.Context, op Op) error) error {
+	start := time.Now()
+	var timer *time.Timer
+	for index := uint64(0); workload.count == 0 || index < workload.count; index++ {
+		if workload.rate > 0 {
+			due := start.Add(time.Duration(float64(index) / workload.rate * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				if timer == nil {
+					timer = time.NewTimer(wait)
+					defer timer.Stop()
+				} else {
+					timer.Reset(wait)
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		op := workload.Next()
+		op.Index = index
+		if err := emit(ctx, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
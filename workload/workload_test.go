@@ -0,0 +1,196 @@
+package workload_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nikole-dunixi/weightedrand/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tolerance = 0.02
+
+const testYAML = `
+rate: 10000
+count: 50
+operations:
+  - name: read
+    weight: 3
+    keys:
+      distribution: zipfian
+      count: 100
+      prefix: user
+      skew: 1.2
+  - name: update
+    keys:
+      prefix: user
+      count: 100
+    valueSizes:
+      - size: 100
+        weight: 3
+      - size: 1000
+`
+
+func TestNew(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	t.Run("panic", func(t *testing.T) {
+		assert.PanicsWithValue(t, "at least one operation must be provided", func() {
+			workload.New(r, workload.Config{})
+		})
+		assert.PanicsWithValue(t, `operation "read" was provided more than once`, func() {
+			workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "read"}, {Name: "read"}}})
+		})
+		assert.Panics(t, func() {
+			workload.New(r, workload.Config{Rate: -1, Operations: []workload.Operation{{Name: "read"}}})
+		})
+		assert.PanicsWithValue(t, `operation "read" has invalid keys: unknown distribution "latest"`, func() {
+			workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "read", Keys: workload.Keys{Distribution: "latest", Count: 1}}}})
+		})
+		assert.Panics(t, func() {
+			workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "read", Keys: workload.Keys{Distribution: workload.KeysZipfian, Count: 1}}}})
+		})
+		assert.Panics(t, func() {
+			workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "read", Keys: workload.Keys{Distribution: workload.KeysHotspot, Count: 1, HotFraction: 0.2, HotShare: 0.8}}}})
+		})
+	})
+	t.Run("load errors", func(t *testing.T) {
+		_, err := workload.LoadJSON(r, strings.NewReader("{"))
+		assert.Error(t, err)
+		_, err = workload.LoadYAML(r, strings.NewReader("operations: [{weight: 1}]"))
+		assert.EqualError(t, err, "every operation must have a name")
+	})
+}
+
+func TestNext(t *testing.T) {
+	const samples = 100_000
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	t.Run("operations", func(t *testing.T) {
+		w, err := workload.LoadYAML(r, strings.NewReader(testYAML))
+		require.NoError(t, err)
+		names := map[string]int{}
+		sizes := map[int]int{}
+		for range samples {
+			op := w.Next()
+			names[op.Name]++
+			assert.True(t, strings.HasPrefix(op.Key, "user"), op.Key)
+			if op.Name == "update" {
+				sizes[op.ValueSize]++
+			} else {
+				assert.Zero(t, op.ValueSize)
+			}
+		}
+		assert.InDeltaf(t, 0.75, float64(names["read"])/samples, tolerance, "%v", names)
+		assert.InDeltaf(t, 0.75, float64(sizes[100])/float64(names["update"]), tolerance, "%v", sizes)
+	})
+	t.Run("skewed mix", func(t *testing.T) {
+		const ops = 1_000_000
+		w := workload.New(r, workload.Config{Operations: []workload.Operation{
+			{Name: "read", Weight: 999},
+			{Name: "write", Weight: 1},
+			{Name: "insert", Weight: 1_000, ValueSizes: []workload.ValueSize{
+				{Size: 100, Weight: 999},
+				{Size: 1_000_000, Weight: 1},
+			}},
+		}})
+		names := map[string]int{}
+		large := 0
+		for range ops {
+			op := w.Next()
+			names[op.Name]++
+			if op.ValueSize == 1_000_000 {
+				large++
+			}
+		}
+		// A coin toss of 1/100 resolution would emit writes and large
+		// values several times as often.
+		assert.InDeltaf(t, 0.0005, float64(names["write"])/ops, 0.0001, "%v", names)
+		assert.InDeltaf(t, 0.001, float64(large)/float64(names["insert"]), 0.0002, "%v", names)
+	})
+	t.Run("zipfian", func(t *testing.T) {
+		w := workload.New(r, workload.Config{Operations: []workload.Operation{{
+			Name: "read",
+			Keys: workload.Keys{Distribution: workload.KeysZipfian, Count: 3, Skew: 1},
+		}}})
+		keys := map[string]int{}
+		for range samples {
+			keys[w.Next().Key]++
+		}
+		// The weights are 1, 1/2 and 1/3, which sum to 11/6.
+		assert.InDeltaf(t, 6.0/11, float64(keys["0"])/samples, tolerance, "%v", keys)
+		assert.InDeltaf(t, 2.0/11, float64(keys["2"])/samples, tolerance, "%v", keys)
+	})
+	t.Run("hotspot", func(t *testing.T) {
+		w := workload.New(r, workload.Config{Operations: []workload.Operation{{
+			Name: "read",
+			Keys: workload.Keys{Distribution: workload.KeysHotspot, Count: 10, Prefix: "k", HotFraction: 0.2, HotShare: 0.8},
+		}}})
+		hot := 0
+		for range samples {
+			switch w.Next().Key {
+			case "k0", "k1":
+				hot++
+			}
+		}
+		assert.InDelta(t, 0.8, float64(hot)/samples, tolerance)
+	})
+	t.Run("no keys", func(t *testing.T) {
+		w := workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "scan"}}})
+		assert.Equal(t, workload.Op{Name: "scan"}, w.Next())
+	})
+}
+
+func TestRun(t *testing.T) {
+	r := rand.New(rand.NewSource(time.Now().Unix()))
+	t.Run("count and rate", func(t *testing.T) {
+		w := workload.New(r, workload.Config{
+			Rate:       1_000,
+			Count:      50,
+			Operations: []workload.Operation{{Name: "read"}},
+		})
+		var indices []uint64
+		start := time.Now()
+		err := w.Run(context.Background(), func(_ context.Context, op workload.Op) error {
+			indices = append(indices, op.Index)
+			return nil
+		})
+		require.NoError(t, err)
+		// The last operation is due after 49 intervals of 1ms.
+		assert.GreaterOrEqual(t, time.Since(start), 49*time.Millisecond)
+		assert.Len(t, indices, 50)
+		assert.Equal(t, uint64(49), indices[49])
+	})
+	t.Run("emit error", func(t *testing.T) {
+		w := workload.New(r, workload.Config{Operations: []workload.Operation{{Name: "read"}}})
+		failure := errors.New("failure")
+		emitted := 0
+		err := w.Run(context.Background(), func(_ context.Context, op workload.Op) error {
+			emitted++
+			if op.Index == 9 {
+				return failure
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, failure)
+		assert.Equal(t, 10, emitted)
+	})
+	t.Run("cancelled", func(t *testing.T) {
+		w := workload.New(r, workload.Config{
+			Rate:       10,
+			Operations: []workload.Operation{{Name: "read"}},
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		emitted := 0
+		err := w.Run(ctx, func(context.Context, workload.Op) error {
+			emitted++
+			return nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, emitted)
+	})
+}